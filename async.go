@@ -1,49 +1,442 @@
 package dashgram
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AsyncTaskInfo describes an asynchronous task passed to result handlers
+type AsyncTaskInfo struct {
+	Endpoint string
+	Data     any
+}
+
+// WithSuccessHandler sets a function called by the async workers after a task is delivered successfully
+func WithSuccessHandler(fn func(task AsyncTaskInfo)) Option {
+	return func(d *Dashgram) {
+		d.successHandler = fn
+	}
+}
+
+// WithErrorHandler sets a function called by the async workers when a task fails
+func WithErrorHandler(fn func(task AsyncTaskInfo, err error)) Option {
+	return func(d *Dashgram) {
+		d.errorHandler = fn
+	}
+}
+
+// WithAsyncFallbackToSync sends async tasks synchronously, from the calling
+// goroutine, when the queue is full instead of waiting for room. This trades
+// latency for reliability; such tasks are counted in Stats().Fallback. With
+// WithAsyncEventOrdering, the events of a user still wait for room.
+func WithAsyncFallbackToSync() Option {
+	return func(d *Dashgram) {
+		d.fallbackToSync = true
+	}
+}
+
+// handleResult records the outcome of an async task and reports it to the configured handlers
+func (d *Dashgram) handleResult(task asyncTask, err error) {
+	if err != nil {
+		d.stats.failed.Add(1)
+		d.log(LogLevelError, "async request failed", "endpoint", task.endpoint, "error", err)
+	} else {
+		d.stats.delivered.Add(1)
+	}
+	task.reportResult(err)
+
+	defer func() {
+		// A misbehaving handler must not kill the worker
+		recover()
+	}()
+
+	info := AsyncTaskInfo{
+		Endpoint: task.endpoint,
+		Data:     task.data,
+	}
+
+	if err != nil {
+		if d.errorHandler != nil {
+			d.errorHandler(info, err)
+		}
+		return
+	}
+
+	if d.successHandler != nil {
+		d.successHandler(info)
+	}
+}
+
+// WithAsyncTaskTimeout bounds how long the async workers spend on a single
+// task, so tasks enqueued with a context that is never cancelled, such as
+// context.Background(), can't hang on a slow API. The timeout starts when a
+// worker picks the task up and also bounds its retries.
+func WithAsyncTaskTimeout(timeout time.Duration) Option {
+	return func(d *Dashgram) {
+		d.taskTimeout = timeout
+	}
+}
+
+// taskContext returns the context an async task is sent with, cancelled when
+// either the task context or the base context is done, or the task timeout
+// set by WithAsyncTaskTimeout expires
+func (d *Dashgram) taskContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.taskTimeout <= 0 {
+		return d.baseTaskContext(ctx)
+	}
+
+	ctx, cancelTimeout := context.WithTimeout(ctx, d.taskTimeout)
+	ctx, cancel := d.baseTaskContext(ctx)
+	return ctx, func() {
+		cancel()
+		cancelTimeout()
+	}
+}
+
+// baseTaskContext returns ctx, cancelled when the base context is done
+func (d *Dashgram) baseTaskContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.baseCtx.Done() == nil {
+		// The base context can never be cancelled
+		return ctx, func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-d.baseCtx.Done():
+			cancel()
+		case <-stop:
+		}
+	}()
+
+	return ctx, func() {
+		close(stop)
+		cancel()
+	}
+}
 
 func (d *Dashgram) enqueueTask(task asyncTask) {
+	if d.workerCtx.Err() != nil {
+		// Worker is shutting down, task dropped
+		d.dropTask(task, "client closed")
+		return
+	}
+	task.enqueuedAt = d.now()
+
+	// Sending an ordered event from the caller would overtake the user's queued events
+	if d.fallbackToSync && task.orderKey == "" {
+		select {
+		case d.queueFor(task) <- task:
+			d.stats.enqueued.Add(1)
+			d.checkBackpressure()
+		default:
+			// Queue is full, send the task from the caller's goroutine
+			d.stats.fallback.Add(1)
+			err := d.request(task.ctx, task.endpoint, task.data, task.opts...)
+			d.handleResult(task, err)
+		}
+		return
+	}
+
 	select {
-	case d.taskChan <- task:
+	case d.queueFor(task) <- task:
 		// Task enqueued successfully
+		d.stats.enqueued.Add(1)
+		d.checkBackpressure()
 	case <-d.workerCtx.Done():
 		// Worker is shutting down, task dropped
+		d.dropTask(task, "client closed")
 	}
 }
 
-// TrackEventAsync enqueues an event tracking task to be processed asynchronously
-func (d *Dashgram) TrackEventAsyncWithContext(ctx context.Context, event any) {
-	requestData := TrackEventRequest{
-		Origin:  d.Origin,
-		Updates: []any{event},
+// queueFor returns the queue matching the endpoint and priority of the task
+func (d *Dashgram) queueFor(task asyncTask) chan asyncTask {
+	return d.poolFor(task.endpoint).laneFor(task.orderKey).queue(newCallOptions(task.opts).priority)
+}
+
+// tryEnqueueTask enqueues the task without blocking and reports whether it was accepted
+func (d *Dashgram) tryEnqueueTask(task asyncTask) bool {
+	if d.workerCtx.Err() != nil {
+		// Worker is shutting down, task dropped
+		d.dropTask(task, "client closed")
+		return false
 	}
+	task.enqueuedAt = d.now()
 
-	d.enqueueTask(asyncTask{
+	select {
+	case d.queueFor(task) <- task:
+		d.stats.enqueued.Add(1)
+		d.checkBackpressure()
+		return true
+	default:
+		// Queue is full, task dropped
+		d.dropTask(task, "queue full")
+		return false
+	}
+}
+
+// dropTask records a task that could not be enqueued
+func (d *Dashgram) dropTask(task asyncTask, reason string) {
+	d.stats.dropped.Add(1)
+	d.log(LogLevelWarn, "async task dropped", "endpoint", task.endpoint, "reason", reason)
+	task.reportResult(fmt.Errorf("%w: %s", ErrTaskDropped, reason))
+}
+
+// TrackEventAsync enqueues an event tracking task to be processed asynchronously.
+// Nil, empty and unserializable events are rejected with a ValidationError
+// instead of being enqueued.
+func (d *Dashgram) TrackEventAsyncWithContext(ctx context.Context, event any, opts ...CallOption) error {
+	return d.trackEventAsync(ctx, event, opts, nil)
+}
+
+// trackEventAsync validates, samples, shards and debounces an event before
+// enqueueing it. The callback, if not nil, is called exactly once: with the
+// ValidationError of an invalid event, nil if the event is skipped or held,
+// and the result of the task otherwise.
+func (d *Dashgram) trackEventAsync(ctx context.Context, event any, opts []CallOption, callback func(error)) error {
+	done := func(err error) error {
+		if callback != nil {
+			callback(err)
+		}
+		return err
+	}
+
+	if err := validateEvent(event); err != nil {
+		return done(err)
+	}
+	if d.skipEvent(ctx, event, opts) {
+		return done(nil)
+	}
+	opts = d.shardOptions(event, opts)
+	if d.debounce(ctx, event, opts) {
+		return done(nil)
+	}
+	return d.enqueueEvent(ctx, event, opts, callback)
+}
+
+// enqueueEvent enqueues a validated event, once sampled and debounced. The
+// callback, if not nil, receives the result of the task, or nil if the event
+// is filtered and the error if it can't be marshaled.
+func (d *Dashgram) enqueueEvent(ctx context.Context, event any, opts []CallOption, callback func(error)) error {
+	task := asyncTask{
 		ctx:      ctx,
 		endpoint: "track",
+		opts:     opts,
+		callback: callback,
+	}
+	if d.orderedAsync {
+		task.orderKey = d.userIDOf(event)
+	}
+
+	event, ok := d.runBeforeSend("track", event)
+	if !ok {
+		task.reportResult(nil)
+		return nil
+	}
+
+	update, err := marshalUpdate(d.prepareUpdate(event))
+	if err != nil {
+		task.reportResult(err)
+		return err
+	}
+
+	task.data = TrackEventRequest{
+		Origin:  newCallOptions(opts).originOr(d.Origin),
+		Updates: []any{update},
+	}
+	d.enqueueTask(task)
+	return nil
+}
+
+// TrackEventWithCallbackWithContext tracks an event and calls cb exactly once
+// with its result: with WithUseAsync, the event is enqueued and cb is called
+// from the worker once the request returned, otherwise the event is sent
+// right away and cb is called before TrackEventWithCallbackWithContext
+// returns. cb may be nil. Invalid events pass their ValidationError to cb,
+// and events that are sampled out, suppressed, filtered or held by
+// WithDebounce pass nil.
+func (d *Dashgram) TrackEventWithCallbackWithContext(ctx context.Context, event any, cb func(err error), opts ...CallOption) {
+	if cb == nil {
+		cb = func(error) {}
+	}
+	if !d.useAsync {
+		cb(d.TrackEventWithContext(ctx, event, opts...))
+		return
+	}
+
+	d.trackEventAsync(ctx, event, opts, cb)
+}
+
+// TrackEventJSONAsyncWithContext enqueues a pre-serialized JSON event. The
+// bytes are copied, so the caller may reuse the slice once it returns.
+func (d *Dashgram) TrackEventJSONAsyncWithContext(ctx context.Context, jsonBytes []byte, opts ...CallOption) error {
+	if err := validateEventJSON(jsonBytes); err != nil {
+		return err
+	}
+
+	return d.TrackEventAsyncWithContext(ctx, json.RawMessage(jsonBytes), opts...)
+}
+
+// TrackEventWithUserIDAsyncAndContext enqueues a copy of the event with its "user_id" set to userID
+func (d *Dashgram) TrackEventWithUserIDAsyncAndContext(ctx context.Context, userID int64, event map[string]any, opts ...CallOption) error {
+	if event == nil {
+		return errNilEvent()
+	}
+
+	return d.TrackEventAsyncWithContext(ctx, withUserID(userID, event), opts...)
+}
+
+// TrackGroupEventAsyncWithContext enqueues an event attributed to a group.
+// Invalid arguments are reported instead of being enqueued.
+func (d *Dashgram) TrackGroupEventAsyncWithContext(ctx context.Context, groupID int, event any, opts ...CallOption) error {
+	requestData, ok, err := d.newGroupEventRequest(ctx, groupID, event, opts)
+	if err != nil || !ok {
+		return err
+	}
+	if requestData.Updates[0], err = marshalUpdate(requestData.Updates[0]); err != nil {
+		return err
+	}
+
+	d.enqueueTask(asyncTask{
+		ctx:      ctx,
+		endpoint: "group_track",
 		data:     requestData,
+		opts:     opts,
 	})
+	return nil
 }
 
 // InvitedByAsync enqueues an invitation tracking task to be processed asynchronously
-func (d *Dashgram) InvitedByAsyncWithContext(ctx context.Context, userID int, invitedBy int) {
-	requestData := InvitedByRequest{
-		UserID:    userID,
-		InvitedBy: invitedBy,
-		Origin:    d.Origin,
+func (d *Dashgram) InvitedByAsyncWithContext(ctx context.Context, userID int64, invitedBy int64, opts ...CallOption) {
+	d.InvitedByAsyncWithOptions(ctx, userID, invitedBy, InviteOptions{}, opts...)
+}
+
+// InvitedByAsyncWithOptions enqueues an invitation tracking task with optional attributes
+func (d *Dashgram) InvitedByAsyncWithOptions(ctx context.Context, userID int64, invitedBy int64, inviteOpts InviteOptions, opts ...CallOption) {
+	d.invitedByAsync(ctx, userID, invitedBy, inviteOpts, opts, nil)
+}
+
+// invitedByAsync enqueues an invitation tracking task. The result channel, if
+// not nil, receives nil if the invitation is suppressed or filtered, and the
+// result of the task otherwise.
+func (d *Dashgram) invitedByAsync(ctx context.Context, userID int64, invitedBy int64, inviteOpts InviteOptions, opts []CallOption, result chan<- error) {
+	task := asyncTask{ctx: ctx, endpoint: "invited_by", opts: opts, result: result}
+	if d.suppressUsers(userID, invitedBy) {
+		task.reportResult(nil)
+		return
+	}
+
+	requestData, ok := d.runBeforeSend("invited_by", d.newInvitedByRequest(userID, invitedBy, inviteOpts, newCallOptions(opts).originOr(d.Origin)))
+	if !ok {
+		task.reportResult(nil)
+		return
+	}
+
+	task.data = requestData
+	d.enqueueTask(task)
+}
+
+// InvitedByAsyncWithResultWithContext enqueues an invitation tracking task
+// like InvitedByAsyncWithContext and returns a channel receiving the error of
+// its request, or nil, once a worker sent it. The channel receives exactly one
+// value and is then closed. It has a buffer of one so callers may ignore it:
+// on an unbuffered channel, the worker would block until the value is
+// received. Tasks dropped because the client is closed, including tasks still
+// queued when Close stops the workers, receive an error wrapping
+// ErrTaskDropped, and suppressed or filtered invitations nil.
+func (d *Dashgram) InvitedByAsyncWithResultWithContext(ctx context.Context, userID int64, invitedBy int64, opts ...CallOption) <-chan error {
+	result := make(chan error, 1)
+	d.invitedByAsync(ctx, userID, invitedBy, InviteOptions{}, opts, result)
+	return result
+}
+
+// SetUserPropertiesAsyncWithContext validates the properties and enqueues them to be sent asynchronously
+func (d *Dashgram) SetUserPropertiesAsyncWithContext(ctx context.Context, userID int64, props map[string]any) error {
+	if err := validateUserProperties(userID, props); err != nil {
+		return err
+	}
+	if d.suppressUsers(userID) {
+		return nil
+	}
+
+	requestData, ok := d.runBeforeSend("user_properties", UserPropertiesRequest{
+		UserID:     userID,
+		Properties: props,
+		Origin:     d.Origin,
+	})
+	if !ok {
+		return nil
+	}
+
+	d.enqueueTask(asyncTask{
+		ctx:      ctx,
+		endpoint: "user_properties",
+		data:     requestData,
+	})
+	return nil
+}
+
+// AliasUserAsyncWithContext validates the arguments and enqueues the identity merge to be sent asynchronously
+func (d *Dashgram) AliasUserAsyncWithContext(ctx context.Context, anonymousID string, userID int64) error {
+	if err := validateAlias(anonymousID, userID); err != nil {
+		return err
+	}
+	if d.suppressUsers(userID) {
+		return nil
+	}
+
+	requestData, ok := d.runBeforeSend("alias", AliasRequest{
+		AnonymousID: anonymousID,
+		UserID:      userID,
+		Origin:      d.Origin,
+	})
+	if !ok {
+		return nil
 	}
 
 	d.enqueueTask(asyncTask{
 		ctx:      ctx,
-		endpoint: "invited_by",
+		endpoint: "alias",
 		data:     requestData,
 	})
+	return nil
+}
+
+func (d *Dashgram) TrackEventAsync(event any, opts ...CallOption) error {
+	return d.TrackEventAsyncWithContext(context.Background(), event, opts...)
+}
+
+func (d *Dashgram) TrackEventWithCallback(event any, cb func(err error), opts ...CallOption) {
+	d.TrackEventWithCallbackWithContext(context.Background(), event, cb, opts...)
+}
+
+func (d *Dashgram) TrackEventJSONAsync(jsonBytes []byte, opts ...CallOption) error {
+	return d.TrackEventJSONAsyncWithContext(context.Background(), jsonBytes, opts...)
+}
+
+func (d *Dashgram) TrackEventWithUserIDAsync(userID int64, event map[string]any, opts ...CallOption) error {
+	return d.TrackEventWithUserIDAsyncAndContext(context.Background(), userID, event, opts...)
+}
+
+func (d *Dashgram) TrackGroupEventAsync(groupID int, event any, opts ...CallOption) error {
+	return d.TrackGroupEventAsyncWithContext(context.Background(), groupID, event, opts...)
+}
+
+func (d *Dashgram) InvitedByAsync(userID int64, invitedBy int64, opts ...CallOption) {
+	d.InvitedByAsyncWithContext(context.Background(), userID, invitedBy, opts...)
+}
+
+func (d *Dashgram) InvitedByAsyncWithResult(userID int64, invitedBy int64, opts ...CallOption) <-chan error {
+	return d.InvitedByAsyncWithResultWithContext(context.Background(), userID, invitedBy, opts...)
 }
 
-func (d *Dashgram) TrackEventAsync(event any) {
-	d.TrackEventAsyncWithContext(context.Background(), event)
+func (d *Dashgram) SetUserPropertiesAsync(userID int64, props map[string]any) error {
+	return d.SetUserPropertiesAsyncWithContext(context.Background(), userID, props)
 }
 
-func (d *Dashgram) InvitedByAsync(userID int, invitedBy int) {
-	d.InvitedByAsyncWithContext(context.Background(), userID, invitedBy)
+func (d *Dashgram) AliasUserAsync(anonymousID string, userID int64) error {
+	return d.AliasUserAsyncWithContext(context.Background(), anonymousID, userID)
 }
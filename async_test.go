@@ -2,6 +2,8 @@ package dashgram
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -70,6 +72,32 @@ func TestDashgram_TrackEventAsync(t *testing.T) {
 	}
 }
 
+func TestDashgram_TrackGroupEventAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	if err := d.TrackGroupEventAsync(0, map[string]any{"action": "message"}); err == nil {
+		t.Errorf("expected validation error, got nil")
+	}
+
+	if err := d.TrackGroupEventAsync(42, map[string]any{"action": "message"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !helper.WaitForRequests(1, time.Second) {
+		t.Fatal("expected request to be made, but none was")
+	}
+
+	if path := helper.LastRequest().URL.Path; !strings.HasSuffix(path, "/group_track") {
+		t.Errorf("expected endpoint '/group_track', got %s", path)
+	}
+	if count := len(helper.RecordedRequests()); count != 1 {
+		t.Errorf("expected 1 request, got %d", count)
+	}
+}
+
 func TestDashgram_TrackEventAsyncWithContext(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -137,8 +165,8 @@ func TestDashgram_TrackEventAsyncWithContext(t *testing.T) {
 func TestDashgram_InvitedByAsync(t *testing.T) {
 	tests := []struct {
 		name          string
-		userID        int
-		invitedBy     int
+		userID        int64
+		invitedBy     int64
 		mockResponse  *http.Response
 		mockError     error
 		expectedError string
@@ -200,8 +228,8 @@ func TestDashgram_InvitedByAsyncWithContext(t *testing.T) {
 	tests := []struct {
 		name          string
 		ctx           context.Context
-		userID        int
-		invitedBy     int
+		userID        int64
+		invitedBy     int64
 		mockResponse  *http.Response
 		mockError     error
 		expectedError string
@@ -263,6 +291,72 @@ func TestDashgram_InvitedByAsyncWithContext(t *testing.T) {
 	}
 }
 
+func TestDashgram_InvitedByAsyncWithResult(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		closed        bool
+		expectedError error
+	}{
+		{
+			name:   "delivered",
+			status: http.StatusOK,
+			body:   `{"status":"success","details":"ok"}`,
+		},
+		{
+			name:          "request failed",
+			status:        http.StatusForbidden,
+			body:          `{"status":"error","details":"forbidden"}`,
+			expectedError: &ForbiddenError{},
+		},
+		{
+			name:          "client closed",
+			closed:        true,
+			expectedError: ErrTaskDropped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(tt.status, tt.body)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+			if tt.closed {
+				d.Close()
+			}
+
+			result := d.InvitedByAsyncWithResult(55555, 66666)
+
+			select {
+			case err := <-result:
+				switch expected := tt.expectedError.(type) {
+				case nil:
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+				case *ForbiddenError:
+					if !errors.As(err, &expected) {
+						t.Errorf("expected ForbiddenError, got %v", err)
+					}
+				default:
+					if !errors.Is(err, expected) {
+						t.Errorf("expected %v, got %v", expected, err)
+					}
+				}
+			case <-time.After(time.Second):
+				t.Fatal("expected a result")
+			}
+
+			if _, ok := <-result; ok {
+				t.Error("expected the channel to be closed after the result")
+			}
+		})
+	}
+}
+
 func TestDashgram_enqueueTask(t *testing.T) {
 	d := New(123, "test-key", WithUseAsync())
 	defer d.Close()
@@ -333,3 +427,498 @@ func TestDashgram_MultipleAsyncWorkers(t *testing.T) {
 	}
 	mu.Unlock()
 }
+
+func TestDashgram_ResultHandlers(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(400, `{"status":"error","details":"bad request"}`)
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(500, `{"status":"error","details":"internal error"}`)
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+
+	var mu sync.Mutex
+	var succeeded []AsyncTaskInfo
+	var failed []error
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithSuccessHandler(func(task AsyncTaskInfo) {
+			mu.Lock()
+			succeeded = append(succeeded, task)
+			mu.Unlock()
+		}),
+		WithErrorHandler(func(task AsyncTaskInfo, err error) {
+			mu.Lock()
+			failed = append(failed, err)
+			mu.Unlock()
+		}),
+	)
+	defer d.Close()
+
+	for i := 0; i < 5; i++ {
+		d.TrackEventAsync(map[string]any{"action": "purchase", "index": i})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		done := len(succeeded)+len(failed) == 5
+		mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(succeeded) != 3 {
+		t.Errorf("expected success handler to fire 3 times, got %d", len(succeeded))
+	}
+	if len(failed) != 2 {
+		t.Errorf("expected error handler to fire 2 times, got %d", len(failed))
+	}
+	for _, task := range succeeded {
+		if task.Endpoint != "track" {
+			t.Errorf("expected endpoint 'track', got '%s'", task.Endpoint)
+		}
+		if _, ok := task.Data.(TrackEventRequest); !ok {
+			t.Errorf("expected TrackEventRequest data, got %T", task.Data)
+		}
+	}
+	for _, err := range failed {
+		if _, ok := err.(*DashgramAPIError); !ok {
+			t.Errorf("expected DashgramAPIError, got %T", err)
+		}
+	}
+}
+
+func TestDashgram_ResultHandlerPanic(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithSuccessHandler(func(task AsyncTaskInfo) {
+			panic("handler failure")
+		}),
+	)
+	defer d.Close()
+
+	d.TrackEventAsync(map[string]any{"action": "first"})
+	d.TrackEventAsync(map[string]any{"action": "second"})
+
+	// The worker must survive the panicking handler and process the second task
+	if !helper.WaitForRequests(2, time.Second) {
+		t.Errorf("expected worker to keep processing after a handler panic")
+	}
+}
+
+func TestDashgram_SetUserPropertiesAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithUseAsync())
+	defer d.Close()
+
+	if err := d.SetUserPropertiesAsync(0, map[string]any{"plan": "premium"}); err == nil {
+		t.Errorf("expected validation error for non-positive user ID")
+	}
+	if err := d.SetUserPropertiesAsync(12345, nil); err == nil {
+		t.Errorf("expected validation error for empty properties")
+	}
+
+	if err := d.SetUserPropertiesAsync(12345, map[string]any{"plan": "premium"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if !helper.WaitForRequests(1, time.Second) {
+		t.Errorf("expected request to be made")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	helper.mu.Lock()
+	if helper.RequestCount != 1 {
+		t.Errorf("expected exactly 1 request, got %d", helper.RequestCount)
+	}
+	helper.mu.Unlock()
+}
+
+func TestDashgram_TrackEventWithUserIDAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	if err := d.TrackEventWithUserIDAsync(12345, nil); !errors.Is(err, ErrNilEvent) {
+		t.Errorf("expected ErrNilEvent, got %v", err)
+	}
+
+	if err := d.TrackEventWithUserIDAsync(12345, map[string]any{"action": "click"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if !helper.WaitForRequests(1, time.Second) {
+		t.Errorf("expected event to be tracked")
+	}
+}
+
+func TestDashgram_TrackEventAsyncNil(t *testing.T) {
+	helper := NewTestHelper()
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+
+	var nilMap map[string]any
+	if err := d.TrackEventAsync(nil); !errors.Is(err, ErrNilEvent) {
+		t.Errorf("expected ErrNilEvent, got %v", err)
+	}
+	if err := d.TrackEventAsync(nilMap); !errors.Is(err, ErrNilEvent) {
+		t.Errorf("expected ErrNilEvent, got %v", err)
+	}
+
+	d.Close()
+
+	if stats := d.Stats(); stats.Enqueued != 0 {
+		t.Errorf("expected no enqueued tasks, got %d", stats.Enqueued)
+	}
+	if helper.RequestCount != 0 {
+		t.Errorf("expected no HTTP requests, got %d", helper.RequestCount)
+	}
+}
+
+func TestDashgram_WithBaseContext(t *testing.T) {
+	started := make(chan struct{})
+	requestErr := make(chan error, 1)
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			close(started)
+			<-req.Context().Done()
+			requestErr <- req.Context().Err()
+			return nil, req.Context().Err()
+		},
+	}
+
+	baseCtx, cancel := context.WithCancel(context.Background())
+	d := New(123, "test-key", WithHTTPClient(mockClient), WithBaseContext(baseCtx))
+	defer d.Close()
+
+	// The task itself is never cancelled
+	if err := d.TrackEventAsyncWithContext(context.Background(), map[string]any{"action": "click"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("expected request to be made, but none was")
+	}
+
+	cancel()
+
+	select {
+	case err := <-requestErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected in-flight request to be cancelled with the base context")
+	}
+}
+
+func TestDashgram_TrackEventJSONAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	// Hold the worker so the caller can reuse the slices before they're sent
+	release := make(chan struct{})
+	mockClient := &mockHTTPClient{doFunc: func(req *http.Request) (*http.Response, error) {
+		<-release
+		return helper.MockHTTPClient().Do(req)
+	}}
+
+	d := New(123, "test-key", WithHTTPClient(mockClient), WithNumWorkers(1))
+	defer d.Close()
+
+	if err := d.TrackEventJSONAsync([]byte(`not json`)); err == nil {
+		t.Errorf("expected validation error, got nil")
+	}
+
+	jsonBytes := []byte(`{"action":"click"}`)
+	if err := d.TrackEventJSONAsync(jsonBytes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	copy(jsonBytes, `{"action":"XXXXX"}`)
+
+	raw := json.RawMessage(`{"action":"share"}`)
+	if err := d.TrackEventAsync(raw); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	copy(raw, `{"action":"XXXXX"}`)
+
+	close(release)
+	if !helper.WaitForRequests(2, time.Second) {
+		t.Fatal("expected 2 requests to be made")
+	}
+
+	expected := []string{
+		`{"updates":[{"action":"click"}],"origin":"Go + Dashgram SDK"}`,
+		`{"updates":[{"action":"share"}],"origin":"Go + Dashgram SDK"}`,
+	}
+	for i, req := range helper.RecordedRequests() {
+		if body := string(req.Body); body != expected[i] {
+			t.Errorf("request %d: expected body '%s', got '%s'", i, expected[i], body)
+		}
+	}
+}
+
+func TestDashgram_WithAsyncFallbackToSync(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			requests++
+			first := requests == 1
+			mu.Unlock()
+
+			if first {
+				// Block the only worker on the first task
+				started <- struct{}{}
+				<-release
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	d := New(123, "test-key", WithHTTPClient(mockClient), WithQueueSize(1), WithAsyncFallbackToSync())
+	defer d.Close()
+	defer close(release)
+
+	d.TrackEventAsync(map[string]any{"action": "picked_up"})
+	<-started
+	d.TrackEventAsync(map[string]any{"action": "queued"})
+
+	// The queue is full, so this one is sent before the call returns
+	d.TrackEventAsync(map[string]any{"action": "fallback"})
+
+	mu.Lock()
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+	mu.Unlock()
+
+	stats := d.Stats()
+	if stats.Fallback != 1 {
+		t.Errorf("expected 1 fallback, got %d", stats.Fallback)
+	}
+	if stats.Delivered != 1 {
+		t.Errorf("expected 1 delivered task, got %d", stats.Delivered)
+	}
+	if stats.Dropped != 0 {
+		t.Errorf("expected no dropped tasks, got %d", stats.Dropped)
+	}
+}
+
+func TestDashgram_AliasUserAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithNumWorkers(1), WithUseAsync())
+
+	if err := d.AliasUserAsync("", 12345); err == nil {
+		t.Errorf("expected validation error for empty anonymous ID")
+	}
+	if err := d.AliasUserAsync("visitor-5f0c", 12345); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// With WithUseAsync, AliasUser enqueues too
+	if err := d.AliasUser(context.Background(), "visitor-9a1b", 12345); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	d.Close()
+
+	if enqueued := d.Stats().Enqueued; enqueued != 2 {
+		t.Errorf("expected 2 enqueued tasks, got %d", enqueued)
+	}
+	expected := `{"anonymous_id":"visitor-9a1b","user_id":12345,"origin":"Go + Dashgram SDK"}`
+	if body := string(helper.LastRequest().Body); body != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+}
+
+func TestDashgram_WithAsyncTaskTimeout(t *testing.T) {
+	t.Run("hanging task times out", func(t *testing.T) {
+		mockClient := &mockHTTPClient{
+			doFunc: func(req *http.Request) (*http.Response, error) {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			},
+		}
+
+		failed := make(chan error, 1)
+		d := New(123, "test-key",
+			WithHTTPClient(mockClient),
+			WithAsyncTaskTimeout(20*time.Millisecond),
+			WithErrorHandler(func(task AsyncTaskInfo, err error) { failed <- err }),
+		)
+		defer d.Close()
+
+		// The task itself is never cancelled
+		if err := d.TrackEventAsyncWithContext(context.Background(), map[string]any{"action": "click"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		select {
+		case err := <-failed:
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected context.DeadlineExceeded, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the task to time out")
+		}
+	})
+
+	t.Run("context cancelled once the task is done", func(t *testing.T) {
+		contexts := make(chan context.Context, 1)
+		mockClient := &mockHTTPClient{
+			doFunc: func(req *http.Request) (*http.Response, error) {
+				contexts <- req.Context()
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+				}, nil
+			},
+		}
+
+		d := New(123, "test-key", WithHTTPClient(mockClient), WithAsyncTaskTimeout(10*time.Second))
+		d.TrackEventAsync(map[string]any{"action": "click"})
+		d.Close()
+
+		ctx := <-contexts
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("expected the task context to have a deadline")
+		}
+		if err := ctx.Err(); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the task context to be cancelled, got %v", err)
+		}
+	})
+}
+
+func TestDashgram_TrackEventWithCallback(t *testing.T) {
+	tests := []struct {
+		name          string
+		async         bool
+		event         any
+		status        int
+		body          string
+		expectedError bool
+	}{
+		{
+			name:   "async delivered",
+			async:  true,
+			event:  map[string]any{"action": "click"},
+			status: http.StatusOK,
+			body:   `{"status":"success","details":"ok"}`,
+		},
+		{
+			name:          "async failed",
+			async:         true,
+			event:         map[string]any{"action": "click"},
+			status:        http.StatusForbidden,
+			body:          `{"status":"error","details":"forbidden"}`,
+			expectedError: true,
+		},
+		{
+			name:          "async invalid event",
+			async:         true,
+			event:         nil,
+			expectedError: true,
+		},
+		{
+			name:   "sync delivered",
+			event:  map[string]any{"action": "click"},
+			status: http.StatusOK,
+			body:   `{"status":"success","details":"ok"}`,
+		},
+		{
+			name:          "sync failed",
+			event:         map[string]any{"action": "click"},
+			status:        http.StatusForbidden,
+			body:          `{"status":"error","details":"forbidden"}`,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const events = 3
+			helper := NewTestHelper()
+			for i := 0; i < events; i++ {
+				helper.AddResponse(tt.status, tt.body)
+			}
+
+			options := []Option{WithHTTPClient(helper.MockHTTPClient())}
+			if tt.async {
+				options = append(options, WithUseAsync())
+			}
+			d := New(123, "test-key", options...)
+
+			var mu sync.Mutex
+			var errs []error
+			for i := 0; i < events; i++ {
+				d.TrackEventWithCallback(tt.event, func(err error) {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				})
+			}
+			d.Close()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(errs) != events {
+				t.Fatalf("expected %d callbacks, got %d", events, len(errs))
+			}
+			for _, err := range errs {
+				if (err != nil) != tt.expectedError {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackEventWithCallbackNil(t *testing.T) {
+	for _, async := range []bool{true, false} {
+		t.Run(fmt.Sprintf("async %v", async), func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			options := []Option{WithHTTPClient(helper.MockHTTPClient())}
+			if async {
+				options = append(options, WithUseAsync())
+			}
+			d := New(123, "test-key", options...)
+
+			d.TrackEventWithCallback(map[string]any{"action": "click"}, nil)
+			d.TrackEventWithCallback(nil, nil)
+			d.Close()
+
+			if helper.RequestCount != 1 {
+				t.Errorf("expected 1 request, got %d", helper.RequestCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package dashgram
+
+// backpressureHysteresis is how far below the threshold the queue utilization
+// must fall before recovery is reported, so a queue hovering around the
+// threshold doesn't make the callback flap
+const backpressureHysteresis = 0.1
+
+// WithBackpressureCallback calls fn when the utilization of the fullest async
+// queue rises above threshold (e.g. 0.8), and again once it has recovered
+// below it, so load can be shed upstream before tasks block or are dropped.
+// fn receives the depth and capacity of the fullest queue at the crossing.
+func WithBackpressureCallback(threshold float64, fn func(depth, capacity int)) Option {
+	return func(d *Dashgram) {
+		d.backpressureThreshold = threshold
+		d.backpressureCallback = fn
+	}
+}
+
+// queueDepth returns the length and capacity of the fullest queue
+func (d *Dashgram) queueDepth() (depth, capacity int) {
+	var check func(pool *workerPool)
+	check = func(pool *workerPool) {
+		for _, lane := range pool.lanes {
+			check(lane)
+		}
+		if len(pool.lanes) > 0 {
+			return
+		}
+		for _, queue := range pool.queues {
+			if len(queue) >= depth {
+				depth, capacity = len(queue), cap(queue)
+			}
+		}
+	}
+
+	check(d.pool)
+	for _, pool := range d.endpointPools {
+		check(pool)
+	}
+	return depth, capacity
+}
+
+// checkBackpressure reports the queue crossing the backpressure threshold
+func (d *Dashgram) checkBackpressure() {
+	if d.backpressureCallback == nil {
+		return
+	}
+
+	depth, capacity := d.queueDepth()
+	if capacity == 0 {
+		return
+	}
+
+	overloaded := d.backpressureOverloaded.Load()
+	if overloaded == d.crossesBackpressure(depth, capacity, overloaded) {
+		return
+	}
+
+	// Serialize the crossings so they're reported in order
+	d.backpressureMu.Lock()
+	defer d.backpressureMu.Unlock()
+
+	depth, capacity = d.queueDepth()
+	overloaded = d.backpressureOverloaded.Load()
+	if next := d.crossesBackpressure(depth, capacity, overloaded); next != overloaded {
+		d.backpressureOverloaded.Store(next)
+		d.backpressureCallback(depth, capacity)
+	}
+}
+
+// crossesBackpressure returns the backpressure state for the given queue utilization
+func (d *Dashgram) crossesBackpressure(depth, capacity int, overloaded bool) bool {
+	utilization := float64(depth) / float64(capacity)
+	if overloaded {
+		return utilization > d.backpressureThreshold-backpressureHysteresis
+	}
+	return utilization > d.backpressureThreshold
+}
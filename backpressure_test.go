@@ -0,0 +1,110 @@
+package dashgram
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WithBackpressureCallback(t *testing.T) {
+	type crossing struct {
+		depth    int
+		capacity int
+	}
+
+	var mu sync.Mutex
+	var crossings []crossing
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var once sync.Once
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			once.Do(func() {
+				// Block the only worker on the first task
+				started <- struct{}{}
+				<-release
+			})
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	d := New(123, "test-key",
+		WithHTTPClient(mockClient),
+		WithQueueSize(10),
+		WithBackpressureCallback(0.8, func(depth, capacity int) {
+			mu.Lock()
+			defer mu.Unlock()
+			crossings = append(crossings, crossing{depth: depth, capacity: capacity})
+		}),
+	)
+	defer d.Close()
+
+	d.TrackEventAsync(map[string]any{"action": "blocking"})
+	<-started
+
+	// 8 queued tasks is exactly the threshold, the 9th crosses it
+	for i := 0; i < 8; i++ {
+		d.TrackEventAsync(map[string]any{"action": "queued"})
+	}
+	mu.Lock()
+	if len(crossings) != 0 {
+		t.Errorf("expected no callback at the threshold, got %v", crossings)
+	}
+	mu.Unlock()
+
+	d.TrackEventAsync(map[string]any{"action": "queued"})
+	d.TrackEventAsync(map[string]any{"action": "queued"})
+
+	mu.Lock()
+	if len(crossings) != 1 || crossings[0] != (crossing{depth: 9, capacity: 10}) {
+		t.Errorf("expected a single rising crossing at depth 9, got %v", crossings)
+	}
+	mu.Unlock()
+
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && d.QueueLength() != 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(crossings) != 2 {
+		t.Fatalf("expected a rising and a recovering crossing, got %v", crossings)
+	}
+	if recovered := crossings[1]; float64(recovered.depth)/float64(recovered.capacity) > 0.7 {
+		t.Errorf("expected recovery below the threshold, got %v", recovered)
+	}
+}
+
+func TestDashgram_crossesBackpressure(t *testing.T) {
+	d := &Dashgram{backpressureThreshold: 0.8}
+
+	tests := []struct {
+		name       string
+		depth      int
+		overloaded bool
+		expected   bool
+	}{
+		{name: "below threshold", depth: 5, overloaded: false, expected: false},
+		{name: "rises above threshold", depth: 9, overloaded: false, expected: true},
+		{name: "stays overloaded within hysteresis", depth: 8, overloaded: true, expected: true},
+		{name: "recovers below hysteresis", depth: 7, overloaded: true, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.crossesBackpressure(tt.depth, 10, tt.overloaded); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package dashgram
+
+import (
+	"context"
+	"sync"
+)
+
+// InvitedByBatchWithContext sends each invitation concurrently, with at most
+// batchConcurrency requests in flight. The returned slice holds the error of
+// every request at the index of its input.
+func (d *Dashgram) InvitedByBatchWithContext(ctx context.Context, requests []InvitedByRequest) []error {
+	errs := make([]error, len(requests))
+	sem := make(chan struct{}, d.batchConcurrency)
+
+	var wg sync.WaitGroup
+	for i, requestData := range requests {
+		if requestData.Origin == "" {
+			requestData.Origin = d.Origin
+		}
+		if d.suppressUsers(requestData.UserID, requestData.InvitedBy) {
+			continue
+		}
+		payload, ok := d.runBeforeSend("invited_by", requestData)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, requestData any) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = d.request(ctx, "invited_by", requestData)
+		}(i, payload)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func (d *Dashgram) InvitedByBatch(requests []InvitedByRequest) []error {
+	return d.InvitedByBatchWithContext(context.Background(), requests)
+}
+
+// defaultInviteImportChunkSize is the number of invitations sent per bulk request
+const defaultInviteImportChunkSize = 500
+
+// InvitePair is an invitation imported with ImportInvitedBy
+type InvitePair struct {
+	UserID    int64 `json:"user_id"`
+	InvitedBy int64 `json:"invited_by"`
+}
+
+// InviteImportOptions configures ImportInvitedBy
+type InviteImportOptions struct {
+	// ChunkSize is the number of pairs sent per request, 500 by default
+	ChunkSize int
+	// Sequential sends the pairs of each chunk one by one to the invited_by
+	// endpoint instead of using the bulk endpoint
+	Sequential bool
+	// Progress, if set, is called after each chunk
+	Progress func(progress ImportProgress)
+}
+
+// ImportProgress reports the outcome of a chunk of an import
+type ImportProgress struct {
+	// Chunk is the index of the chunk, out of Chunks
+	Chunk  int
+	Chunks int
+	// Done is the number of pairs processed so far, out of Total
+	Done  int
+	Total int
+	// Err is the error of the chunk, if it failed
+	Err error
+}
+
+// ImportInvitedBy backfills invitations, e.g. when migrating a referral graph.
+// The pairs are split into chunks sent one after the other; chunks that fail
+// don't stop the import and are described by the returned ImportError, so
+// they can be imported again. If ctx is done, the import stops and the error
+// of ctx is returned.
+func (d *Dashgram) ImportInvitedBy(ctx context.Context, pairs []InvitePair, opts InviteImportOptions) error {
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultInviteImportChunkSize
+	}
+	chunks := (len(pairs) + chunkSize - 1) / chunkSize
+
+	var failed []*ImportChunkError
+	for chunk := 0; chunk < chunks; chunk++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		start := chunk * chunkSize
+		end := start + chunkSize
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+
+		var err error
+		if opts.Sequential {
+			err = d.importInvitesSequentially(ctx, pairs[start:end])
+		} else if invites := d.filterSuppressedInvites(pairs[start:end]); len(invites) > 0 {
+			err = d.request(ctx, "invited_by/bulk", InvitedByBulkRequest{
+				Invites: invites,
+				Origin:  d.Origin,
+			})
+		}
+		if err != nil {
+			failed = append(failed, &ImportChunkError{Chunk: chunk, Start: start, End: end, Err: err})
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(ImportProgress{
+				Chunk:  chunk,
+				Chunks: chunks,
+				Done:   end,
+				Total:  len(pairs),
+				Err:    err,
+			})
+		}
+	}
+
+	if len(failed) > 0 {
+		return &ImportError{Chunks: chunks, Failed: failed}
+	}
+	return nil
+}
+
+// importInvitesSequentially sends the pairs one by one and returns the first error
+func (d *Dashgram) importInvitesSequentially(ctx context.Context, pairs []InvitePair) error {
+	var firstErr error
+	for _, pair := range d.filterSuppressedInvites(pairs) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		requestData := d.newInvitedByRequest(pair.UserID, pair.InvitedBy, InviteOptions{}, d.Origin)
+		if err := d.request(ctx, "invited_by", requestData); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// filterSuppressedInvites returns the pairs whose users didn't opt out, see WithOptOut
+func (d *Dashgram) filterSuppressedInvites(pairs []InvitePair) []InvitePair {
+	if d.optOut == nil {
+		return pairs
+	}
+
+	filtered := make([]InvitePair, 0, len(pairs))
+	for _, pair := range pairs {
+		if !d.suppressUsers(pair.UserID, pair.InvitedBy) {
+			filtered = append(filtered, pair)
+		}
+	}
+	return filtered
+}
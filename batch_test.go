@@ -0,0 +1,460 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDashgram_InvitedByBatch(t *testing.T) {
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			var requestData InvitedByRequest
+			if err := json.NewDecoder(req.Body).Decode(&requestData); err != nil {
+				t.Errorf("failed to decode request body: %v", err)
+			}
+
+			// Fail every request for odd user IDs
+			if requestData.UserID%2 == 1 {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"status":"error","details":"user %d"}`, requestData.UserID))),
+				}, nil
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	d := New(123, "test-key", WithHTTPClient(mockClient), WithBatchConcurrency(3))
+	defer d.Close()
+
+	requests := make([]InvitedByRequest, 20)
+	for i := range requests {
+		requests[i] = InvitedByRequest{UserID: int64(i), InvitedBy: 1000}
+	}
+
+	errs := d.InvitedByBatch(requests)
+
+	if len(errs) != len(requests) {
+		t.Fatalf("expected %d errors, got %d", len(requests), len(errs))
+	}
+	for i, err := range errs {
+		if i%2 == 0 {
+			if err != nil {
+				t.Errorf("expected nil error at index %d, got %v", i, err)
+			}
+			continue
+		}
+
+		expected := fmt.Sprintf("dashgram API error (status: 400): user %d", i)
+		if err == nil {
+			t.Errorf("expected error at index %d, got nil", i)
+		} else if err.Error() != expected {
+			t.Errorf("expected error '%s' at index %d, got '%s'", expected, i, err.Error())
+		}
+	}
+}
+
+func TestDashgram_InvitedByBatchConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []Option
+		expectedMax int
+	}{
+		{name: "explicit limit", options: []Option{WithBatchConcurrency(2)}, expectedMax: 2},
+		{name: "defaults to the number of workers", options: []Option{WithNumWorkers(3)}, expectedMax: 3},
+		{name: "explicit limit overrides workers", options: []Option{WithNumWorkers(3), WithBatchConcurrency(1)}, expectedMax: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var active, maxActive int
+
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					mu.Lock()
+					active++
+					if active > maxActive {
+						maxActive = active
+					}
+					mu.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					mu.Lock()
+					active--
+					mu.Unlock()
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+					}, nil
+				},
+			}
+
+			d := New(123, "test-key", append(tt.options, WithHTTPClient(mockClient))...)
+			defer d.Close()
+
+			requests := make([]InvitedByRequest, 10)
+			for i := range requests {
+				requests[i] = InvitedByRequest{UserID: int64(i), InvitedBy: 1000}
+			}
+
+			for i, err := range d.InvitedByBatch(requests) {
+				if err != nil {
+					t.Errorf("unexpected error at index %d: %v", i, err)
+				}
+			}
+
+			if maxActive > tt.expectedMax {
+				t.Errorf("expected at most %d concurrent requests, got %d", tt.expectedMax, maxActive)
+			}
+		})
+	}
+}
+
+func TestDashgram_ImportInvitedBy(t *testing.T) {
+	pairs := make([]InvitePair, 25)
+	for i := range pairs {
+		pairs[i] = InvitePair{UserID: int64(1000 + i), InvitedBy: 1}
+	}
+
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusInternalServerError, `{"status":"error","details":"temporary failure"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	var progress []ImportProgress
+	err := d.ImportInvitedBy(context.Background(), pairs, InviteImportOptions{
+		ChunkSize: 10,
+		Progress: func(p ImportProgress) {
+			progress = append(progress, p)
+		},
+	})
+
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("expected ImportError, got %v", err)
+	}
+	if importErr.Chunks != 3 || len(importErr.Failed) != 1 {
+		t.Fatalf("expected 1 of 3 chunks to fail, got %d of %d", len(importErr.Failed), importErr.Chunks)
+	}
+	if failed := importErr.Failed[0]; failed.Chunk != 1 || failed.Start != 10 || failed.End != 20 {
+		t.Errorf("expected chunk 1 with pairs 10-20 to fail, got %+v", failed)
+	}
+	var apiErr *DashgramAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected the chunk error to unwrap to the API error, got %v", err)
+	}
+
+	requests := helper.RecordedRequests()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	for i, size := range []int{10, 10, 5} {
+		if !strings.HasSuffix(requests[i].URL.Path, "/invited_by/bulk") {
+			t.Errorf("expected endpoint '/invited_by/bulk', got %s", requests[i].URL.Path)
+		}
+		var body InvitedByBulkRequest
+		if err := json.Unmarshal(requests[i].Body, &body); err != nil {
+			t.Fatalf("failed to unmarshal request body: %v", err)
+		}
+		if len(body.Invites) != size {
+			t.Errorf("expected chunk %d to have %d pairs, got %d", i, size, len(body.Invites))
+		}
+	}
+
+	if len(progress) != 3 {
+		t.Fatalf("expected 3 progress reports, got %d", len(progress))
+	}
+	if last := progress[2]; last.Done != 25 || last.Total != 25 || last.Chunks != 3 || last.Err != nil {
+		t.Errorf("unexpected last progress report: %+v", last)
+	}
+	if progress[1].Err == nil {
+		t.Errorf("expected the failed chunk to be reported with its error")
+	}
+}
+
+func TestDashgram_ImportInvitedByCancelled(t *testing.T) {
+	pairs := make([]InvitePair, 30)
+	for i := range pairs {
+		pairs[i] = InvitePair{UserID: int64(1000 + i), InvitedBy: 1}
+	}
+
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := d.ImportInvitedBy(ctx, pairs, InviteImportOptions{
+		ChunkSize: 10,
+		Progress: func(ImportProgress) {
+			cancel()
+		},
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if helper.RequestCount != 1 {
+		t.Errorf("expected the remaining chunks not to be sent, got %d requests", helper.RequestCount)
+	}
+}
+
+func TestDashgram_ImportInvitedBySequential(t *testing.T) {
+	pairs := []InvitePair{{UserID: 1, InvitedBy: 9}, {UserID: 2, InvitedBy: 9}, {UserID: 3, InvitedBy: 9}}
+
+	helper := NewTestHelper()
+	for range pairs {
+		helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	}
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	if err := d.ImportInvitedBy(context.Background(), pairs, InviteImportOptions{Sequential: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := helper.RecordedRequests()
+	if len(requests) != len(pairs) {
+		t.Fatalf("expected %d requests, got %d", len(pairs), len(requests))
+	}
+	for i, req := range requests {
+		var body InvitedByRequest
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			t.Fatalf("failed to unmarshal request body: %v", err)
+		}
+		if !strings.HasSuffix(req.URL.Path, "/invited_by") || body.UserID != pairs[i].UserID {
+			t.Errorf("expected invited_by request for user %d, got %s %+v", pairs[i].UserID, req.URL.Path, body)
+		}
+	}
+}
+
+func TestDashgram_TrackEventBatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		events        []any
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "single request for all events",
+			events:   []any{map[string]any{"action": "first"}, map[string]any{"action": "second"}},
+			expected: `{"updates":[{"action":"first"},{"action":"second"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "no events",
+			events:        nil,
+			expectedError: "invalid events: must not be empty",
+		},
+		{
+			name:          "invalid event",
+			events:        []any{map[string]any{"action": "first"}, nil},
+			expectedError: "event 1: invalid event: must not be nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			err := d.TrackEventBatch(tt.events)
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedError, err)
+				}
+				if helper.RequestCount != 0 {
+					t.Errorf("expected no requests, got %d", helper.RequestCount)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if body := string(helper.LastRequest().Body); body != tt.expected {
+				t.Errorf("expected body '%s', got '%s'", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithWorkerBatchSize(t *testing.T) {
+	helper := NewTestHelper()
+	for i := 0; i < 10; i++ {
+		helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	}
+
+	var mu sync.Mutex
+	var delivered int
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithWorkerBatchSize(5),
+		WithWorkerBatchFlushInterval(50*time.Millisecond),
+		WithSuccessHandler(func(task AsyncTaskInfo) {
+			mu.Lock()
+			delivered++
+			mu.Unlock()
+		}),
+	)
+	defer d.Close()
+
+	for i := 0; i < 4; i++ {
+		d.TrackEventAsync(map[string]any{"action": fmt.Sprintf("event_%d", i)})
+	}
+	d.InvitedByAsync(1, 2)
+	d.TrackEventAsync(map[string]any{"action": "other_origin"}, WithCallOrigin("Other"))
+
+	// 5 tasks fill the first batch, the 6th is flushed after the interval
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		done := delivered == 6
+		mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	if delivered != 6 {
+		t.Errorf("expected 6 delivered tasks, got %d", delivered)
+	}
+	mu.Unlock()
+
+	requests := helper.RecordedRequests()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+
+	var trackBodies []TrackEventRequest
+	var invites int
+	for _, req := range requests {
+		if strings.HasSuffix(req.URL.Path, "/invited_by") {
+			invites++
+			continue
+		}
+		var body TrackEventRequest
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			t.Fatalf("failed to unmarshal request body: %v", err)
+		}
+		trackBodies = append(trackBodies, body)
+	}
+
+	if invites != 1 {
+		t.Errorf("expected 1 invited_by request, got %d", invites)
+	}
+	if len(trackBodies) != 2 {
+		t.Fatalf("expected 2 track requests, got %d", len(trackBodies))
+	}
+	if len(trackBodies[0].Updates) != 4 || trackBodies[0].Origin != "Go + Dashgram SDK" {
+		t.Errorf("expected the first 4 events merged into one request, got %+v", trackBodies[0])
+	}
+	if len(trackBodies[1].Updates) != 1 || trackBodies[1].Origin != "Other" {
+		t.Errorf("expected the event with another origin sent separately, got %+v", trackBodies[1])
+	}
+}
+
+func TestDashgram_WithWorkerBatchSizeKeepsOrder(t *testing.T) {
+	helper := NewTestHelper()
+	for i := 0; i < 5; i++ {
+		helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	}
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithNumWorkers(1),
+		WithWorkerBatchSize(6),
+		WithWorkerBatchFlushInterval(time.Second),
+	)
+
+	d.TrackEventAsync(map[string]any{"action": "a"})
+	d.TrackEventAsync(map[string]any{"action": "b"})
+	d.TrackEventAsync(map[string]any{"action": "c"}, WithCallHeader("X-Request-ID", "1"))
+	d.TrackEventAsync(map[string]any{"action": "d"})
+	d.TrackEventAsync(map[string]any{"action": "e"}, WithCallOrigin("Other"))
+	d.TrackEventAsync(map[string]any{"action": "f"})
+	d.Close()
+
+	var actions [][]string
+	for _, req := range helper.RecordedRequests() {
+		var body struct {
+			Updates []struct {
+				Action string `json:"action"`
+			} `json:"updates"`
+		}
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			t.Fatalf("failed to unmarshal request body: %v", err)
+		}
+		var request []string
+		for _, update := range body.Updates {
+			request = append(request, update.Action)
+		}
+		actions = append(actions, request)
+	}
+
+	expected := [][]string{{"a", "b"}, {"c"}, {"d"}, {"e"}, {"f"}}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Errorf("expected requests %v, got %v", expected, actions)
+	}
+}
+
+func TestDashgram_WithWorkerBatchSizeKeepsPropagation(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	helper := NewTestHelper()
+	for i := 0; i < 3; i++ {
+		helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	}
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithNumWorkers(1),
+		WithWorkerBatchSize(3),
+		WithWorkerBatchFlushInterval(time.Second),
+		WithTracePropagation(),
+	)
+
+	ctx := ContextWithW3CTrace(context.Background(), traceparent, "")
+	d.TrackEventAsync(map[string]any{"action": "a"})
+	d.TrackEventAsyncWithContext(ctx, map[string]any{"action": "b"})
+	d.TrackEventAsync(map[string]any{"action": "c"})
+	d.Close()
+
+	requests := helper.RecordedRequests()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+
+	expected := []string{"", traceparent, ""}
+	for i, req := range requests {
+		if got := req.Headers.Get("traceparent"); got != expected[i] {
+			t.Errorf("request %d: expected traceparent '%s', got '%s'", i, expected[i], got)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package dashgram
+
+// BeforeSendFunc transforms the payload of a request before it is marshaled.
+// Returning false drops it.
+type BeforeSendFunc func(endpoint string, payload any) (any, bool)
+
+// WithBeforeSend calls fn before anything is sent, e.g. to enrich events with
+// the app version or rename legacy events. For "track" and "group_track" the
+// payload is each event as passed to the tracking method, so the hook runs
+// once per event of a batch; for other endpoints it is the request, such as
+// an InvitedByRequest, UserPropertiesRequest or SegmentRequest. Bulk imports
+// of invitations and segments don't pass through the hook. The hook runs in
+// the calling goroutine, before the task is enqueued by async methods.
+//
+// Dropped payloads are counted in Stats().Filtered and the tracking methods
+// return nil. A panic in fn is recovered and logged, and the payload dropped.
+func WithBeforeSend(fn BeforeSendFunc) Option {
+	return func(d *Dashgram) {
+		d.beforeSend = fn
+	}
+}
+
+// runBeforeSend passes the payload through the WithBeforeSend hook and reports whether to send it
+func (d *Dashgram) runBeforeSend(endpoint string, payload any) (result any, keep bool) {
+	if d.beforeSend == nil {
+		return payload, true
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			d.log(LogLevelError, "before send hook panicked", "endpoint", endpoint, "panic", r)
+			result, keep = nil, false
+		}
+		if !keep {
+			d.stats.filtered.Add(1)
+		}
+	}()
+
+	return d.beforeSend(endpoint, payload)
+}
@@ -0,0 +1,177 @@
+package dashgram
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// enrich adds the app version to map events and renames the legacy "tap" action
+func enrich(endpoint string, payload any) (any, bool) {
+	event, ok := payload.(map[string]any)
+	if !ok {
+		return payload, true
+	}
+	if event["action"] == "drop" {
+		return nil, false
+	}
+
+	enriched := map[string]any{"app_version": "1.2.3"}
+	for key, value := range event {
+		enriched[key] = value
+	}
+	if enriched["action"] == "tap" {
+		enriched["action"] = "click"
+	}
+	return enriched, true
+}
+
+func TestDashgram_WithBeforeSend(t *testing.T) {
+	tests := []struct {
+		name             string
+		async            bool
+		call             func(d *Dashgram) error
+		expectedBody     string
+		expectedFiltered int64
+	}{
+		{
+			name:         "enrich event",
+			call:         func(d *Dashgram) error { return d.TrackEvent(map[string]any{"action": "tap"}) },
+			expectedBody: `{"updates":[{"action":"click","app_version":"1.2.3"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:         "enrich async event",
+			async:        true,
+			call:         func(d *Dashgram) error { return d.TrackEvent(map[string]any{"action": "tap"}) },
+			expectedBody: `{"updates":[{"action":"click","app_version":"1.2.3"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:         "enrich group event",
+			call:         func(d *Dashgram) error { return d.TrackGroupEvent(7, map[string]any{"action": "tap"}) },
+			expectedBody: `{"group_id":7,"updates":[{"action":"click","app_version":"1.2.3"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name: "batch runs per update",
+			call: func(d *Dashgram) error {
+				return d.TrackEventBatch([]any{
+					map[string]any{"action": "tap"},
+					map[string]any{"action": "drop"},
+					map[string]any{"action": "view"},
+				})
+			},
+			expectedBody:     `{"updates":[{"action":"click","app_version":"1.2.3"},{"action":"view","app_version":"1.2.3"}],"origin":"Go + Dashgram SDK"}`,
+			expectedFiltered: 1,
+		},
+		{
+			name:             "drop event",
+			call:             func(d *Dashgram) error { return d.TrackEvent(map[string]any{"action": "drop"}) },
+			expectedFiltered: 1,
+		},
+		{
+			name:             "drop async event",
+			async:            true,
+			call:             func(d *Dashgram) error { return d.TrackEvent(map[string]any{"action": "drop"}) },
+			expectedFiltered: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			options := []Option{WithHTTPClient(helper.MockHTTPClient()), WithBeforeSend(enrich)}
+			if tt.async {
+				options = append(options, WithUseAsync())
+			}
+			d := New(123, "test-key", options...)
+
+			if err := tt.call(d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			d.Close()
+
+			req := helper.LastRequest()
+			if tt.expectedBody == "" {
+				if req != nil {
+					t.Errorf("expected no request, got %s", req.Body)
+				}
+			} else if req == nil {
+				t.Error("expected a request")
+			} else if string(req.Body) != tt.expectedBody {
+				t.Errorf("expected body %s, got %s", tt.expectedBody, req.Body)
+			}
+
+			if filtered := d.Stats().Filtered; filtered != tt.expectedFiltered {
+				t.Errorf("expected %d filtered payloads, got %d", tt.expectedFiltered, filtered)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithBeforeSendInvitedBy(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	var endpoints []string
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithBeforeSend(func(endpoint string, payload any) (any, bool) {
+			endpoints = append(endpoints, endpoint)
+			request := payload.(InvitedByRequest)
+			request.Campaign = "default_campaign"
+			return request, true
+		}),
+	)
+	defer d.Close()
+
+	if err := d.InvitedBy(1, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"user_id":1,"invited_by":2,"campaign":"default_campaign","origin":"Go + Dashgram SDK"}`
+	if body := string(helper.LastRequest().Body); body != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+	if len(endpoints) != 1 || endpoints[0] != "invited_by" {
+		t.Errorf("expected the hook to be called for invited_by, got %v", endpoints)
+	}
+}
+
+func TestDashgram_WithBeforeSendPanic(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	var calls atomic.Int64
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithUseAsync(),
+		WithBeforeSend(func(endpoint string, payload any) (any, bool) {
+			if calls.Add(1) == 1 {
+				panic("broken hook")
+			}
+			return payload, true
+		}),
+	)
+
+	if err := d.TrackEvent(map[string]any{"action": "first"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.TrackEvent(map[string]any{"action": "second"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !helper.WaitForRequests(1, time.Second) {
+		t.Fatal("expected the client to keep working after the panic")
+	}
+	d.Close()
+
+	expected := `{"updates":[{"action":"second"}],"origin":"Go + Dashgram SDK"}`
+	if body := string(helper.LastRequest().Body); body != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+	if filtered := d.Stats().Filtered; filtered != 1 {
+		t.Errorf("expected the panicking payload to be filtered, got %d", filtered)
+	}
+}
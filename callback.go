@@ -0,0 +1,98 @@
+package dashgram
+
+import (
+	"context"
+	"unicode/utf8"
+)
+
+// maxCallbackDataSize is the largest callback data Telegram allows, in bytes
+const maxCallbackDataSize = 64
+
+// TrackCallbackQuery tracks a press of an inline keyboard button, with the
+// callback data of the button and the ID of the message it belongs to:
+//
+//	{"action": "callback_query", "user_id": 42, "properties": {"data": "buy:pro", "message_id": 7}}
+//
+// messageID is 0 for buttons of inline messages and is then omitted. data is
+// truncated to the 64 bytes Telegram allows.
+func (d *Dashgram) TrackCallbackQuery(ctx context.Context, userID int64, data string, messageID int64) error {
+	event, err := callbackQueryEvent(userID, data, messageID)
+	if err != nil {
+		return err
+	}
+
+	return d.TrackEventWithContext(ctx, event)
+}
+
+// TrackCallbackQueryAsyncWithContext validates the arguments and enqueues the button press to be tracked asynchronously
+func (d *Dashgram) TrackCallbackQueryAsyncWithContext(ctx context.Context, userID int64, data string, messageID int64) error {
+	event, err := callbackQueryEvent(userID, data, messageID)
+	if err != nil {
+		return err
+	}
+
+	return d.TrackEventAsyncWithContext(ctx, event)
+}
+
+func (d *Dashgram) TrackCallbackQueryAsync(userID int64, data string, messageID int64) error {
+	return d.TrackCallbackQueryAsyncWithContext(context.Background(), userID, data, messageID)
+}
+
+// TrackCallbackQueryFrom is TrackCallbackQuery for a Telegram CallbackQuery
+func (d *Dashgram) TrackCallbackQueryFrom(ctx context.Context, q CallbackQuery) error {
+	return d.TrackCallbackQuery(ctx, q.From.ID, q.Data, q.messageID())
+}
+
+// TrackCallbackQueryFromAsyncWithContext is TrackCallbackQueryAsyncWithContext for a Telegram CallbackQuery
+func (d *Dashgram) TrackCallbackQueryFromAsyncWithContext(ctx context.Context, q CallbackQuery) error {
+	return d.TrackCallbackQueryAsyncWithContext(ctx, q.From.ID, q.Data, q.messageID())
+}
+
+func (d *Dashgram) TrackCallbackQueryFromAsync(q CallbackQuery) error {
+	return d.TrackCallbackQueryFromAsyncWithContext(context.Background(), q)
+}
+
+// messageID returns the ID of the message of the callback query, 0 for inline messages
+func (q CallbackQuery) messageID() int64 {
+	if q.Message == nil {
+		return 0
+	}
+	return q.Message.MessageID
+}
+
+// callbackQueryEvent validates the arguments of TrackCallbackQuery and returns the event to track
+func callbackQueryEvent(userID int64, data string, messageID int64) (map[string]any, error) {
+	if userID <= 0 {
+		return nil, &ValidationError{Field: "userID", Message: "must be positive"}
+	}
+	if messageID < 0 {
+		return nil, &ValidationError{Field: "messageID", Message: "must not be negative"}
+	}
+
+	properties := map[string]any{
+		"data": truncateCallbackData(data),
+	}
+	if messageID != 0 {
+		properties["message_id"] = messageID
+	}
+
+	return map[string]any{
+		"action":     "callback_query",
+		"user_id":    userID,
+		"properties": properties,
+	}, nil
+}
+
+// truncateCallbackData cuts data to maxCallbackDataSize bytes, without
+// splitting a UTF-8 character
+func truncateCallbackData(data string) string {
+	if len(data) <= maxCallbackDataSize {
+		return data
+	}
+
+	end := maxCallbackDataSize
+	for end > 0 && !utf8.RuneStart(data[end]) {
+		end--
+	}
+	return data[:end]
+}
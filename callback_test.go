@@ -0,0 +1,138 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTruncateCallbackData(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{name: "short", data: "buy:pro", expected: "buy:pro"},
+		{name: "exactly 64 bytes", data: strings.Repeat("a", 64), expected: strings.Repeat("a", 64)},
+		{name: "too long", data: strings.Repeat("a", 70), expected: strings.Repeat("a", 64)},
+		{name: "multi-byte character on the limit", data: strings.Repeat("a", 63) + "é", expected: strings.Repeat("a", 63)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if truncated := truncateCallbackData(tt.data); truncated != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, truncated)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackCallbackQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        int64
+		data          string
+		messageID     int64
+		expected      string
+		expectedField string
+	}{
+		{
+			name:      "button of a message",
+			userID:    12345,
+			data:      "buy:pro",
+			messageID: 7,
+			expected:  `{"updates":[{"action":"callback_query","properties":{"data":"buy:pro","message_id":7},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "button of an inline message",
+			userID:   12345,
+			data:     "buy:pro",
+			expected: `{"updates":[{"action":"callback_query","properties":{"data":"buy:pro"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:      "data truncated",
+			userID:    12345,
+			data:      strings.Repeat("x", 100),
+			messageID: 7,
+			expected:  `{"updates":[{"action":"callback_query","properties":{"data":"` + strings.Repeat("x", 64) + `","message_id":7},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "invalid user",
+			userID:        0,
+			data:          "buy:pro",
+			expectedField: "userID",
+		},
+		{
+			name:          "negative message ID",
+			userID:        12345,
+			data:          "buy:pro",
+			messageID:     -1,
+			expectedField: "messageID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			err := d.TrackCallbackQuery(context.Background(), tt.userID, tt.data, tt.messageID)
+
+			if tt.expectedField != "" {
+				assertValidationError(t, err, tt.expectedField, helper)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if body := string(helper.LastRequest().Body); body != tt.expected {
+				t.Errorf("expected body %s, got %s", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackCallbackQueryFrom(t *testing.T) {
+	var query CallbackQuery
+	update := `{"id":"4382bfdwdsb323b2d9","from":{"id":12345,"is_bot":false,"first_name":"Ann"},"message":{"message_id":7,"date":1714564800},"chat_instance":"-123","data":"buy:pro"}`
+	if err := json.Unmarshal([]byte(update), &query); err != nil {
+		t.Fatalf("failed to decode callback query: %v", err)
+	}
+
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+
+	if err := d.TrackCallbackQueryFrom(context.Background(), query); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.TrackCallbackQueryFromAsync(query); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.TrackCallbackQueryFromAsync(CallbackQuery{Data: "buy:pro"}); err == nil {
+		t.Error("expected a callback query without a user to be rejected")
+	}
+	d.Close()
+
+	expected := `{"updates":[{"action":"callback_query","properties":{"data":"buy:pro","message_id":7},"user_id":12345}],"origin":"Go + Dashgram SDK"}`
+	requests := helper.RecordedRequests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	for _, req := range requests {
+		if body := string(req.Body); body != expected {
+			t.Errorf("expected body %s, got %s", expected, body)
+		}
+	}
+	if enqueued := d.Stats().Enqueued; enqueued != 1 {
+		t.Errorf("expected 1 enqueued task, got %d", enqueued)
+	}
+}
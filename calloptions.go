@@ -0,0 +1,90 @@
+package dashgram
+
+import (
+	"net/http"
+	"time"
+)
+
+// CallOption overrides the client configuration for a single call
+type CallOption func(*callOptions)
+
+// callOptions holds the per-call overrides
+type callOptions struct {
+	origin   string
+	timeout  time.Duration
+	headers  http.Header
+	priority Priority
+
+	// projectID overrides the project of the call, set by WithProjectIDHasher
+	projectID int
+
+	maxRetries   int
+	retryBackoff time.Duration
+	retrySet     bool
+
+	sampleRate    float64
+	sampleRateSet bool
+}
+
+// newCallOptions resolves the per-call overrides
+func newCallOptions(opts []CallOption) callOptions {
+	var co callOptions
+	for _, opt := range opts {
+		opt(&co)
+	}
+	return co
+}
+
+// originOr returns the overridden origin, or the default one
+func (co callOptions) originOr(origin string) string {
+	if co.origin != "" {
+		return co.origin
+	}
+	return origin
+}
+
+// WithCallOrigin overrides the origin for a single call
+func WithCallOrigin(origin string) CallOption {
+	return func(co *callOptions) {
+		co.origin = origin
+	}
+}
+
+// WithCallTimeout overrides the request timeout for a single call
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(co *callOptions) {
+		co.timeout = timeout
+	}
+}
+
+// withCallRetry overrides WithRetry for a single call, 0 retries disables them.
+// It is internal to ImportEvents, whose requests are never merged by workers.
+func withCallRetry(maxRetries int, backoff time.Duration) CallOption {
+	return func(co *callOptions) {
+		if maxRetries < 0 {
+			maxRetries = 0
+		}
+		if backoff < 0 {
+			backoff = 0
+		}
+		co.maxRetries, co.retryBackoff, co.retrySet = maxRetries, backoff, true
+	}
+}
+
+// retryOr returns the overridden retries and backoff, or the default ones
+func (co callOptions) retryOr(maxRetries int, backoff time.Duration) (int, time.Duration) {
+	if co.retrySet {
+		return co.maxRetries, co.retryBackoff
+	}
+	return maxRetries, backoff
+}
+
+// WithCallHeader adds an HTTP header to the request of a single call
+func WithCallHeader(key, value string) CallOption {
+	return func(co *callOptions) {
+		if co.headers == nil {
+			co.headers = make(http.Header)
+		}
+		co.headers.Add(key, value)
+	}
+}
@@ -0,0 +1,146 @@
+package dashgram
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCallOptions(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithOrigin("Default Origin"))
+	defer d.Close()
+
+	err := d.TrackEvent(map[string]any{"action": "click"},
+		WithCallOrigin("Call Origin"),
+		WithCallHeader("X-Request-ID", "abc"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := helper.LastRequest()
+	if got := req.Headers.Get("X-Request-ID"); got != "abc" {
+		t.Errorf("expected header X-Request-ID 'abc', got '%s'", got)
+	}
+
+	var body TrackEventRequest
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatalf("failed to unmarshal request body: %v", err)
+	}
+	if body.Origin != "Call Origin" {
+		t.Errorf("expected origin 'Call Origin', got '%s'", body.Origin)
+	}
+
+	// Overrides only apply to the call they were passed to
+	if err := d.InvitedBy(1, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req = helper.LastRequest()
+	if got := req.Headers.Get("X-Request-ID"); got != "" {
+		t.Errorf("expected no X-Request-ID header, got '%s'", got)
+	}
+
+	var invited InvitedByRequest
+	if err := json.Unmarshal(req.Body, &invited); err != nil {
+		t.Fatalf("failed to unmarshal request body: %v", err)
+	}
+	if invited.Origin != "Default Origin" {
+		t.Errorf("expected origin 'Default Origin', got '%s'", invited.Origin)
+	}
+}
+
+func TestCallOptions_Async(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithOrigin("Default Origin"))
+
+	defer d.Close()
+
+	d.InvitedByAsync(1, 2, WithCallOrigin("Call Origin"), WithCallHeader("X-Request-ID", "abc"))
+	if !helper.WaitForRequests(1, time.Second) {
+		t.Fatal("expected a request to be sent")
+	}
+
+	req := helper.LastRequest()
+	if got := req.Headers.Get("X-Request-ID"); got != "abc" {
+		t.Errorf("expected header X-Request-ID 'abc', got '%s'", got)
+	}
+
+	var body InvitedByRequest
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatalf("failed to unmarshal request body: %v", err)
+	}
+	if body.Origin != "Call Origin" {
+		t.Errorf("expected origin 'Call Origin', got '%s'", body.Origin)
+	}
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	client := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		},
+	}
+
+	d := New(123, "test-key", WithHTTPClient(client), WithRequestTimeout(time.Minute))
+	defer d.Close()
+
+	start := time.Now()
+	err := d.TrackEvent(map[string]any{"action": "click"}, WithCallTimeout(20*time.Millisecond))
+	if err == nil {
+		t.Fatal("expected timeout error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the call timeout to apply, took %v", elapsed)
+	}
+}
+
+func TestWithCallRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		opts             []CallOption
+		expectedRequests int
+	}{
+		{
+			name:             "client retries",
+			expectedRequests: 2,
+		},
+		{
+			name:             "more retries",
+			opts:             []CallOption{withCallRetry(2, time.Millisecond)},
+			expectedRequests: 3,
+		},
+		{
+			name:             "retries disabled",
+			opts:             []CallOption{withCallRetry(0, 0)},
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			for i := 0; i < 3; i++ {
+				helper.AddResponse(http.StatusServiceUnavailable, `{"status":"error","details":"unavailable"}`)
+			}
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithRetry(1, time.Millisecond))
+			defer d.Close()
+
+			if err := d.TrackEvent(map[string]any{"action": "click"}, tt.opts...); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if helper.RequestCount != tt.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tt.expectedRequests, helper.RequestCount)
+			}
+		})
+	}
+}
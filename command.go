@@ -0,0 +1,99 @@
+package dashgram
+
+import (
+	"context"
+	"strings"
+)
+
+// ParseCommand splits the text of a Telegram message into a bot command and
+// its payload, e.g. "/start@MyBot ref_42" into "start" and "ref_42". The
+// leading slash and the @botname suffix are stripped. ok is false if the text
+// isn't a command.
+func ParseCommand(text string) (cmd, payload string, ok bool) {
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+
+	cmd, payload, _ = strings.Cut(text, " ")
+	cmd = normalizeCommand(cmd)
+	if !isValidCommand(cmd) {
+		return "", "", false
+	}
+
+	return cmd, strings.TrimSpace(payload), true
+}
+
+// normalizeCommand strips the leading slash and the @botname suffix of a command
+func normalizeCommand(command string) string {
+	command = strings.TrimPrefix(strings.TrimSpace(command), "/")
+	command, _, _ = strings.Cut(command, "@")
+	return command
+}
+
+// isValidCommand reports whether the command only holds the letters, digits
+// and underscores Telegram allows, up to 32 of them
+func isValidCommand(command string) bool {
+	if command == "" || len(command) > 32 {
+		return false
+	}
+	for _, r := range command {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// commandEvent validates the arguments of TrackCommand and returns the event to track
+func commandEvent(userID int64, command string, payload string) (map[string]any, error) {
+	if userID <= 0 {
+		return nil, &ValidationError{Field: "userID", Message: "must be positive"}
+	}
+	command = normalizeCommand(command)
+	if !isValidCommand(command) {
+		return nil, &ValidationError{Field: "command", Message: "must be a bot command"}
+	}
+
+	properties := map[string]any{
+		"command": command,
+	}
+	if payload != "" {
+		properties["payload"] = payload
+	}
+
+	return map[string]any{
+		"action":     "command",
+		"user_id":    userID,
+		"properties": properties,
+	}, nil
+}
+
+// TrackCommand tracks a bot command sent by a user. The command is normalized,
+// so "/start", "start" and "/start@MyBot" are tracked alike, and its payload,
+// such as a deep-link start parameter, is recorded separately:
+//
+//	{"action": "command", "user_id": 42, "properties": {"command": "start", "payload": "ref_42"}}
+//
+// Use ParseCommand to split a message text into its command and payload.
+func (d *Dashgram) TrackCommand(ctx context.Context, userID int64, command string, payload string) error {
+	event, err := commandEvent(userID, command, payload)
+	if err != nil {
+		return err
+	}
+
+	return d.TrackEventWithContext(ctx, event)
+}
+
+// TrackCommandAsyncWithContext validates the command and enqueues it to be tracked asynchronously
+func (d *Dashgram) TrackCommandAsyncWithContext(ctx context.Context, userID int64, command string, payload string) error {
+	event, err := commandEvent(userID, command, payload)
+	if err != nil {
+		return err
+	}
+
+	return d.TrackEventAsyncWithContext(ctx, event)
+}
+
+func (d *Dashgram) TrackCommandAsync(userID int64, command string, payload string) error {
+	return d.TrackCommandAsyncWithContext(context.Background(), userID, command, payload)
+}
@@ -0,0 +1,126 @@
+package dashgram
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		text            string
+		expectedCmd     string
+		expectedPayload string
+		expectedOK      bool
+	}{
+		{text: "/start", expectedCmd: "start", expectedOK: true},
+		{text: "/start ref_42", expectedCmd: "start", expectedPayload: "ref_42", expectedOK: true},
+		{text: "/start@MyBot ref_42", expectedCmd: "start", expectedPayload: "ref_42", expectedOK: true},
+		{text: "/help@MyBot", expectedCmd: "help", expectedOK: true},
+		{text: "/search  golang  tips ", expectedCmd: "search", expectedPayload: "golang  tips", expectedOK: true},
+		{text: "hello /start"},
+		{text: "/"},
+		{text: "/@MyBot"},
+		{text: "/не_команда"},
+		{text: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			cmd, payload, ok := ParseCommand(tt.text)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok %v, got %v", tt.expectedOK, ok)
+			}
+			if cmd != tt.expectedCmd {
+				t.Errorf("expected command %q, got %q", tt.expectedCmd, cmd)
+			}
+			if payload != tt.expectedPayload {
+				t.Errorf("expected payload %q, got %q", tt.expectedPayload, payload)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackCommand(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        int64
+		command       string
+		payload       string
+		expected      string
+		expectedField string
+	}{
+		{
+			name:     "start with payload",
+			userID:   12345,
+			command:  "/start@MyBot",
+			payload:  "ref_42",
+			expected: `{"updates":[{"action":"command","properties":{"command":"start","payload":"ref_42"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "bare command",
+			userID:   12345,
+			command:  "help",
+			expected: `{"updates":[{"action":"command","properties":{"command":"help"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "invalid user",
+			userID:        0,
+			command:       "/start",
+			expectedField: "userID",
+		},
+		{
+			name:          "empty command",
+			userID:        12345,
+			command:       "/",
+			expectedField: "command",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			err := d.TrackCommand(context.Background(), tt.userID, tt.command, tt.payload)
+
+			if tt.expectedField != "" {
+				assertValidationError(t, err, tt.expectedField, helper)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if body := string(helper.LastRequest().Body); body != tt.expected {
+				t.Errorf("expected body %s, got %s", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackCommandAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+
+	if err := d.TrackCommandAsync(12345, "/start", "ref_42"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.TrackCommandAsync(12345, "/", ""); err == nil {
+		t.Error("expected an invalid command to be rejected")
+	}
+	d.Close()
+
+	expected := `{"updates":[{"action":"command","properties":{"command":"start","payload":"ref_42"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`
+	if body := string(helper.LastRequest().Body); body != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+	if enqueued := d.Stats().Enqueued; enqueued != 1 {
+		t.Errorf("expected 1 enqueued task, got %d", enqueued)
+	}
+}
@@ -0,0 +1,49 @@
+package dashgram
+
+// DashgramConfig is a serializable snapshot of a client configuration
+type DashgramConfig struct {
+	ProjectID  int    `json:"project_id"`
+	AccessKey  string `json:"access_key"`
+	APIURL     string `json:"api_url,omitempty"`
+	Origin     string `json:"origin,omitempty"`
+	UseAsync   bool   `json:"use_async,omitempty"`
+	NumWorkers int    `json:"num_workers,omitempty"`
+	QueueSize  int    `json:"queue_size,omitempty"`
+}
+
+// Snapshot returns the serializable configuration of the client. Note that it
+// includes the access key, so store it as a secret.
+func (d *Dashgram) Snapshot() DashgramConfig {
+	return DashgramConfig{
+		ProjectID:  d.ProjectID,
+		AccessKey:  d.accessKey(),
+		APIURL:     d.baseURL,
+		Origin:     d.Origin,
+		UseAsync:   d.useAsync,
+		NumWorkers: d.numWorkers,
+		QueueSize:  d.queueSize,
+	}
+}
+
+// NewFromConfig creates a new Dashgram client from a configuration. Zero
+// fields keep their defaults, and options are applied after the configuration.
+func NewFromConfig(cfg DashgramConfig, options ...Option) *Dashgram {
+	var configOptions []Option
+	if cfg.APIURL != "" {
+		configOptions = append(configOptions, WithAPIURL(cfg.APIURL))
+	}
+	if cfg.Origin != "" {
+		configOptions = append(configOptions, WithOrigin(cfg.Origin))
+	}
+	if cfg.UseAsync {
+		configOptions = append(configOptions, WithUseAsync())
+	}
+	if cfg.NumWorkers > 0 {
+		configOptions = append(configOptions, WithNumWorkers(cfg.NumWorkers))
+	}
+	if cfg.QueueSize > 0 {
+		configOptions = append(configOptions, WithQueueSize(cfg.QueueSize))
+	}
+
+	return New(cfg.ProjectID, cfg.AccessKey, append(configOptions, options...)...)
+}
@@ -0,0 +1,133 @@
+package dashgram
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDashgram_Snapshot(t *testing.T) {
+	d := New(123, "test-key",
+		WithAPIURL("https://custom.api.com/v2"),
+		WithOrigin("Test App"),
+		WithUseAsync(),
+		WithNumWorkers(4),
+		WithQueueSize(50),
+	)
+	defer d.Close()
+
+	expected := DashgramConfig{
+		ProjectID:  123,
+		AccessKey:  "test-key",
+		APIURL:     "https://custom.api.com/v2",
+		Origin:     "Test App",
+		UseAsync:   true,
+		NumWorkers: 4,
+		QueueSize:  50,
+	}
+
+	if cfg := d.Snapshot(); cfg != expected {
+		t.Errorf("expected snapshot %+v, got %+v", expected, cfg)
+	}
+	if cap(d.pool.queue(PriorityNormal)) != 50 {
+		t.Errorf("expected queue capacity 50, got %d", cap(d.pool.queue(PriorityNormal)))
+	}
+}
+
+func TestDashgramConfig_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   DashgramConfig
+		expected string
+	}{
+		{
+			name: "full config",
+			config: DashgramConfig{
+				ProjectID:  123,
+				AccessKey:  "test-key",
+				APIURL:     "https://custom.api.com/v2",
+				Origin:     "Test App",
+				UseAsync:   true,
+				NumWorkers: 4,
+				QueueSize:  50,
+			},
+			expected: `{"project_id":123,"access_key":"test-key","api_url":"https://custom.api.com/v2","origin":"Test App","use_async":true,"num_workers":4,"queue_size":50}`,
+		},
+		{
+			name: "minimal config",
+			config: DashgramConfig{
+				ProjectID: 456,
+				AccessKey: "test-key-2",
+			},
+			expected: `{"project_id":456,"access_key":"test-key-2"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.config)
+			if err != nil {
+				t.Fatalf("failed to marshal DashgramConfig: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("expected JSON '%s', got '%s'", tt.expected, string(data))
+			}
+
+			var unmarshaled DashgramConfig
+			if err := json.Unmarshal(data, &unmarshaled); err != nil {
+				t.Fatalf("failed to unmarshal DashgramConfig: %v", err)
+			}
+			if unmarshaled != tt.config {
+				t.Errorf("expected %+v, got %+v", tt.config, unmarshaled)
+			}
+		})
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	original := New(123, "test-key",
+		WithAPIURL("https://custom.api.com/v2"),
+		WithOrigin("Test App"),
+		WithNumWorkers(3),
+		WithQueueSize(10),
+	)
+	defer original.Close()
+
+	data, err := json.Marshal(original.Snapshot())
+	if err != nil {
+		t.Fatalf("failed to marshal snapshot: %v", err)
+	}
+
+	var cfg DashgramConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("failed to unmarshal snapshot: %v", err)
+	}
+
+	restored := NewFromConfig(cfg)
+	defer restored.Close()
+
+	if restored.Snapshot() != original.Snapshot() {
+		t.Errorf("expected restored config %+v, got %+v", original.Snapshot(), restored.Snapshot())
+	}
+	if restored.APIURL != "https://custom.api.com/v2/123" {
+		t.Errorf("expected APIURL 'https://custom.api.com/v2/123', got %s", restored.APIURL)
+	}
+
+	// Options take precedence over the configuration
+	overridden := NewFromConfig(cfg, WithOrigin("Override"))
+	defer overridden.Close()
+
+	if overridden.Origin != "Override" {
+		t.Errorf("expected Origin 'Override', got %s", overridden.Origin)
+	}
+
+	// Zero fields keep their defaults
+	defaults := NewFromConfig(DashgramConfig{ProjectID: 1, AccessKey: "key"})
+	defer defaults.Close()
+
+	if defaults.APIURL != "https://api.dashgram.io/v1/1" {
+		t.Errorf("expected default APIURL, got %s", defaults.APIURL)
+	}
+	if defaults.queueSize != defaultQueueSize {
+		t.Errorf("expected default queue size %d, got %d", defaultQueueSize, defaults.queueSize)
+	}
+}
@@ -6,8 +6,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"reflect"
+	"runtime/pprof"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,8 +22,124 @@ type asyncTask struct {
 	ctx      context.Context
 	endpoint string
 	data     any
+	opts     []CallOption
+	// enqueuedAt is when the task entered the queue
+	enqueuedAt time.Time
+	// result, if not nil, receives the result of the task and is then closed
+	result chan<- error
+	// callback, if not nil, is called with the result of the task
+	callback func(error)
+	// orderKey routes the task to a worker lane, see WithAsyncEventOrdering
+	orderKey string
 }
 
+// reportResult sends the result of the task to its result channel and its
+// callback, if any
+func (task asyncTask) reportResult(err error) {
+	if task.result != nil {
+		task.result <- err
+		close(task.result)
+	}
+	if task.callback != nil {
+		// A misbehaving callback must not kill the worker
+		defer func() {
+			recover()
+		}()
+		task.callback(err)
+	}
+}
+
+// defaultQueueSize is the number of tasks buffered by each worker pool per priority
+const defaultQueueSize = 1000
+
+// workerPool is a group of workers consuming tasks from shared queues, one per
+// priority. With WithAsyncEventOrdering, each worker has its own queues
+// instead, held by a single-worker pool in lanes.
+type workerPool struct {
+	size      int
+	queues    [3]chan asyncTask
+	processed atomic.Int64
+	lanes     []*workerPool
+	nextLane  atomic.Uint64
+}
+
+func newWorkerPool(size int, queueSize int) *workerPool {
+	if size < 1 {
+		size = 1
+	}
+
+	pool := &workerPool{size: size}
+	for i := range pool.queues {
+		pool.queues[i] = make(chan asyncTask, queueSize)
+	}
+	return pool
+}
+
+// queue returns the queue of tasks with the given priority
+func (p *workerPool) queue(priority Priority) chan asyncTask {
+	return p.queues[priority.index()]
+}
+
+// len returns the number of tasks waiting in the pool's queues
+func (p *workerPool) len() int {
+	length := 0
+	for _, lane := range p.lanes {
+		length += lane.len()
+	}
+	for _, queue := range p.queues {
+		length += len(queue)
+	}
+	return length
+}
+
+// drain removes the tasks left in the pool's queues, passing each to fn
+func (p *workerPool) drain(fn func(task asyncTask)) {
+	for _, lane := range p.lanes {
+		lane.drain(fn)
+	}
+	for _, queue := range p.queues {
+		// The workers stopped, so nothing else receives from the queue
+		for len(queue) > 0 {
+			fn(<-queue)
+		}
+	}
+}
+
+// next waits for the highest priority task, it returns false once done is
+// closed or timeout fires. A nil timeout never fires.
+func (p *workerPool) next(done <-chan struct{}, timeout <-chan time.Time) (asyncTask, bool) {
+	for _, queue := range p.queues {
+		select {
+		case task := <-queue:
+			return task, true
+		default:
+		}
+	}
+
+	select {
+	case task := <-p.queues[0]:
+		return task, true
+	case task := <-p.queues[1]:
+		return task, true
+	case task := <-p.queues[2]:
+		return task, true
+	case <-done:
+		return asyncTask{}, false
+	case <-timeout:
+		return asyncTask{}, false
+	}
+}
+
+// authScheme is the scheme used to authenticate requests
+type authScheme int
+
+const (
+	// authSchemeBearer sends the access key as a Bearer token
+	authSchemeBearer authScheme = iota
+	// authSchemeBasic sends HTTP Basic Auth credentials
+	authSchemeBasic
+)
+
 // HttpClient is an interface that wraps the Do method
 type HttpClient interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -30,33 +152,181 @@ type Dashgram struct {
 	APIURL    string
 	Origin    string
 	client    HttpClient
+	baseURL   string
+	accept    string
+	now       func() time.Time
+
+	// Authentication
+	authScheme     authScheme
+	basicUsername  string
+	basicPassword  string
+	keyMu          sync.RWMutex
+	keyMasker      AccessKeyMasker
+	tokenRefresher TokenRefresher
+
+	// Logging
+	logger   Logger
+	logLevel LogLevel
+
+	// Event transformation
+	eventFormat     EventFormat
+	eventTimestamps bool
+	timeEncoding    TimeEncoding
+	omitEmpty       bool
+	encoder         Encoder
+	fieldNames      map[string]string
+	scrubPaths      [][]string
+	useNumber       bool
+
+	// Request timeouts
+	requestTimeout   time.Duration
+	endpointTimeouts map[string]time.Duration
+
+	// Transport timeouts
+	dialTimeout           time.Duration
+	responseHeaderTimeout time.Duration
+	closeConnOnError      bool
+	redirectPolicy        func(req *http.Request, via []*http.Request) error
+
+	// Retries
+	maxRetries        int
+	retryBackoff      time.Duration
+	retryableStatuses map[int]bool
+	retryableCheck    RetryableChecker
+	retryBudget       *retryBudget
+
+	// Connection warm-up
+	keepAliveProbe bool
+
+	// Ping cache
+	pingCache pingCache
+
+	// Request hooks
+	clientTrace func(ctx context.Context) context.Context
+	propagators []ContextPropagator
+
+	// Configuration validation
+	projectIDValidators []ProjectIDValidator
+	httpsOnly           bool
+	failFast            bool
+	configErr           error
+
+	// Batch requests
+	batchConcurrency int
+
+	// Webhook middleware
+	webhookMaxBodySize int64
 
 	// Async worker
-	useAsync     bool
-	numWorkers   int
-	workerCtx    context.Context
-	workerCancel context.CancelFunc
-	taskChan     chan asyncTask
-	workerWg     sync.WaitGroup
+	useAsync        bool
+	orderedAsync    bool
+	numWorkers      int
+	workerName      string
+	queueSize       int
+	endpointWorkers map[string]int
+	baseCtx         context.Context
+	workerCtx       context.Context
+	workerCancel    context.CancelFunc
+	pool            *workerPool
+	endpointPools   map[string]*workerPool
+	workerWg        sync.WaitGroup
+	successHandler  func(task AsyncTaskInfo)
+	errorHandler    func(task AsyncTaskInfo, err error)
+	closeOnce       sync.Once
+	fallbackToSync  bool
+	taskTimeout     time.Duration
+
+	// Worker batching
+	workerBatchSize     int
+	workerFlushInterval time.Duration
+
+	// Backpressure
+	backpressureThreshold  float64
+	backpressureCallback   func(depth, capacity int)
+	backpressureOverloaded atomic.Bool
+	backpressureMu         sync.Mutex
+
+	// Watchdog
+	watchdogInterval time.Duration
+	watchdogCallback func(pending int)
+	workerStalled    atomic.Bool
+
+	// Shutdown
+	shutdownHandler func(err error)
+	shutdownErr     atomic.Pointer[ShutdownError]
+
+	// Filtering
+	beforeSend BeforeSendFunc
+
+	// Heartbeat
+	heartbeatInterval   time.Duration
+	heartbeatProperties func(properties map[string]any)
+
+	// Request integrity
+	hashRequestBody bool
+
+	// Panic tracking
+	swallowPanics bool
+
+	// Debouncing
+	debounceKey     func(event any) string
+	debounceWindow  time.Duration
+	debounceMu      sync.Mutex
+	debouncePending map[string]*debouncedEvent
+	debounceClosed  bool
+	debounceWg      sync.WaitGroup
+
+	// Sharding
+	projectIDHasher ProjectIDHasher
+	shardProjectIDs []int
+
+	// Opt-out and sampling
+	optOut          func(userID int64) bool
+	userIDExtractor UserIDExtractor
+	sampleRate      float64
+	sampler         Sampler
+	userIDType      reflect.Type
+
+	// Deep-link starts
+	startParsers    []StartPayloadParser
+	startParsersSet bool
+	startNoInvite   bool
+
+	// Synthetic update IDs of TrackMessage
+	lastUpdateID atomic.Int64
+
+	// Stats
+	stats         statsCounters
+	statsInterval time.Duration
+	statsReporter func(Stats)
 }
 
 // New creates a new Dashgram client instance
 func New(projectID int, accessKey string, options ...Option) *Dashgram {
-	ctx, cancel := context.WithCancel(context.Background())
-
 	d := &Dashgram{
 		ProjectID: projectID,
 		AccessKey: accessKey,
 		APIURL:    "https://api.dashgram.io/v1",
 		Origin:    "Go + Dashgram SDK",
+		accept:    "application/json",
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		useAsync:     false,
-		numWorkers:   1,
-		workerCtx:    ctx,
-		workerCancel: cancel,
-		taskChan:     make(chan asyncTask, 1000), // Buffer for 1000 tasks
+		now:                 time.Now,
+		keyMasker:           DefaultAccessKeyMasker,
+		retryBackoff:        100 * time.Millisecond,
+		sampleRate:          1,
+		workerFlushInterval: defaultWorkerFlushInterval,
+		logLevel:            LogLevelInfo,
+		webhookMaxBodySize:  defaultWebhookMaxBodySize,
+		useAsync:            false,
+		numWorkers:          1,
+		workerName:          defaultWorkerName,
+		queueSize:           defaultQueueSize,
+		endpointTimeouts:    make(map[string]time.Duration),
+		endpointWorkers:     make(map[string]int),
+		baseCtx:             context.Background(),
+		endpointPools:       make(map[string]*workerPool),
 	}
 
 	// Apply options
@@ -64,35 +334,225 @@ func New(projectID int, accessKey string, options ...Option) *Dashgram {
 		option(d)
 	}
 
+	if d.batchConcurrency == 0 {
+		d.batchConcurrency = d.numWorkers
+		if d.batchConcurrency < 1 {
+			d.batchConcurrency = 1
+		}
+	}
+
+	if len(d.fieldNames) > 0 && d.encoder == nil {
+		d.encoder = renamingEncoder{names: d.fieldNames}
+	}
+
+	if d.retryableStatuses == nil {
+		WithRetryableStatuses(defaultRetryableStatuses...)(d)
+	}
+
+	d.workerCtx, d.workerCancel = context.WithCancel(d.baseCtx)
+
+	// Apply transport timeouts
+	d.configureTransport()
+	d.configureRedirects()
+
+	// Validate configuration
+	d.configErr = d.validateConfig()
+	if d.configErr != nil && d.failFast {
+		d.workerCancel()
+		panic(d.configErr)
+	}
+
 	// Set up API URL with project ID
+	d.baseURL = d.APIURL
 	d.APIURL = fmt.Sprintf("%s/%d", d.APIURL, d.ProjectID)
 
-	// Start the async worker
+	// Set up worker pools
+	newPool := newWorkerPool
+	if d.orderedAsync {
+		newPool = newOrderedWorkerPool
+	}
+	d.pool = newPool(d.numWorkers, d.queueSize)
+	for endpoint, numWorkers := range d.endpointWorkers {
+		d.endpointPools[endpoint] = newPool(numWorkers, d.queueSize)
+	}
+
+	// Start the async workers
 	d.StartWorker()
+	d.startStatsReporter()
+	d.startWatchdog()
+	d.startKeepAliveProbe()
+	d.startHeartbeat()
 
 	return d
 }
 
-// Close stops the async worker and waits for pending tasks
+// configureTransport applies the transport timeouts to the HTTP client. Only
+// *http.Client clients using an *http.Transport can be configured; the client
+// is copied so one passed to WithHTTPClient is left untouched.
+func (d *Dashgram) configureTransport() {
+	if d.dialTimeout == 0 && d.responseHeaderTimeout == 0 && !d.closeConnOnError {
+		return
+	}
+
+	client, ok := d.client.(*http.Client)
+	if !ok {
+		return
+	}
+
+	var transport *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return
+	}
+
+	if d.dialTimeout > 0 {
+		dialer := &net.Dialer{
+			Timeout:   d.dialTimeout,
+			KeepAlive: 30 * time.Second,
+		}
+		transport.DialContext = dialer.DialContext
+		transport.TLSHandshakeTimeout = d.dialTimeout
+	}
+	if d.responseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = d.responseHeaderTimeout
+	}
+
+	configured := *client
+	configured.Transport = transport
+	d.client = &configured
+}
+
+// validateConfig checks the client configuration
+func (d *Dashgram) validateConfig() error {
+	for _, validator := range d.projectIDValidators {
+		if err := validator(d.ProjectID); err != nil {
+			return &ConfigurationError{Field: "ProjectID", Err: err}
+		}
+	}
+	if d.httpsOnly && !strings.HasPrefix(d.APIURL, "https://") {
+		return &ConfigurationError{Field: "APIURL", Err: fmt.Errorf("must use https, got %q", d.APIURL)}
+	}
+	return d.validateSharding()
+}
+
+// Validate checks the current client configuration, returning a
+// ConfigurationError if it is invalid. Unlike InitErr it doesn't report the
+// outcome of New, so it also covers fields changed since.
+func (d *Dashgram) Validate() error {
+	return d.validateConfig()
+}
+
+// InitErr returns the configuration error detected by New, if any. Requests
+// made by a misconfigured client fail with this error.
+func (d *Dashgram) InitErr() error {
+	return d.configErr
+}
+
+// HTTPClient returns the HTTP client the SDK sends requests with. Options
+// such as WithDialTimeout or WithHTTPRedirectPolicy configure a copy of an
+// *http.Client, which is then returned rather than the one given to
+// WithHTTPClient. The client must not be modified.
+func (d *Dashgram) HTTPClient() HttpClient {
+	return d.client
+}
+
+// Close stops the async workers and waits for the tasks being sent. Tasks
+// still queued are dropped: their result channels and callbacks receive an
+// error wrapping ErrTaskDropped, and ShutdownResult reports them as pending.
+// It is safe to call more than once, and concurrently: every call returns once
+// the client is closed.
 func (d *Dashgram) Close() {
-	d.workerCancel()
-	d.workerWg.Wait()
+	d.closeOnce.Do(func() {
+		d.flushDebounced()
+		failed := d.stats.failed.Load()
+		d.workerCancel()
+		d.workerWg.Wait()
+		d.recordShutdown(failed)
+		d.dropPending()
+
+		if d.statsReporter != nil {
+			d.statsReporter(d.Stats())
+		}
+	})
 }
 
-// startWorker starts the background worker goroutine
+// StartWorker starts the background worker goroutines of every worker pool
 func (d *Dashgram) StartWorker() {
-	d.workerWg.Add(1)
-	go func() {
-		defer d.workerWg.Done()
-		for {
-			select {
-			case task := <-d.taskChan:
-				d.request(task.ctx, task.endpoint, task.data)
-			case <-d.workerCtx.Done():
-				return
-			}
+	d.startPool(d.pool)
+	for _, pool := range d.endpointPools {
+		d.startPool(pool)
+	}
+}
+
+// startPool starts the worker goroutines consuming tasks of a single pool
+func (d *Dashgram) startPool(pool *workerPool) {
+	if len(pool.lanes) > 0 {
+		for _, lane := range pool.lanes {
+			d.startPool(lane)
+		}
+		return
+	}
+
+	for i := 0; i < pool.size; i++ {
+		d.workerWg.Add(1)
+		go func() {
+			defer d.workerWg.Done()
+			pprof.Do(d.workerCtx, pprof.Labels("worker", d.workerName), func(context.Context) {
+				d.runWorker(pool)
+			})
+		}()
+	}
+}
+
+// runWorker consumes the pool's tasks until the client is closed
+func (d *Dashgram) runWorker(pool *workerPool) {
+	if d.workerBatchSize > 1 {
+		d.runBatchWorker(pool)
+		return
+	}
+
+	for {
+		task, ok := d.nextTask(pool, nil)
+		if !ok {
+			return
 		}
-	}()
+		d.checkBackpressure()
+		d.processTask(pool, task)
+	}
+}
+
+// nextTask waits for the next task of the pool until the client is closed or
+// timeout fires, recording how long the task waited in the queue
+func (d *Dashgram) nextTask(pool *workerPool, timeout <-chan time.Time) (asyncTask, bool) {
+	task, ok := pool.next(d.workerCtx.Done(), timeout)
+	if ok {
+		d.stats.queueWait.Add(int64(d.now().Sub(task.enqueuedAt)))
+		d.stats.dequeued.Add(1)
+	}
+	return task, ok
+}
+
+// processTask sends a single async task and reports its result
+func (d *Dashgram) processTask(pool *workerPool, task asyncTask) {
+	d.stats.inFlight.Add(1)
+	ctx, cancel := d.taskContext(task.ctx)
+	err := d.request(ctx, task.endpoint, task.data, task.opts...)
+	cancel()
+	d.stats.inFlight.Add(-1)
+	pool.processed.Add(1)
+	d.handleResult(task, err)
+}
+
+// poolFor returns the worker pool responsible for the given endpoint
+func (d *Dashgram) poolFor(endpoint string) *workerPool {
+	if pool, ok := d.endpointPools[endpoint]; ok {
+		return pool
+	}
+	return d.pool
 }
 
 // Option is a function type for configuring Dashgram client options
@@ -112,6 +572,15 @@ func WithOrigin(origin string) Option {
 	}
 }
 
+// WithAccept sets the Accept header of every request, "application/json" by
+// default, e.g. to opt into a versioned response format. Responses must still
+// be JSON. An empty mediaType omits the header.
+func WithAccept(mediaType string) Option {
+	return func(d *Dashgram) {
+		d.accept = mediaType
+	}
+}
+
 // WithHTTPClient sets a custom HTTP client
 func WithHTTPClient(client HttpClient) Option {
 	return func(d *Dashgram) {
@@ -133,43 +602,286 @@ func WithNumWorkers(numWorkers int) Option {
 	}
 }
 
-// request makes an HTTP request to the Dashgram API
-func (d *Dashgram) request(ctx context.Context, endpoint string, data any) error {
-	// Prepare request body
-	var body io.Reader
-	if data != nil {
-		jsonData, err := json.Marshal(data)
-		if err != nil {
-			return fmt.Errorf("failed to marshal request data: %w", err)
+// defaultWorkerName is the profiler label of the async workers
+const defaultWorkerName = "dashgram-worker"
+
+// WithAsyncWorkerName sets the "worker" pprof label of the async worker
+// goroutines, "dashgram-worker" by default, so they can be told apart in CPU
+// and goroutine profiles, e.g. when several clients run in one process
+func WithAsyncWorkerName(name string) Option {
+	return func(d *Dashgram) {
+		d.workerName = name
+	}
+}
+
+// WithBaseContext sets the context the async workers run under. Cancelling it
+// stops the workers and cancels in-flight requests, whatever context their
+// tasks were enqueued with.
+func WithBaseContext(ctx context.Context) Option {
+	return func(d *Dashgram) {
+		if ctx != nil {
+			d.baseCtx = ctx
 		}
-		body = bytes.NewBuffer(jsonData)
 	}
+}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/%s", d.APIURL, endpoint), body)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// WithQueueSize sets the number of tasks each worker pool can buffer per priority
+func WithQueueSize(queueSize int) Option {
+	return func(d *Dashgram) {
+		if queueSize >= 0 {
+			d.queueSize = queueSize
+		}
 	}
+}
 
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", d.AccessKey))
-	req.Header.Set("Content-Type", "application/json")
+// WithEndpointWorkers dedicates a separate pool of workers to asynchronous
+// requests for the given endpoint (e.g. "track" or "invited_by"). Endpoints
+// without a dedicated pool share the pool sized by WithNumWorkers.
+func WithEndpointWorkers(endpoint string, numWorkers int) Option {
+	return func(d *Dashgram) {
+		d.endpointWorkers[endpoint] = numWorkers
+	}
+}
 
-	// Make request
-	resp, err := d.client.Do(req)
+// WithBatchConcurrency sets the maximum number of concurrent requests made by
+// batch methods, the number of workers set by WithNumWorkers by default
+func WithBatchConcurrency(n int) Option {
+	return func(d *Dashgram) {
+		if n > 0 {
+			d.batchConcurrency = n
+		}
+	}
+}
+
+// ProjectIDValidator validates a project ID, returning an error if it is not allowed
+type ProjectIDValidator func(id int) error
+
+// WithProjectIDValidator adds a validator run against the project ID in New
+func WithProjectIDValidator(fn ProjectIDValidator) Option {
+	return func(d *Dashgram) {
+		d.projectIDValidators = append(d.projectIDValidators, fn)
+	}
+}
+
+// WithPositiveProjectID rejects project IDs that are zero or negative
+func WithPositiveProjectID() Option {
+	return WithProjectIDValidator(func(id int) error {
+		if id <= 0 {
+			return fmt.Errorf("project ID must be positive, got %d", id)
+		}
+		return nil
+	})
+}
+
+// WithHTTPSOnly rejects API URLs not using https, so events are never sent
+// in plaintext. Like the project ID validators, an http API URL fails every
+// request with a ConfigurationError, or panics in New with WithFailFast.
+func WithHTTPSOnly() Option {
+	return func(d *Dashgram) {
+		d.httpsOnly = true
+	}
+}
+
+// WithFailFast makes New panic on an invalid configuration instead of
+// failing every request with the configuration error
+func WithFailFast() Option {
+	return func(d *Dashgram) {
+		d.failFast = true
+	}
+}
+
+// WithRequestTimeout sets a timeout applied to the context of every request
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(d *Dashgram) {
+		d.requestTimeout = timeout
+	}
+}
+
+// WithTimeoutPerEndpoint sets request timeouts for specific endpoints, e.g.
+// {"track": 2 * time.Second}. Other endpoints use WithRequestTimeout.
+func WithTimeoutPerEndpoint(mapping map[string]time.Duration) Option {
+	return func(d *Dashgram) {
+		for endpoint, timeout := range mapping {
+			d.endpointTimeouts[endpoint] = timeout
+		}
+	}
+}
+
+// WithEndpointTimeout sets the request timeout of a single endpoint, e.g.
+// WithEndpointTimeout("track", 2*time.Second) for fire-and-forget events while
+// "invited_by" keeps more leeway. Other endpoints use WithRequestTimeout.
+func WithEndpointTimeout(endpoint string, timeout time.Duration) Option {
+	return func(d *Dashgram) {
+		d.endpointTimeouts[endpoint] = timeout
+	}
+}
+
+// WithDialTimeout sets the timeout for establishing connections, applied to
+// both the TCP connect and the TLS handshake. It is independent of the total
+// request timeout of the HTTP client.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(d *Dashgram) {
+		d.dialTimeout = timeout
+	}
+}
+
+// WithResponseHeaderTimeout sets how long to wait for the response headers
+// after the request is written, independent of the total request timeout
+func WithResponseHeaderTimeout(timeout time.Duration) Option {
+	return func(d *Dashgram) {
+		d.responseHeaderTimeout = timeout
+	}
+}
+
+// WithConnectionCloseOnError closes the idle connections of the HTTP
+// transport after any transport error, so a connection left broken by the
+// network isn't reused. It only applies to *http.Client clients using an
+// *http.Transport, which is then cloned so other clients are unaffected.
+func WithConnectionCloseOnError() Option {
+	return func(d *Dashgram) {
+		d.closeConnOnError = true
+	}
+}
+
+// closeIdleConnections discards the idle connections after a transport error
+// if WithConnectionCloseOnError is set
+func (d *Dashgram) closeIdleConnections() {
+	if !d.closeConnOnError {
+		return
+	}
+	if client, ok := d.client.(*http.Client); ok {
+		if transport, ok := client.Transport.(*http.Transport); ok {
+			transport.CloseIdleConnections()
+		}
+	}
+}
+
+// WithHTTPAuth authenticates requests with HTTP Basic Auth instead of the
+// Bearer access key. Both use the Authorization header, so they are mutually
+// exclusive: the access key is not sent when this option is set.
+func WithHTTPAuth(username, password string) Option {
+	return func(d *Dashgram) {
+		d.authScheme = authSchemeBasic
+		d.basicUsername = username
+		d.basicPassword = password
+	}
+}
+
+// WithClientTrace sets a function evaluated for every request to derive its
+// context, typically to attach an httptrace.ClientTrace:
+//
+//	dashgram.WithClientTrace(func(ctx context.Context) context.Context {
+//		return httptrace.WithClientTrace(ctx, trace)
+//	})
+func WithClientTrace(fn func(ctx context.Context) context.Context) Option {
+	return func(d *Dashgram) {
+		d.clientTrace = fn
+	}
+}
+
+// TokenRefresher returns a new access key, e.g. to replace an expired short-lived token
+type TokenRefresher func(ctx context.Context) (newKey string, err error)
+
+// WithTokenRefreshOnUnauthorized calls fn when the API rejects the access key
+// with 401, stores the returned key and retries the request once. If fn fails,
+// the request returns a TokenRefreshError.
+func WithTokenRefreshOnUnauthorized(fn TokenRefresher) Option {
+	return func(d *Dashgram) {
+		d.tokenRefresher = fn
+	}
+}
+
+// accessKey returns the current access key
+func (d *Dashgram) accessKey() string {
+	d.keyMu.RLock()
+	defer d.keyMu.RUnlock()
+	return d.AccessKey
+}
+
+// setAccessKey replaces the access key used by subsequent requests
+func (d *Dashgram) setAccessKey(key string) {
+	d.keyMu.Lock()
+	defer d.keyMu.Unlock()
+	d.AccessKey = key
+}
+
+// request makes a POST request to the Dashgram API
+func (d *Dashgram) request(ctx context.Context, endpoint string, data any, opts ...CallOption) error {
+	_, err := d.do(ctx, http.MethodPost, endpoint, nil, data, nil, opts...)
+	return err
+}
+
+// do makes an HTTP request to the Dashgram API. The query is appended to the
+// endpoint URL and, if out is not nil, the response body is decoded into it.
+// The response is returned whenever the API answered, even with an error.
+func (d *Dashgram) do(ctx context.Context, method string, endpoint string, query url.Values, data any, out any, opts ...CallOption) (*Response, error) {
+	if d.configErr != nil {
+		return nil, d.configErr
+	}
+
+	call := newCallOptions(opts)
+
+	// Apply the request timeout
+	timeout, ok := d.endpointTimeouts[endpoint]
+	if !ok {
+		timeout = d.requestTimeout
+	}
+	if call.timeout > 0 {
+		timeout = call.timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if d.clientTrace != nil {
+		ctx = d.clientTrace(ctx)
+	}
+
+	requestURL := fmt.Sprintf("%s/%s", d.APIURL, endpoint)
+	if call.projectID != 0 {
+		requestURL = fmt.Sprintf("%s/%d/%s", d.baseURL, call.projectID, endpoint)
+	}
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	data, err := d.scrubRequest(data)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("failed to marshal request data: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
+	resp, respBody, err := d.sendWithRetry(ctx, method, requestURL, endpoint, data, call)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
-	if resp.StatusCode == http.StatusForbidden {
-		return &InvalidCredentialsError{}
+	d.log(LogLevelDebug, "request completed", "endpoint", endpoint, "status", resp.StatusCode)
+
+	// Refresh an expired access key and retry once
+	if resp.StatusCode == http.StatusUnauthorized && d.tokenRefresher != nil {
+		newKey, err := d.tokenRefresher(ctx)
+		if err != nil {
+			return resp, &TokenRefreshError{Err: err}
+		}
+		d.setAccessKey(newKey)
+
+		resp, respBody, err = d.sendWithRetry(ctx, method, requestURL, endpoint, data, call)
+		if err != nil {
+			return nil, err
+		}
+
+		d.log(LogLevelDebug, "request completed", "endpoint", endpoint, "status", resp.StatusCode)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		d.invalidatePing()
+		return resp, &InvalidCredentialsError{}
+	case http.StatusForbidden:
+		d.invalidatePing()
+		return resp, &ForbiddenError{}
 	}
 
 	var response struct {
@@ -178,16 +890,106 @@ func (d *Dashgram) request(ctx context.Context, endpoint string, data any) error
 	}
 
 	if err := json.Unmarshal(respBody, &response); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
+		return resp, fmt.Errorf("failed to parse response: %w", err)
 	}
+	resp.Status = response.Status
+	resp.Details = d.redact(response.Details)
 
 	// Check if status code is in 2xx range (200-299)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 || response.Status != "success" {
-		return &DashgramAPIError{
+		return resp, &DashgramAPIError{
 			StatusCode: resp.StatusCode,
-			Details:    response.Details,
+			Details:    resp.Details,
+		}
+	}
+
+	if out != nil {
+		if err := d.decodeResponse(respBody, out); err != nil {
+			return resp, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// send performs a single HTTP round trip and returns the response and its body
+func (d *Dashgram) send(ctx context.Context, method string, requestURL string, data any, call callOptions) (*Response, []byte, error) {
+	// Prepare request body, the pooled buffer is released when the client closes it
+	var body io.ReadCloser
+	var payload []byte
+	var contentType string
+	if multipartData, ok := data.(*multipartRequest); ok {
+		buf, formContentType, err := multipartData.encode()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal request data: %w", err)
+		}
+		body, payload, contentType = io.NopCloser(buf), buf.Bytes(), formContentType
+	} else if data != nil && d.encoder != nil {
+		encoded, err := d.encoder.Encode(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal request data: %w", err)
+		}
+		body, payload, contentType = io.NopCloser(bytes.NewReader(encoded)), encoded, d.encoder.MediaType()
+	} else if data != nil {
+		jsonBody, err := newRequestBody(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal request data: %w", err)
+		}
+		body, payload, contentType = jsonBody, jsonBody.Bytes(), "application/json"
+	}
+
+	// Create request
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+	if err != nil {
+		if body != nil {
+			body.Close()
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Body = body
+		req.ContentLength = int64(len(payload))
+	}
+
+	// Set headers
+	switch d.authScheme {
+	case authSchemeBasic:
+		req.SetBasicAuth(d.basicUsername, d.basicPassword)
+	default:
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", d.accessKey()))
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+		d.setBodyHash(req, payload)
+	}
+	if d.accept != "" {
+		req.Header.Set("Accept", d.accept)
+	}
+	for _, propagator := range d.propagators {
+		for key, value := range propagator.Extract(ctx) {
+			req.Header.Set(key, value)
 		}
 	}
+	for key, values := range call.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	// Make request
+	resp, err := d.client.Do(req)
+	if err != nil {
+		d.closeIdleConnections()
+		return nil, nil, &TransportError{Err: d.redactError(err)}
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		d.closeIdleConnections()
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
 
-	return nil
+	return &Response{StatusCode: resp.StatusCode, Headers: resp.Header}, respBody, nil
 }
@@ -2,10 +2,18 @@ package dashgram
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"runtime/pprof"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -192,6 +200,79 @@ func TestDashgram_Close(t *testing.T) {
 	}
 }
 
+func TestDashgram_CloseIsIdempotent(t *testing.T) {
+	var reports atomic.Int64
+	d := New(123, "test-key",
+		WithUseAsync(),
+		WithStatsReporter(time.Hour, func(Stats) { reports.Add(1) }),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				d.Close()
+			}()
+		}
+		wg.Wait()
+
+		// Closing an already closed client returns immediately
+		d.Close()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected concurrent Close calls to return")
+	}
+
+	if d.workerCtx.Err() == nil {
+		t.Error("expected worker context to be cancelled after Close()")
+	}
+	if count := reports.Load(); count != 1 {
+		t.Errorf("expected the final stats to be reported once, got %d", count)
+	}
+}
+
+func TestDashgram_WithAsyncWorkerName(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       []Option
+		expectedLabel string
+	}{
+		{name: "default", expectedLabel: `"worker":"dashgram-worker"`},
+		{name: "custom", options: []Option{WithAsyncWorkerName("payments-bot")}, expectedLabel: `"worker":"payments-bot"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New(123, "test-key", append(tt.options, WithNumWorkers(2))...)
+			defer d.Close()
+
+			// The workers may not have started yet
+			deadline := time.Now().Add(time.Second)
+			for {
+				var profile strings.Builder
+				if err := pprof.Lookup("goroutine").WriteTo(&profile, 1); err != nil {
+					t.Fatalf("failed to write goroutine profile: %v", err)
+				}
+				if strings.Contains(profile.String(), tt.expectedLabel) {
+					return
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("expected worker goroutines labeled %s", tt.expectedLabel)
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		})
+	}
+}
+
 func TestDashgram_StartWorker(t *testing.T) {
 	d := New(123, "test-key", WithUseAsync())
 	defer d.Close()
@@ -247,6 +328,16 @@ func TestDashgram_request(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name:     "unauthorized response",
+			endpoint: "track",
+			data:     map[string]string{"event": "test"},
+			mockResponse: &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"error","details":"unauthorized"}`)),
+			},
+			expectedError: "invalid credentials",
+		},
 		{
 			name:     "forbidden response",
 			endpoint: "track",
@@ -255,7 +346,7 @@ func TestDashgram_request(t *testing.T) {
 				StatusCode: http.StatusForbidden,
 				Body:       io.NopCloser(strings.NewReader(`{"status":"error","details":"forbidden"}`)),
 			},
-			expectedError: "invalid credentials",
+			expectedError: "forbidden",
 		},
 		{
 			name:     "API error response",
@@ -308,3 +399,782 @@ func TestDashgram_request(t *testing.T) {
 		})
 	}
 }
+
+func TestDashgram_EndpointWorkerPools(t *testing.T) {
+	var mu sync.Mutex
+	active := make(map[string]int)
+	maxActive := make(map[string]int)
+
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			endpoint := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+
+			mu.Lock()
+			active[endpoint]++
+			if active[endpoint] > maxActive[endpoint] {
+				maxActive[endpoint] = active[endpoint]
+			}
+			mu.Unlock()
+
+			// Simulate some processing time
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			active[endpoint]--
+			mu.Unlock()
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	d := New(123, "test-key",
+		WithHTTPClient(mockClient),
+		WithEndpointWorkers("track", 5),
+		WithEndpointWorkers("invited_by", 1),
+	)
+	defer d.Close()
+
+	if d.poolFor("track").size != 5 {
+		t.Errorf("expected track pool size 5, got %d", d.poolFor("track").size)
+	}
+	if d.poolFor("invited_by").size != 1 {
+		t.Errorf("expected invited_by pool size 1, got %d", d.poolFor("invited_by").size)
+	}
+	if d.poolFor("other") != d.pool {
+		t.Errorf("expected endpoints without a dedicated pool to use the default pool")
+	}
+
+	for i := 0; i < 10; i++ {
+		d.TrackEventAsync(map[string]any{"action": "test", "index": i})
+	}
+	for i := 0; i < 3; i++ {
+		d.InvitedByAsync(int64(12345+i), 67890)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if d.poolFor("track").processed.Load() == 10 && d.poolFor("invited_by").processed.Load() == 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := d.poolFor("track").processed.Load(); got != 10 {
+		t.Errorf("expected track pool to process 10 tasks, got %d", got)
+	}
+	if got := d.poolFor("invited_by").processed.Load(); got != 3 {
+		t.Errorf("expected invited_by pool to process 3 tasks, got %d", got)
+	}
+	if got := d.pool.processed.Load(); got != 0 {
+		t.Errorf("expected default pool to process 0 tasks, got %d", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxActive["track"] < 2 {
+		t.Errorf("expected track tasks to be processed concurrently, max concurrency was %d", maxActive["track"])
+	}
+	if maxActive["invited_by"] != 1 {
+		t.Errorf("expected invited_by tasks to be processed one at a time, max concurrency was %d", maxActive["invited_by"])
+	}
+}
+
+func TestDashgram_ProjectIDValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		projectID   int
+		options     []Option
+		expectedErr bool
+	}{
+		{
+			name:      "no validator",
+			projectID: 0,
+		},
+		{
+			name:      "positive project ID accepted",
+			projectID: 123,
+			options:   []Option{WithPositiveProjectID()},
+		},
+		{
+			name:        "zero project ID rejected",
+			projectID:   0,
+			options:     []Option{WithPositiveProjectID()},
+			expectedErr: true,
+		},
+		{
+			name:      "custom validator",
+			projectID: 5000,
+			options: []Option{
+				WithProjectIDValidator(func(id int) error {
+					if id >= 1000 {
+						return fmt.Errorf("project ID %d out of range", id)
+					}
+					return nil
+				}),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+
+			options := append([]Option{WithHTTPClient(helper.MockHTTPClient())}, tt.options...)
+			d := New(tt.projectID, "test-key", options...)
+			defer d.Close()
+
+			err := d.TrackEvent(TestEventData)
+
+			if !tt.expectedErr {
+				if d.InitErr() != nil {
+					t.Errorf("unexpected InitErr: %v", d.InitErr())
+				}
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var configErr *ConfigurationError
+			if !errors.As(d.InitErr(), &configErr) {
+				t.Fatalf("expected ConfigurationError from InitErr, got %v", d.InitErr())
+			}
+			if configErr.Field != "ProjectID" {
+				t.Errorf("expected Field 'ProjectID', got '%s'", configErr.Field)
+			}
+			if err != d.InitErr() {
+				t.Errorf("expected request to fail with the configuration error, got %v", err)
+			}
+			if helper.RequestCount != 0 {
+				t.Errorf("expected no HTTP requests, got %d", helper.RequestCount)
+			}
+		})
+	}
+}
+
+func TestDashgram_FailFast(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("expected New to panic")
+		}
+		if _, ok := r.(*ConfigurationError); !ok {
+			t.Errorf("expected panic with ConfigurationError, got %T", r)
+		}
+	}()
+
+	New(-1, "test-key", WithPositiveProjectID(), WithFailFast())
+}
+
+func TestDashgram_WithHTTPSOnly(t *testing.T) {
+	tests := []struct {
+		name        string
+		apiURL      string
+		expectedErr bool
+	}{
+		{
+			name:   "https accepted",
+			apiURL: "https://api.example.com/v1",
+		},
+		{
+			name:        "http rejected",
+			apiURL:      "http://api.example.com/v1",
+			expectedErr: true,
+		},
+		{
+			name:        "no scheme rejected",
+			apiURL:      "api.example.com/v1",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithAPIURL(tt.apiURL), WithHTTPSOnly())
+			defer d.Close()
+
+			err := d.TrackEvent(TestEventData)
+
+			if !tt.expectedErr {
+				if d.InitErr() != nil {
+					t.Errorf("unexpected InitErr: %v", d.InitErr())
+				}
+				if d.Validate() != nil {
+					t.Errorf("unexpected Validate error: %v", d.Validate())
+				}
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var configErr *ConfigurationError
+			if !errors.As(d.InitErr(), &configErr) {
+				t.Fatalf("expected ConfigurationError from InitErr, got %v", d.InitErr())
+			}
+			if configErr.Field != "APIURL" {
+				t.Errorf("expected Field 'APIURL', got '%s'", configErr.Field)
+			}
+			if !errors.As(d.Validate(), &configErr) {
+				t.Errorf("expected ConfigurationError from Validate, got %v", d.Validate())
+			}
+			if err != d.InitErr() {
+				t.Errorf("expected request to fail with the configuration error, got %v", err)
+			}
+			if helper.RequestCount != 0 {
+				t.Errorf("expected no HTTP requests, got %d", helper.RequestCount)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithHTTPSOnlyFailFast(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("expected New to panic")
+		}
+		if _, ok := r.(*ConfigurationError); !ok {
+			t.Errorf("expected panic with ConfigurationError, got %T", r)
+		}
+	}()
+
+	New(123, "test-key", WithAPIURL("http://api.example.com/v1"), WithHTTPSOnly(), WithFailFast())
+}
+
+func TestDashgram_WithClientTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success","details":"ok"}`))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var gotConn int
+
+	d := New(123, "test-key",
+		WithAPIURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithClientTrace(func(ctx context.Context) context.Context {
+			return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+				GotConn: func(info httptrace.GotConnInfo) {
+					mu.Lock()
+					gotConn++
+					mu.Unlock()
+				},
+			})
+		}),
+	)
+	defer d.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := d.TrackEvent(TestEventData); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gotConn != 2 {
+		t.Errorf("expected GotConn to fire once per request, got %d", gotConn)
+	}
+}
+
+func TestDashgram_WithHTTPAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		expected string
+	}{
+		{
+			name:     "bearer by default",
+			expected: "Bearer test-key",
+		},
+		{
+			name:     "basic auth",
+			options:  []Option{WithHTTPAuth("user", "secret")},
+			expected: "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authorization string
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					authorization = req.Header.Get("Authorization")
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+					}, nil
+				},
+			}
+
+			options := append([]Option{WithHTTPClient(mockClient)}, tt.options...)
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			if err := d.TrackEvent(TestEventData); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if authorization != tt.expected {
+				t.Errorf("expected Authorization header '%s', got '%s'", tt.expected, authorization)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithAccept(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		expected []string
+	}{
+		{
+			name:     "JSON by default",
+			expected: []string{"application/json"},
+		},
+		{
+			name:     "versioned format",
+			options:  []Option{WithAccept("application/vnd.dashgram.v2+json")},
+			expected: []string{"application/vnd.dashgram.v2+json"},
+		},
+		{
+			name:    "omitted",
+			options: []Option{WithAccept("")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok","project":{"id":123}}`)
+
+			options := append([]Option{WithHTTPClient(helper.MockHTTPClient())}, tt.options...)
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			d.TrackEvent(TestEventData)
+			d.GetProjectInfo(context.Background())
+
+			for _, req := range helper.RecordedRequests() {
+				accept := req.Headers.Values("Accept")
+				if len(accept) != len(tt.expected) || (len(accept) > 0 && accept[0] != tt.expected[0]) {
+					t.Errorf("expected Accept header %v on %s, got %v", tt.expected, req.URL.Path, accept)
+				}
+			}
+		})
+	}
+}
+
+func TestDashgram_TransportTimeouts(t *testing.T) {
+	t.Run("default client", func(t *testing.T) {
+		d := New(123, "test-key",
+			WithDialTimeout(2*time.Second),
+			WithResponseHeaderTimeout(15*time.Second),
+		)
+		defer d.Close()
+
+		client, ok := d.client.(*http.Client)
+		if !ok {
+			t.Fatalf("expected *http.Client, got %T", d.client)
+		}
+		if client.Timeout != 30*time.Second {
+			t.Errorf("expected total timeout to stay 30s, got %v", client.Timeout)
+		}
+
+		transport, ok := client.Transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("expected *http.Transport, got %T", client.Transport)
+		}
+		if transport.DialContext == nil {
+			t.Errorf("expected DialContext to be set")
+		}
+		if transport.TLSHandshakeTimeout != 2*time.Second {
+			t.Errorf("expected TLSHandshakeTimeout 2s, got %v", transport.TLSHandshakeTimeout)
+		}
+		if transport.ResponseHeaderTimeout != 15*time.Second {
+			t.Errorf("expected ResponseHeaderTimeout 15s, got %v", transport.ResponseHeaderTimeout)
+		}
+	})
+
+	t.Run("custom client is not mutated", func(t *testing.T) {
+		custom := &http.Client{Timeout: 60 * time.Second}
+
+		d := New(123, "test-key",
+			WithHTTPClient(custom),
+			WithResponseHeaderTimeout(5*time.Second),
+		)
+		defer d.Close()
+
+		if custom.Transport != nil {
+			t.Errorf("expected custom client transport to stay nil")
+		}
+
+		client := d.client.(*http.Client)
+		if client.Timeout != 60*time.Second {
+			t.Errorf("expected total timeout 60s, got %v", client.Timeout)
+		}
+		if transport := client.Transport.(*http.Transport); transport.ResponseHeaderTimeout != 5*time.Second {
+			t.Errorf("expected ResponseHeaderTimeout 5s, got %v", transport.ResponseHeaderTimeout)
+		}
+	})
+
+	t.Run("non-standard client is left alone", func(t *testing.T) {
+		mockClient := &mockHTTPClient{}
+
+		d := New(123, "test-key", WithHTTPClient(mockClient), WithDialTimeout(time.Second))
+		defer d.Close()
+
+		if d.client != mockClient {
+			t.Errorf("expected mock client to be kept as is")
+		}
+	})
+}
+
+func TestDashgram_WithConnectionCloseOnError(t *testing.T) {
+	tests := []struct {
+		name             string
+		options          []Option
+		expectedNewConns int64
+	}{
+		{
+			name:             "idle connections reused",
+			expectedNewConns: 2,
+		},
+		{
+			name:             "idle connections closed after an error",
+			options:          []Option{WithConnectionCloseOnError()},
+			expectedNewConns: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests, newConns atomic.Int64
+			var arrived sync.WaitGroup
+			arrived.Add(2)
+
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch requests.Add(1) {
+				case 1, 2:
+					// Hold the first requests until both arrived, so they use two connections
+					arrived.Done()
+					arrived.Wait()
+				case 3:
+					// Break the connection without responding
+					conn, _, _ := w.(http.Hijacker).Hijack()
+					conn.Close()
+					return
+				}
+				w.Write([]byte(`{"status":"success","details":"ok"}`))
+			}))
+			server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					newConns.Add(1)
+				}
+			}
+			server.Start()
+			defer server.Close()
+
+			options := append([]Option{WithAPIURL(server.URL)}, tt.options...)
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			var wg sync.WaitGroup
+			for i := 0; i < 2; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := d.TrackEvent(TestEventData); err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			var transportErr *TransportError
+			if err := d.TrackEvent(TestEventData); !errors.As(err, &transportErr) {
+				t.Fatalf("expected TransportError, got %v", err)
+			}
+			if err := d.TrackEvent(TestEventData); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if conns := newConns.Load(); conns != tt.expectedNewConns {
+				t.Errorf("expected %d connections, got %d", tt.expectedNewConns, conns)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithTimeoutPerEndpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []Option
+		expectError bool
+	}{
+		{
+			name:        "short track timeout",
+			options:     []Option{WithTimeoutPerEndpoint(map[string]time.Duration{"track": time.Millisecond})},
+			expectError: true,
+		},
+		{
+			name:    "long track timeout",
+			options: []Option{WithTimeoutPerEndpoint(map[string]time.Duration{"track": 10 * time.Second})},
+		},
+		{
+			name: "endpoint timeout overrides request timeout",
+			options: []Option{
+				WithRequestTimeout(time.Millisecond),
+				WithTimeoutPerEndpoint(map[string]time.Duration{"track": 10 * time.Second}),
+			},
+		},
+		{
+			name: "request timeout applies to other endpoints",
+			options: []Option{
+				WithRequestTimeout(time.Millisecond),
+				WithTimeoutPerEndpoint(map[string]time.Duration{"invited_by": 10 * time.Second}),
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					select {
+					case <-time.After(50 * time.Millisecond):
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+						}, nil
+					case <-req.Context().Done():
+						return nil, req.Context().Err()
+					}
+				},
+			}
+
+			options := append([]Option{WithHTTPClient(mockClient)}, tt.options...)
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			err := d.TrackEvent(TestEventData)
+
+			if tt.expectError {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("expected deadline exceeded error, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithEndpointTimeout(t *testing.T) {
+	var mu sync.Mutex
+	timeouts := make(map[string]time.Duration)
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			deadline, ok := req.Context().Deadline()
+			mu.Lock()
+			if ok {
+				endpoint := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+				timeouts[endpoint] = time.Until(deadline)
+			}
+			mu.Unlock()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	d := New(123, "test-key",
+		WithHTTPClient(mockClient),
+		WithRequestTimeout(5*time.Second),
+		WithEndpointTimeout("track", 2*time.Second),
+		WithEndpointTimeout("invited_by", 20*time.Second),
+	)
+	defer d.Close()
+
+	if err := d.TrackEvent(TestEventData); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.InvitedBy(1, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.SetUserProperties(1, map[string]any{"plan": "pro"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]time.Duration{
+		"track":           2 * time.Second,
+		"invited_by":      20 * time.Second,
+		"user_properties": 5 * time.Second,
+	}
+	for endpoint, timeout := range expected {
+		remaining, ok := timeouts[endpoint]
+		if !ok {
+			t.Errorf("expected a deadline on %s", endpoint)
+			continue
+		}
+		if remaining > timeout || remaining < timeout-time.Second {
+			t.Errorf("expected %s to time out in %v, got %v", endpoint, timeout, remaining)
+		}
+	}
+}
+
+func TestDashgram_WithEndpointTimeoutCancels(t *testing.T) {
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			select {
+			case <-time.After(50 * time.Millisecond):
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+				}, nil
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+		},
+	}
+
+	d := New(123, "test-key",
+		WithHTTPClient(mockClient),
+		WithEndpointTimeout("track", time.Millisecond),
+		WithEndpointTimeout("invited_by", 10*time.Second),
+	)
+	defer d.Close()
+
+	if err := d.TrackEvent(TestEventData); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected track to time out, got %v", err)
+	}
+	if err := d.InvitedBy(1, 2); err != nil {
+		t.Errorf("expected invited_by not to time out, got %v", err)
+	}
+}
+
+func TestDashgram_WithTokenRefreshOnUnauthorized(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []int
+		refreshErr    error
+		expectedAuth  []string
+		expectedCalls int
+		checkError    func(error) bool
+	}{
+		{
+			name:          "refreshes and retries once",
+			statuses:      []int{http.StatusUnauthorized, http.StatusOK},
+			expectedAuth:  []string{"Bearer old-key", "Bearer new-key"},
+			expectedCalls: 1,
+			checkError:    func(err error) bool { return err == nil },
+		},
+		{
+			name:          "still unauthorized after refresh",
+			statuses:      []int{http.StatusUnauthorized, http.StatusUnauthorized},
+			expectedAuth:  []string{"Bearer old-key", "Bearer new-key"},
+			expectedCalls: 1,
+			checkError: func(err error) bool {
+				var credErr *InvalidCredentialsError
+				return errors.As(err, &credErr)
+			},
+		},
+		{
+			name:          "refresh fails",
+			statuses:      []int{http.StatusUnauthorized},
+			refreshErr:    errors.New("token service down"),
+			expectedAuth:  []string{"Bearer old-key"},
+			expectedCalls: 1,
+			checkError: func(err error) bool {
+				var refreshErr *TokenRefreshError
+				return errors.As(err, &refreshErr) && err.Error() == "token refresh failed: token service down"
+			},
+		},
+		{
+			name:          "no refresh on success",
+			statuses:      []int{http.StatusOK},
+			expectedAuth:  []string{"Bearer old-key"},
+			expectedCalls: 0,
+			checkError:    func(err error) bool { return err == nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			for _, status := range tt.statuses {
+				helper.AddResponse(status, `{"status":"success","details":"ok"}`)
+			}
+
+			var calls int
+			refresher := func(ctx context.Context) (string, error) {
+				calls++
+				return "new-key", tt.refreshErr
+			}
+
+			d := New(123, "old-key",
+				WithHTTPClient(helper.MockHTTPClient()),
+				WithTokenRefreshOnUnauthorized(refresher),
+			)
+			defer d.Close()
+
+			err := d.TrackEvent(map[string]any{"action": "click"})
+			if !tt.checkError(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d refresh calls, got %d", tt.expectedCalls, calls)
+			}
+
+			requests := helper.RecordedRequests()
+			if len(requests) != len(tt.expectedAuth) {
+				t.Fatalf("expected %d requests, got %d", len(tt.expectedAuth), len(requests))
+			}
+			for i, auth := range tt.expectedAuth {
+				if got := requests[i].Headers.Get("Authorization"); got != auth {
+					t.Errorf("expected Authorization '%s' on request %d, got '%s'", auth, i, got)
+				}
+				if len(requests[i].Body) == 0 {
+					t.Errorf("expected request %d to have a body", i)
+				}
+			}
+		})
+	}
+}
+
+func TestDashgram_HTTPClient(t *testing.T) {
+	helper := NewTestHelper()
+	configured := helper.MockHTTPClient()
+
+	d := New(123, "test-key", WithHTTPClient(configured))
+	defer d.Close()
+
+	if client := d.HTTPClient(); client != configured {
+		t.Errorf("expected the client set with WithHTTPClient, got %v", client)
+	}
+
+	httpClient := &http.Client{}
+	derived := New(123, "test-key", WithHTTPClient(httpClient), WithDialTimeout(time.Second))
+	defer derived.Close()
+
+	client, ok := derived.HTTPClient().(*http.Client)
+	if !ok || client == httpClient {
+		t.Fatalf("expected a configured copy of the client, got %v", derived.HTTPClient())
+	}
+	if _, ok := client.Transport.(*http.Transport); !ok {
+		t.Errorf("expected the copy to use an *http.Transport, got %T", client.Transport)
+	}
+}
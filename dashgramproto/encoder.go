@@ -0,0 +1,60 @@
+// Package dashgramproto serializes Dashgram requests with Protobuf, for use
+// with dashgram.WithEncoder.
+//
+// It lives in its own module so the core SDK stays free of third-party
+// dependencies.
+package dashgramproto
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// MediaType is the Content-Type of Protobuf requests
+const MediaType = "application/x-protobuf"
+
+// ProtoEncoder is a dashgram.Encoder producing Protobuf. Request data that is
+// already a proto.Message, e.g. built from your own generated types, is sent
+// as is. Other data, such as dashgram.TrackEventRequest, is converted to a
+// google.protobuf.Struct holding the same fields as the JSON encoding.
+//
+// The Dashgram API must accept application/x-protobuf requests:
+//
+//	client := dashgram.New(projectID, accessKey, dashgram.WithEncoder(dashgramproto.ProtoEncoder{}))
+type ProtoEncoder struct{}
+
+// Encode serializes v to the Protobuf wire format
+func (ProtoEncoder) Encode(v any) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Marshal(m)
+	}
+
+	s, err := toStruct(v)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(s)
+}
+
+// MediaType returns application/x-protobuf
+func (ProtoEncoder) MediaType() string {
+	return MediaType
+}
+
+// toStruct converts v to a Struct through its JSON encoding, so the field
+// names match the JSON API. Numbers become doubles, as in JSON.
+func toStruct(v any) (*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("request data must encode to a JSON object: %w", err)
+	}
+	return structpb.NewStruct(fields)
+}
@@ -0,0 +1,83 @@
+package dashgramproto
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dashgram/go-dashgram"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type mockHTTPClient struct {
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	m.requests = append(m.requests, req)
+	m.bodies = append(m.bodies, body)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+	}, nil
+}
+
+func TestProtoEncoder_TrackEvent(t *testing.T) {
+	mock := &mockHTTPClient{}
+	client := dashgram.New(123, "test-key", dashgram.WithHTTPClient(mock), dashgram.WithEncoder(ProtoEncoder{}))
+	defer client.Close()
+
+	if err := client.TrackEvent(map[string]any{"action": "click", "user_id": 42}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(mock.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(mock.requests))
+	}
+	if contentType := mock.requests[0].Header.Get("Content-Type"); contentType != MediaType {
+		t.Errorf("expected Content-Type %s, got %s", MediaType, contentType)
+	}
+
+	var decoded structpb.Struct
+	if err := proto.Unmarshal(mock.bodies[0], &decoded); err != nil {
+		t.Fatalf("failed to decode request: %v", err)
+	}
+
+	fields := decoded.AsMap()
+	if fields["origin"] != "Go + Dashgram SDK" {
+		t.Errorf("expected origin 'Go + Dashgram SDK', got %v", fields["origin"])
+	}
+	updates, ok := fields["updates"].([]any)
+	if !ok || len(updates) != 1 {
+		t.Fatalf("expected 1 update, got %v", fields["updates"])
+	}
+	update := updates[0].(map[string]any)
+	if update["action"] != "click" || update["user_id"] != 42.0 {
+		t.Errorf("unexpected update: %v", update)
+	}
+}
+
+func TestProtoEncoder_Encode(t *testing.T) {
+	message := wrapperspb.String("already protobuf")
+	data, err := ProtoEncoder{}.Encode(message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded wrapperspb.StringValue
+	if err := proto.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	if decoded.Value != "already protobuf" {
+		t.Errorf("expected the message to be sent as is, got %q", decoded.Value)
+	}
+
+	if _, err := (ProtoEncoder{}).Encode([]int{1, 2}); err == nil {
+		t.Error("expected an error for data that isn't a JSON object")
+	}
+}
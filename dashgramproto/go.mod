@@ -0,0 +1,10 @@
+module github.com/dashgram/go-dashgram/dashgramproto
+
+go 1.20
+
+require (
+	github.com/dashgram/go-dashgram v0.0.0
+	google.golang.org/protobuf v1.33.0
+)
+
+replace github.com/dashgram/go-dashgram => ../
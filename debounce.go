@@ -0,0 +1,110 @@
+package dashgram
+
+import (
+	"context"
+	"time"
+)
+
+// WithDebounce collapses bursts of events, e.g. typing or scrolling: an event
+// is held for window and replaced by any later event with the same key, so
+// only the latest event of a burst is sent once no new one arrived for window.
+// Unlike dropping duplicates, the events of a burst need not be identical.
+// keyFn returns the key of an event, e.g. its user and action; events with an
+// empty key are sent immediately.
+//
+// Held events are sent through the async queue even by TrackEvent, which then
+// returns nil without waiting for the request, and replaced events are counted
+// in Stats().Debounced. TrackEventWithResponse and batches are never
+// debounced. Close sends the events still held before stopping the workers.
+func WithDebounce(keyFn func(event any) string, window time.Duration) Option {
+	return func(d *Dashgram) {
+		d.debounceKey = keyFn
+		d.debounceWindow = window
+	}
+}
+
+// debouncedEvent is the latest event held for a debounce key
+type debouncedEvent struct {
+	ctx   context.Context
+	event any
+	opts  []CallOption
+	timer *time.Timer
+}
+
+// debounce holds the event if WithDebounce applies to it, replacing the event
+// held for the same key, and reports whether it did
+func (d *Dashgram) debounce(ctx context.Context, event any, opts []CallOption) bool {
+	if d.debounceKey == nil || d.debounceWindow <= 0 {
+		return false
+	}
+	key := d.debounceKey(event)
+	if key == "" {
+		return false
+	}
+
+	d.debounceMu.Lock()
+	defer d.debounceMu.Unlock()
+	if d.debounceClosed {
+		return false
+	}
+
+	if held, ok := d.debouncePending[key]; ok {
+		// If the timer already fired, its callback is waiting for the lock
+		// and sends the replaced event right away
+		if held.timer.Stop() {
+			held.timer.Reset(d.debounceWindow)
+		}
+		held.ctx, held.event, held.opts = ctx, event, opts
+		d.stats.debounced.Add(1)
+		return true
+	}
+
+	if d.debouncePending == nil {
+		d.debouncePending = make(map[string]*debouncedEvent)
+	}
+	held := &debouncedEvent{ctx: ctx, event: event, opts: opts}
+	d.debounceWg.Add(1)
+	held.timer = time.AfterFunc(d.debounceWindow, func() {
+		defer d.debounceWg.Done()
+
+		d.debounceMu.Lock()
+		if d.debouncePending[key] == held {
+			delete(d.debouncePending, key)
+		}
+		ctx, event, opts := held.ctx, held.event, held.opts
+		d.debounceMu.Unlock()
+
+		d.sendDebounced(ctx, event, opts)
+	})
+	d.debouncePending[key] = held
+	return true
+}
+
+// sendDebounced enqueues an event once its debounce window settled
+func (d *Dashgram) sendDebounced(ctx context.Context, event any, opts []CallOption) {
+	if err := d.enqueueEvent(ctx, event, opts, nil); err != nil {
+		d.log(LogLevelError, "failed to send debounced event", "error", err)
+	}
+}
+
+// flushDebounced sends the events still held and waits for the pending
+// timers, so they are enqueued before the workers stop
+func (d *Dashgram) flushDebounced() {
+	d.debounceMu.Lock()
+	d.debounceClosed = true
+	var held []*debouncedEvent
+	for key, e := range d.debouncePending {
+		// Events whose timer already fired are sent by their callback
+		if e.timer.Stop() {
+			held = append(held, e)
+			delete(d.debouncePending, key)
+		}
+	}
+	d.debounceMu.Unlock()
+
+	for _, e := range held {
+		d.sendDebounced(e.ctx, e.event, e.opts)
+		d.debounceWg.Done()
+	}
+	d.debounceWg.Wait()
+}
@@ -0,0 +1,152 @@
+package dashgram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// debounceByUser keys events by their user and action, events without a user aren't debounced
+func debounceByUser(event any) string {
+	e, ok := event.(map[string]any)
+	if !ok || e["user_id"] == nil {
+		return ""
+	}
+	return fmt.Sprint(e["user_id"], "/", e["action"])
+}
+
+func TestDashgram_WithDebounce(t *testing.T) {
+	tests := []struct {
+		name     string
+		track    func(d *Dashgram) error
+		expected []string
+	}{
+		{
+			name: "same key sends latest",
+			track: func(d *Dashgram) error {
+				for _, query := range []string{"d", "da", "das"} {
+					if err := d.TrackEvent(map[string]any{"user_id": 42, "action": "typing", "query": query}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			expected: []string{"das"},
+		},
+		{
+			name: "distinct keys send separately",
+			track: func(d *Dashgram) error {
+				if err := d.TrackEvent(map[string]any{"user_id": 42, "action": "typing", "query": "a"}); err != nil {
+					return err
+				}
+				return d.TrackEvent(map[string]any{"user_id": 43, "action": "typing", "query": "b"})
+			},
+			expected: []string{"a", "b"},
+		},
+		{
+			name: "empty key is not held",
+			track: func(d *Dashgram) error {
+				return d.TrackEvent(map[string]any{"action": "typing", "query": "a"})
+			},
+			expected: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			for range tt.expected {
+				helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+			}
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithDebounce(debounceByUser, 50*time.Millisecond))
+			defer d.Close()
+
+			if err := tt.track(d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !helper.WaitForRequests(len(tt.expected), time.Second) {
+				t.Fatalf("expected %d requests, got %d", len(tt.expected), helper.RequestCount)
+			}
+
+			// Give a wrongly held event time to be sent
+			time.Sleep(100 * time.Millisecond)
+			calls := helper.RecordedCalls()
+			if len(calls) != len(tt.expected) {
+				t.Fatalf("expected %d requests, got %d", len(tt.expected), len(calls))
+			}
+
+			var queries []string
+			for _, call := range calls {
+				body := call.Body.(map[string]any)
+				update := body["updates"].([]any)[0].(map[string]any)
+				queries = append(queries, update["query"].(string))
+			}
+			sort.Strings(queries)
+			if fmt.Sprint(queries) != fmt.Sprint(tt.expected) {
+				t.Errorf("expected events %v, got %v", tt.expected, queries)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithDebounceWaitsForWindow(t *testing.T) {
+	var requests atomic.Int64
+	d := New(123, "test-key", WithHTTPClient(countingClient(&requests)), WithUseAsync(), WithDebounce(debounceByUser, 200*time.Millisecond))
+	defer d.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := d.TrackEventAsyncWithContext(context.Background(), map[string]any{"user_id": 42, "action": "scroll", "offset": i}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if sent := requests.Load(); sent != 0 {
+		t.Errorf("expected no request before the window settles, got %d", sent)
+	}
+	waitFor(t, func() bool { return requests.Load() == 1 })
+
+	if debounced := d.Stats().Debounced; debounced != 2 {
+		t.Errorf("expected 2 debounced events, got %d", debounced)
+	}
+}
+
+func TestDashgram_WithDebounceFlushesOnClose(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithDebounce(debounceByUser, time.Hour))
+
+	for _, query := range []string{"a", "ab"} {
+		if err := d.TrackEvent(map[string]any{"user_id": 42, "action": "typing", "query": query}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	d.Close()
+
+	calls := helper.RecordedCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(calls))
+	}
+	update := calls[0].Body.(map[string]any)["updates"].([]any)[0].(map[string]any)
+	if update["query"] != "ab" {
+		t.Errorf("expected query ab, got %v", update["query"])
+	}
+
+	// Events tracked after Close aren't held
+	if err := d.TrackEvent(map[string]any{"user_id": 42, "action": "typing", "query": "abc"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count := len(helper.RecordedCalls()); count != 2 {
+		t.Errorf("expected the event to be sent right away, got %d requests", count)
+	}
+	if debounced := d.Stats().Debounced; debounced != 1 {
+		t.Errorf("expected 1 debounced event, got %d", debounced)
+	}
+}
@@ -0,0 +1,116 @@
+package dashgram
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Encoder serializes request bodies, e.g. to send Protobuf instead of JSON.
+// Responses are still expected to be JSON.
+type Encoder interface {
+	// Encode serializes the request data, such as a TrackEventRequest
+	Encode(v any) ([]byte, error)
+	// MediaType is the Content-Type of the encoded requests
+	MediaType() string
+}
+
+// WithEncoder serializes request bodies with enc instead of JSON, see the
+// dashgramproto module for Protobuf. The Dashgram API must accept the media
+// type of the encoder, or every request will be rejected. The payload part of
+// TrackEventMultipart requests is always JSON.
+func WithEncoder(enc Encoder) Option {
+	return func(d *Dashgram) {
+		d.encoder = enc
+	}
+}
+
+// WithFieldNames renames top-level JSON keys of request bodies, e.g.
+// {"updates": "events", "invited_by": "referrer"} for a self-hosted backend
+// with a different schema. It applies to every request, such as
+// TrackEventRequest and InvitedByRequest; nested keys, like those of the
+// events themselves, are left untouched. It has no effect with WithEncoder.
+func WithFieldNames(names map[string]string) Option {
+	return func(d *Dashgram) {
+		d.fieldNames = names
+	}
+}
+
+// WithJSONNumbersAsStrings decodes the numbers of untyped response values,
+// such as map[string]any, as json.Number strings instead of float64, so 64-bit
+// IDs above 2^53 keep their precision. Events are never decoded to float64,
+// whatever this option: raw JSON events are sent as is, and the options that
+// rewrite them, like WithScrubFields, keep their numbers exact.
+func WithJSONNumbersAsStrings() Option {
+	return func(d *Dashgram) {
+		d.useNumber = true
+	}
+}
+
+// decodeResponse decodes a JSON response body, see WithJSONNumbersAsStrings
+func (d *Dashgram) decodeResponse(data []byte, out any) error {
+	if !d.useNumber {
+		return json.Unmarshal(data, out)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(out)
+}
+
+// renamingEncoder is the JSON encoder used with WithFieldNames
+type renamingEncoder struct {
+	names map[string]string
+}
+
+func (e renamingEncoder) Encode(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return renameKeys(data, e.names)
+}
+
+func (e renamingEncoder) MediaType() string {
+	return "application/json"
+}
+
+// renameKeys renames the keys of a JSON object, keeping their order. Other
+// JSON values are returned as is.
+func renameKeys(data []byte, names map[string]string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return data, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+		if name, ok := names[key]; ok {
+			key = name
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,233 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// prefixEncoder encodes requests as JSON behind a fixed prefix
+type prefixEncoder struct {
+	err error
+}
+
+func (e prefixEncoder) Encode(v any) ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	data, err := json.Marshal(v)
+	return append([]byte("encoded:"), data...), err
+}
+
+func (e prefixEncoder) MediaType() string {
+	return "application/x-test"
+}
+
+func TestDashgram_WithEncoder(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithEncoder(prefixEncoder{}))
+	defer d.Close()
+
+	if err := d.TrackEvent(map[string]any{"action": "click"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := helper.LastRequest()
+	if contentType := req.Headers.Get("Content-Type"); contentType != "application/x-test" {
+		t.Errorf("expected Content-Type 'application/x-test', got '%s'", contentType)
+	}
+	expected := `encoded:{"updates":[{"action":"click"}],"origin":"Go + Dashgram SDK"}`
+	if string(req.Body) != expected {
+		t.Errorf("expected body %s, got %s", expected, req.Body)
+	}
+}
+
+func TestDashgram_WithEncoderError(t *testing.T) {
+	helper := NewTestHelper()
+	encodeErr := errors.New("unsupported type")
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithEncoder(prefixEncoder{err: encodeErr}))
+	defer d.Close()
+
+	err := d.TrackEvent(map[string]any{"action": "click"})
+	if !errors.Is(err, encodeErr) {
+		t.Fatalf("expected the encoder error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "failed to marshal request data") {
+		t.Errorf("expected a marshal error, got %v", err)
+	}
+	if helper.RequestCount != 0 {
+		t.Errorf("expected no request, got %d", helper.RequestCount)
+	}
+}
+
+func TestDashgram_WithEncoderSkipsBodylessRequests(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithEncoder(prefixEncoder{}))
+	defer d.Close()
+
+	if err := d.DeleteUserData(context.Background(), 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := helper.LastRequest()
+	if len(req.Body) != 0 || req.Headers.Get("Content-Type") != "" {
+		t.Errorf("expected no body and no Content-Type, got %q with %q", req.Body, req.Headers.Get("Content-Type"))
+	}
+}
+
+func TestDashgram_WithFieldNames(t *testing.T) {
+	names := map[string]string{"updates": "events", "invited_by": "referrer"}
+
+	tests := []struct {
+		name         string
+		call         func(d *Dashgram) error
+		expectedBody string
+	}{
+		{
+			name:         "track event",
+			call:         func(d *Dashgram) error { return d.TrackEvent(map[string]any{"action": "click", "updates": 1}) },
+			expectedBody: `{"events":[{"action":"click","updates":1}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:         "invited by",
+			call:         func(d *Dashgram) error { return d.InvitedBy(1, 2) },
+			expectedBody: `{"user_id":1,"referrer":2,"origin":"Go + Dashgram SDK"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithFieldNames(names))
+			defer d.Close()
+
+			if err := tt.call(d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			req := helper.LastRequest()
+			if string(req.Body) != tt.expectedBody {
+				t.Errorf("expected body %s, got %s", tt.expectedBody, req.Body)
+			}
+			if contentType := req.Headers.Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("expected Content-Type 'application/json', got '%s'", contentType)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithFieldNamesAndEncoder(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithFieldNames(map[string]string{"updates": "events"}),
+		WithEncoder(prefixEncoder{}),
+	)
+	defer d.Close()
+
+	d.TrackEvent(map[string]any{"action": "click"})
+
+	expected := `encoded:{"updates":[{"action":"click"}],"origin":"Go + Dashgram SDK"}`
+	if body := string(helper.LastRequest().Body); body != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+}
+
+func TestRenameKeys(t *testing.T) {
+	names := map[string]string{"a": "x", "b": `q"uote`}
+
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{name: "keeps order", data: `{"c":1,"a":{"a":2},"b":[3]}`, expected: `{"c":1,"x":{"a":2},"q\"uote":[3]}`},
+		{name: "empty object", data: `{}`, expected: `{}`},
+		{name: "not an object", data: `[{"a":1}]`, expected: `[{"a":1}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renameKeys([]byte(tt.data), names)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithJSONNumbersAsStrings(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		expected any
+	}{
+		{
+			name:     "float64 by default",
+			expected: float64(9007199254740992),
+		},
+		{
+			name:     "json.Number",
+			options:  []Option{WithJSONNumbersAsStrings()},
+			expected: json.Number("9007199254740993"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok","user":{"id":9007199254740993}}`)
+
+			options := append([]Option{WithHTTPClient(helper.MockHTTPClient())}, tt.options...)
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			var response struct {
+				User map[string]any `json:"user"`
+			}
+			if _, err := d.do(context.Background(), http.MethodGet, "users/9007199254740993", nil, nil, &response); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if id := response.User["id"]; id != tt.expected {
+				t.Errorf("expected user ID %v (%T), got %v (%T)", tt.expected, tt.expected, id, id)
+			}
+		})
+	}
+}
+
+func TestDashgram_LargeUserIDRoundTrip(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	// Scrubbing decodes and re-encodes the event
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithJSONNumbersAsStrings(),
+		WithScrubFields("text"),
+	)
+	defer d.Close()
+
+	if err := d.TrackEventJSON([]byte(`{"text":"secret","user_id":9007199254740993}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"updates":[{"text":"[redacted]","user_id":9007199254740993}],"origin":"Go + Dashgram SDK"}`
+	if body := string(helper.LastRequest().Body); body != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+}
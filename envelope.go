@@ -0,0 +1,109 @@
+package dashgram
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// EventEnvelope wraps an event with metadata, so the metadata doesn't have to
+// be encoded inside the event itself
+type EventEnvelope struct {
+	// EventID identifies the event, sent as "_event_id". A random ID is
+	// generated if it is empty.
+	EventID string
+	// SendAt is when the event happened, sent as "_send_at" and encoded as
+	// set by WithTimeEncoding. It is omitted if zero.
+	SendAt time.Time
+	// Event is the event payload, which must encode to a JSON object
+	Event any
+	// Tags are added as top-level fields of the event. Fields of the event
+	// take precedence over tags with the same name.
+	Tags map[string]string
+}
+
+// TrackEventEnvelope tracks the event of the envelope with its metadata
+// merged as top-level fields:
+//
+//	{"action": "purchase", "plan": "pro", "_event_id": "5f0c...", "_send_at": "2024-05-01T12:00:00Z"}
+func (d *Dashgram) TrackEventEnvelope(envelope EventEnvelope, opts ...CallOption) error {
+	return d.TrackEventEnvelopeWithContext(context.Background(), envelope, opts...)
+}
+
+// TrackEventEnvelopeWithContext is TrackEventEnvelope with a context
+func (d *Dashgram) TrackEventEnvelopeWithContext(ctx context.Context, envelope EventEnvelope, opts ...CallOption) error {
+	event, err := envelope.merge()
+	if err != nil {
+		return err
+	}
+
+	return d.TrackEventWithContext(ctx, event, opts...)
+}
+
+// merge returns a copy of the event with the metadata and tags added
+func (e EventEnvelope) merge() (map[string]any, error) {
+	if e.Event == nil {
+		return nil, &ValidationError{Field: "Event", Message: "must not be nil"}
+	}
+
+	fields, err := eventFields(e.Event)
+	if err != nil {
+		return nil, err
+	}
+
+	event := make(map[string]any, len(fields)+len(e.Tags)+2)
+	for key, value := range e.Tags {
+		event[key] = value
+	}
+	for key, value := range fields {
+		event[key] = value
+	}
+
+	event["_event_id"] = e.EventID
+	if e.EventID == "" {
+		if event["_event_id"], err = newEventID(); err != nil {
+			return nil, err
+		}
+	}
+	if !e.SendAt.IsZero() {
+		event["_send_at"] = e.SendAt
+	}
+	return event, nil
+}
+
+// eventFields returns the top-level fields of an event, decoding it from
+// JSON unless it is a map
+func eventFields(event any) (map[string]any, error) {
+	if m, ok := event.(map[string]any); ok {
+		return m, nil
+	}
+
+	raw, ok := event.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(event); err != nil {
+			return nil, &ValidationError{Field: "Event", Message: "must be JSON-serializable: " + err.Error()}
+		}
+	}
+
+	// Decode numbers as is, so large IDs keep their precision
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil || fields == nil {
+		return nil, &ValidationError{Field: "Event", Message: "must encode to a JSON object"}
+	}
+	return fields, nil
+}
+
+// newEventID returns a random 128-bit event ID in hex
+func newEventID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id[:]), nil
+}
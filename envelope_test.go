@@ -0,0 +1,125 @@
+package dashgram
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDashgram_TrackEventEnvelope(t *testing.T) {
+	sendAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		envelope      EventEnvelope
+		options       []Option
+		expected      string
+		expectedField string
+	}{
+		{
+			name: "map event with metadata",
+			envelope: EventEnvelope{
+				EventID: "evt-1",
+				SendAt:  sendAt,
+				Event:   map[string]any{"action": "purchase", "user_id": 42},
+				Tags:    map[string]string{"plan": "pro"},
+			},
+			expected: `{"updates":[{"_event_id":"evt-1","_send_at":"2024-05-01T12:00:00Z","action":"purchase","plan":"pro","user_id":42}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name: "struct event without send time",
+			envelope: EventEnvelope{
+				EventID: "evt-2",
+				Event: struct {
+					Action string `json:"action"`
+					UserID int64  `json:"user_id"`
+				}{Action: "open", UserID: 9007199254740993},
+			},
+			expected: `{"updates":[{"_event_id":"evt-2","action":"open","user_id":9007199254740993}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name: "event fields take precedence over tags",
+			envelope: EventEnvelope{
+				EventID: "evt-3",
+				Event:   json.RawMessage(`{"action":"open","source":"menu"}`),
+				Tags:    map[string]string{"source": "tag", "env": "prod"},
+			},
+			expected: `{"updates":[{"_event_id":"evt-3","action":"open","env":"prod","source":"menu"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name: "send time encoding",
+			envelope: EventEnvelope{
+				EventID: "evt-4",
+				SendAt:  sendAt,
+				Event:   map[string]any{"action": "open"},
+			},
+			options:  []Option{WithTimeEncoding(TimeEncodingUnixSeconds)},
+			expected: `{"updates":[{"_event_id":"evt-4","_send_at":1714564800,"action":"open"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "nil event",
+			envelope:      EventEnvelope{EventID: "evt-5"},
+			expectedField: "Event",
+		},
+		{
+			name:          "event not an object",
+			envelope:      EventEnvelope{Event: []string{"open"}},
+			expectedField: "Event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			options := append([]Option{WithHTTPClient(helper.MockHTTPClient())}, tt.options...)
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			err := d.TrackEventEnvelope(tt.envelope)
+
+			if tt.expectedField != "" {
+				assertValidationError(t, err, tt.expectedField, helper)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if body := string(helper.LastRequest().Body); body != tt.expected {
+				t.Errorf("expected body %s, got %s", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackEventEnvelopeGeneratesEventID(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	envelope := EventEnvelope{Event: map[string]any{"action": "open"}}
+	for i := 0; i < 2; i++ {
+		if err := d.TrackEventEnvelope(envelope); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var ids []string
+	for _, call := range helper.RecordedCalls() {
+		update := call.Body.(map[string]any)["updates"].([]any)[0].(map[string]any)
+		id, _ := update["_event_id"].(string)
+		if len(id) != 32 {
+			t.Errorf("expected a 32 character event ID, got %q", id)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 2 || ids[0] == ids[1] {
+		t.Errorf("expected two distinct event IDs, got %v", ids)
+	}
+}
@@ -1,6 +1,21 @@
 package dashgram
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNilEvent is wrapped by the ValidationError returned when a nil event is
+// passed to a tracking method, check for it with errors.Is
+var ErrNilEvent = errors.New("nil event")
+
+// ErrRedirect is wrapped by the TransportError returned when a redirect is
+// refused by NoFollow or FollowOnce. Such requests aren't retried.
+var ErrRedirect = errors.New("redirect not followed")
+
+// ErrTaskDropped is wrapped by the error reported by InvitedByAsyncWithResult
+// when its task is dropped instead of being sent, e.g. after Close
+var ErrTaskDropped = errors.New("async task dropped")
 
 // InvalidCredentialsError represents an invalid credentials error
 type InvalidCredentialsError struct{}
@@ -9,6 +24,96 @@ func (e *InvalidCredentialsError) Error() string {
 	return "invalid credentials"
 }
 
+// ForbiddenError represents an error returned when the credentials are valid
+// but lack the permissions required for the request
+type ForbiddenError struct{}
+
+func (e *ForbiddenError) Error() string {
+	return "forbidden"
+}
+
+// TransportError represents a failure to reach the Dashgram API, such as a
+// DNS, connection or timeout error
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("request failed: %v", e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// UserNotFoundError is returned when the API doesn't know the user
+type UserNotFoundError struct {
+	UserID int64
+}
+
+func (e *UserNotFoundError) Error() string {
+	return fmt.Sprintf("user %d not found", e.UserID)
+}
+
+// TokenRefreshError represents a failure to refresh the access key after a 401
+type TokenRefreshError struct {
+	Err error
+}
+
+func (e *TokenRefreshError) Error() string {
+	return fmt.Sprintf("token refresh failed: %v", e.Err)
+}
+
+func (e *TokenRefreshError) Unwrap() error {
+	return e.Err
+}
+
+// ImportChunkError is the error of a chunk of an import, holding the pairs[Start:End]
+type ImportChunkError struct {
+	Chunk int
+	Start int
+	End   int
+	Err   error
+}
+
+func (e *ImportChunkError) Error() string {
+	return fmt.Sprintf("chunk %d (pairs %d-%d): %v", e.Chunk, e.Start, e.End, e.Err)
+}
+
+func (e *ImportChunkError) Unwrap() error {
+	return e.Err
+}
+
+// ImportError lists the chunks of an import that failed
+type ImportError struct {
+	Chunks int
+	Failed []*ImportChunkError
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("import failed for %d of %d chunks, first: %v", len(e.Failed), e.Chunks, e.Failed[0])
+}
+
+func (e *ImportError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, chunkErr := range e.Failed {
+		errs[i] = chunkErr
+	}
+	return errs
+}
+
+// ShutdownError reports the async tasks that didn't drain cleanly on Close
+type ShutdownError struct {
+	// Failed is the number of tasks whose request failed during Close
+	Failed int64
+	// Pending is the number of tasks still queued once the workers stopped
+	Pending int
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("shutdown: %d async tasks failed, %d left undelivered", e.Failed, e.Pending)
+}
+
 // DashgramAPIError represents an API error from Dashgram
 type DashgramAPIError struct {
 	StatusCode int
@@ -18,3 +123,58 @@ type DashgramAPIError struct {
 func (e *DashgramAPIError) Error() string {
 	return fmt.Sprintf("dashgram API error (status: %d): %s", e.StatusCode, e.Details)
 }
+
+// Is reports whether target is a StatusRangeError containing the status code
+func (e *DashgramAPIError) Is(target error) bool {
+	r, ok := target.(*statusRangeError)
+	return ok && r.min <= e.StatusCode && e.StatusCode <= r.max
+}
+
+// statusRangeError matches the DashgramAPIErrors of a range of status codes
+type statusRangeError struct {
+	min int
+	max int
+}
+
+func (e *statusRangeError) Error() string {
+	return fmt.Sprintf("dashgram API error (status: %d-%d)", e.min, e.max)
+}
+
+// StatusRangeError returns a target for errors.Is matching any DashgramAPIError
+// whose status code is between min and max, inclusive:
+//
+//	if errors.Is(err, dashgram.StatusRangeError(500, 599)) {
+//		// server-side error
+//	}
+func StatusRangeError(min, max int) error {
+	return &statusRangeError{min: min, max: max}
+}
+
+// ConfigurationError represents an invalid client configuration
+type ConfigurationError struct {
+	Field string
+	Err   error
+}
+
+func (e *ConfigurationError) Error() string {
+	return fmt.Sprintf("invalid configuration (%s): %v", e.Field, e.Err)
+}
+
+func (e *ConfigurationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError represents invalid arguments rejected before any request is made
+type ValidationError struct {
+	Field   string
+	Message string
+	Err     error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
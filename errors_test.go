@@ -1,6 +1,8 @@
 package dashgram
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -13,6 +15,28 @@ func TestInvalidCredentialsError(t *testing.T) {
 	}
 }
 
+func TestForbiddenError(t *testing.T) {
+	err := &ForbiddenError{}
+
+	expected := "forbidden"
+	if err.Error() != expected {
+		t.Errorf("expected error message '%s', got '%s'", expected, err.Error())
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := &TransportError{Err: cause}
+
+	expected := "request failed: connection refused"
+	if err.Error() != expected {
+		t.Errorf("expected error message '%s', got '%s'", expected, err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expected TransportError to unwrap to its cause")
+	}
+}
+
 func TestDashgramAPIError(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -54,6 +78,28 @@ func TestDashgramAPIError(t *testing.T) {
 	}
 }
 
+func TestConfigurationError(t *testing.T) {
+	cause := errors.New("project ID must be positive, got 0")
+	err := &ConfigurationError{Field: "ProjectID", Err: cause}
+
+	expected := "invalid configuration (ProjectID): project ID must be positive, got 0"
+	if err.Error() != expected {
+		t.Errorf("expected error message '%s', got '%s'", expected, err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expected ConfigurationError to unwrap to its cause")
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{Field: "userID", Message: "must be positive"}
+
+	expected := "invalid userID: must be positive"
+	if err.Error() != expected {
+		t.Errorf("expected error message '%s', got '%s'", expected, err.Error())
+	}
+}
+
 func TestErrorTypeAssertions(t *testing.T) {
 	// Test InvalidCredentialsError type assertion
 	var err error = &InvalidCredentialsError{}
@@ -62,6 +108,13 @@ func TestErrorTypeAssertions(t *testing.T) {
 		t.Errorf("failed to assert InvalidCredentialsError type")
 	}
 
+	// Test ForbiddenError type assertion
+	var forbiddenErr error = &ForbiddenError{}
+
+	if _, ok := forbiddenErr.(*ForbiddenError); !ok {
+		t.Errorf("failed to assert ForbiddenError type")
+	}
+
 	// Test DashgramAPIError type assertion
 	var apiErr error = &DashgramAPIError{
 		StatusCode: 404,
@@ -72,3 +125,60 @@ func TestErrorTypeAssertions(t *testing.T) {
 		t.Errorf("failed to assert DashgramAPIError type")
 	}
 }
+
+func TestTokenRefreshError(t *testing.T) {
+	cause := errors.New("token service down")
+	err := &TokenRefreshError{Err: cause}
+
+	expected := "token refresh failed: token service down"
+	if err.Error() != expected {
+		t.Errorf("expected error message '%s', got '%s'", expected, err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expected TokenRefreshError to unwrap to its cause")
+	}
+}
+
+func TestValidationErrorUnwrap(t *testing.T) {
+	err := &ValidationError{Field: "event", Message: "must not be nil", Err: ErrNilEvent}
+
+	if !errors.Is(err, ErrNilEvent) {
+		t.Errorf("expected ValidationError to unwrap to ErrNilEvent")
+	}
+	if (&ValidationError{Field: "event", Message: "must not be empty"}).Unwrap() != nil {
+		t.Errorf("expected ValidationError without cause to unwrap to nil")
+	}
+}
+
+func TestStatusRangeError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		target   error
+		expected bool
+	}{
+		{name: "in range", err: &DashgramAPIError{StatusCode: 503}, target: StatusRangeError(500, 599), expected: true},
+		{name: "lower bound", err: &DashgramAPIError{StatusCode: 500}, target: StatusRangeError(500, 599), expected: true},
+		{name: "upper bound", err: &DashgramAPIError{StatusCode: 599}, target: StatusRangeError(500, 599), expected: true},
+		{name: "out of range", err: &DashgramAPIError{StatusCode: 404}, target: StatusRangeError(500, 599), expected: false},
+		{name: "wrapped", err: fmt.Errorf("tracking failed: %w", &DashgramAPIError{StatusCode: 429}), target: StatusRangeError(400, 499), expected: true},
+		{name: "other error", err: &TransportError{Err: errors.New("timeout")}, target: StatusRangeError(500, 599), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.expected {
+				t.Errorf("expected errors.Is to return %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUserNotFoundError(t *testing.T) {
+	err := &UserNotFoundError{UserID: 12345}
+
+	expected := "user 12345 not found"
+	if err.Error() != expected {
+		t.Errorf("expected error message '%s', got '%s'", expected, err.Error())
+	}
+}
@@ -0,0 +1,97 @@
+package dashgram
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Granularity is the size of the time buckets of a StatsQuery
+type Granularity string
+
+const (
+	// GranularityHour counts events per hour
+	GranularityHour Granularity = "hour"
+	// GranularityDay counts events per day
+	GranularityDay Granularity = "day"
+)
+
+// StatsQuery selects the events counted by QueryEventCounts
+type StatsQuery struct {
+	// From and To bound the time range, From inclusive and To exclusive
+	From time.Time
+	To   time.Time
+	// Granularity defaults to GranularityDay
+	Granularity Granularity
+	// Event optionally restricts the counts to events with this name
+	Event string
+}
+
+// StatsBucket is the number of events in the time bucket starting at Start
+type StatsBucket struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+}
+
+// StatsResult holds the event counts of a StatsQuery
+type StatsResult struct {
+	Granularity Granularity   `json:"granularity"`
+	Total       int64         `json:"total"`
+	Buckets     []StatsBucket `json:"buckets"`
+}
+
+// validate checks the query and fills in the default granularity
+func (q *StatsQuery) validate() error {
+	if q.From.IsZero() {
+		return &ValidationError{Field: "From", Message: "must be set"}
+	}
+	if !q.To.After(q.From) {
+		return &ValidationError{Field: "To", Message: "must be after From"}
+	}
+
+	switch q.Granularity {
+	case "":
+		q.Granularity = GranularityDay
+	case GranularityHour, GranularityDay:
+	default:
+		return &ValidationError{Field: "Granularity", Message: "must be hour or day"}
+	}
+	return nil
+}
+
+// values encodes the query as URL query parameters
+func (q StatsQuery) values() url.Values {
+	values := url.Values{}
+	values.Set("from", q.From.UTC().Format(time.RFC3339))
+	values.Set("to", q.To.UTC().Format(time.RFC3339))
+	values.Set("granularity", string(q.Granularity))
+	if q.Event != "" {
+		values.Set("event", q.Event)
+	}
+	return values
+}
+
+// QueryEventCounts returns the number of tracked events over a time range,
+// bucketed by hour or day. It always runs synchronously.
+func (d *Dashgram) QueryEventCounts(ctx context.Context, q StatsQuery) (*StatsResult, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Result *StatsResult `json:"result"`
+	}
+	if _, err := d.do(ctx, http.MethodGet, "stats/events", q.values(), nil, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Result == nil {
+		return nil, &DashgramAPIError{
+			StatusCode: http.StatusOK,
+			Details:    "missing result in response",
+		}
+	}
+
+	return response.Result, nil
+}
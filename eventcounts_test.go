@@ -0,0 +1,114 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDashgram_QueryEventCounts(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok","result":{"granularity":"hour","total":15,"buckets":[{"start":"2024-05-01T12:00:00Z","count":10},{"start":"2024-05-01T13:00:00Z","count":5}]}}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	from := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	result, err := d.QueryEventCounts(context.Background(), StatsQuery{
+		From:        from,
+		To:          from.Add(2 * time.Hour),
+		Granularity: GranularityHour,
+		Event:       "purchase",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Granularity != GranularityHour || result.Total != 15 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.Buckets) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(result.Buckets))
+	}
+	if !result.Buckets[1].Start.Equal(from.Add(time.Hour)) || result.Buckets[1].Count != 5 {
+		t.Errorf("unexpected second bucket: %+v", result.Buckets[1])
+	}
+
+	req := helper.LastRequest()
+	if req.Method != http.MethodGet {
+		t.Errorf("expected method GET, got %s", req.Method)
+	}
+	if req.URL.Path != "/v1/123/stats/events" {
+		t.Errorf("expected path '/v1/123/stats/events', got '%s'", req.URL.Path)
+	}
+	query := req.URL.Query()
+	expected := map[string]string{
+		"from":        "2024-05-01T12:00:00Z",
+		"to":          "2024-05-01T14:00:00Z",
+		"granularity": "hour",
+		"event":       "purchase",
+	}
+	for key, value := range expected {
+		if got := query.Get(key); got != value {
+			t.Errorf("expected query %s=%s, got '%s'", key, value, got)
+		}
+	}
+}
+
+func TestDashgram_QueryEventCountsValidation(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query StatsQuery
+		field string
+	}{
+		{name: "missing from", query: StatsQuery{To: from}, field: "From"},
+		{name: "to before from", query: StatsQuery{From: from, To: from.Add(-time.Hour)}, field: "To"},
+		{name: "unknown granularity", query: StatsQuery{From: from, To: from.Add(time.Hour), Granularity: "week"}, field: "Granularity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			_, err := d.QueryEventCounts(context.Background(), tt.query)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("expected field '%s', got '%s'", tt.field, validationErr.Field)
+			}
+			if helper.RequestCount != 0 {
+				t.Errorf("expected no requests, got %d", helper.RequestCount)
+			}
+		})
+	}
+}
+
+func TestDashgram_QueryEventCountsDefaultGranularity(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok","result":{"granularity":"day","total":0,"buckets":[]}}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := d.QueryEventCounts(context.Background(), StatsQuery{From: from, To: from.AddDate(0, 0, 7)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	query := helper.LastRequest().URL.Query()
+	if got := query.Get("granularity"); got != "day" {
+		t.Errorf("expected granularity 'day', got '%s'", got)
+	}
+	if query.Has("event") {
+		t.Errorf("expected no event filter, got '%s'", query.Get("event"))
+	}
+}
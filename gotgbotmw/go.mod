@@ -0,0 +1,10 @@
+module github.com/dashgram/go-dashgram/gotgbotmw
+
+go 1.20
+
+require (
+	github.com/PaulSonOfLars/gotgbot/v2 v2.0.0-rc.30
+	github.com/dashgram/go-dashgram v0.0.0
+)
+
+replace github.com/dashgram/go-dashgram => ../
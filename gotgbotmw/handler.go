@@ -0,0 +1,66 @@
+// Package gotgbotmw tracks updates processed by gotgbot v2 dispatchers with Dashgram.
+//
+// It lives in its own module so the core SDK stays free of third-party
+// dependencies.
+package gotgbotmw
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/dashgram/go-dashgram"
+)
+
+// TrackingGroup is the dispatcher handler group used by Register. It is the
+// lowest possible group, so updates are tracked before any user handler runs.
+const TrackingGroup = math.MinInt32
+
+// Handler is an ext.Handler that sends every update to Dashgram asynchronously
+type Handler struct {
+	client *dashgram.Dashgram
+}
+
+// NewHandler creates a new tracking handler for the given Dashgram client
+func NewHandler(client *dashgram.Dashgram) *Handler {
+	return &Handler{client: client}
+}
+
+// Register adds a tracking handler to the dispatcher in TrackingGroup
+func Register(dispatcher *ext.Dispatcher, client *dashgram.Dashgram) {
+	dispatcher.AddHandlerToGroup(NewHandler(client), TrackingGroup)
+}
+
+// CheckUpdate matches every update
+func (h *Handler) CheckUpdate(b *gotgbot.Bot, ctx *ext.Context) bool {
+	return true
+}
+
+// HandleUpdate enqueues the update JSON for tracking. It always returns nil so
+// the dispatcher moves on to the user handlers, whatever happens to tracking.
+func (h *Handler) HandleUpdate(b *gotgbot.Bot, ctx *ext.Context) (err error) {
+	defer func() {
+		// Tracking must never break update processing
+		if r := recover(); r != nil {
+			err = nil
+		}
+	}()
+
+	if ctx == nil || ctx.Update == nil {
+		return nil
+	}
+
+	data, marshalErr := json.Marshal(ctx.Update)
+	if marshalErr != nil {
+		return nil
+	}
+
+	h.client.TrackEventAsync(json.RawMessage(data))
+	return nil
+}
+
+// Name returns the handler name
+func (h *Handler) Name() string {
+	return "dashgram"
+}
@@ -0,0 +1,96 @@
+package gotgbotmw
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/dashgram/go-dashgram"
+)
+
+// Mock HTTP client for testing
+type mockHTTPClient struct {
+	mu     sync.Mutex
+	bodies [][]byte
+	status int
+}
+
+func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+
+	m.mu.Lock()
+	m.bodies = append(m.bodies, body)
+	m.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: m.status,
+		Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+	}, nil
+}
+
+func (m *mockHTTPClient) waitForRequests(expected int, timeout time.Duration) [][]byte {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		m.mu.Lock()
+		if len(m.bodies) >= expected {
+			bodies := m.bodies
+			m.mu.Unlock()
+			return bodies
+		}
+		m.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
+
+func TestHandler_HandleUpdate(t *testing.T) {
+	mockClient := &mockHTTPClient{status: http.StatusOK}
+	client := dashgram.New(123, "test-key", dashgram.WithHTTPClient(mockClient))
+	defer client.Close()
+
+	h := NewHandler(client)
+	ctx := &ext.Context{Update: &gotgbot.Update{UpdateId: 42}}
+
+	if !h.CheckUpdate(nil, ctx) {
+		t.Errorf("expected handler to match every update")
+	}
+	if err := h.HandleUpdate(nil, ctx); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+
+	bodies := mockClient.waitForRequests(1, time.Second)
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 tracked update, got %d", len(bodies))
+	}
+
+	var request struct {
+		Updates []map[string]any `json:"updates"`
+	}
+	if err := json.Unmarshal(bodies[0], &request); err != nil {
+		t.Fatalf("failed to parse request body: %v", err)
+	}
+	if len(request.Updates) != 1 || request.Updates[0]["update_id"] != float64(42) {
+		t.Errorf("expected tracked update with update_id 42, got %v", request.Updates)
+	}
+}
+
+func TestHandler_FailOpen(t *testing.T) {
+	mockClient := &mockHTTPClient{status: http.StatusForbidden}
+	client := dashgram.New(123, "test-key", dashgram.WithHTTPClient(mockClient))
+	defer client.Close()
+
+	h := NewHandler(client)
+
+	if err := h.HandleUpdate(nil, &ext.Context{Update: &gotgbot.Update{UpdateId: 1}}); err != nil {
+		t.Errorf("expected nil error when tracking fails, got %v", err)
+	}
+	if err := h.HandleUpdate(nil, &ext.Context{}); err != nil {
+		t.Errorf("expected nil error for context without update, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package dashgram
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+)
+
+// contentSHA256Header is the request header set by WithRequestBodyHashing
+const contentSHA256Header = "X-Content-SHA256"
+
+// WithRequestBodyHashing sets the X-Content-SHA256 header of every request with
+// a body to the base64-encoded SHA-256 hash of the body as sent, e.g. for API
+// gateways checking request integrity. Requests without a body don't get it.
+func WithRequestBodyHashing() Option {
+	return func(d *Dashgram) {
+		d.hashRequestBody = true
+	}
+}
+
+// setBodyHash sets the X-Content-SHA256 header if WithRequestBodyHashing is set
+func (d *Dashgram) setBodyHash(req *http.Request, payload []byte) {
+	if !d.hashRequestBody {
+		return
+	}
+
+	sum := sha256.Sum256(payload)
+	req.Header.Set(contentSHA256Header, base64.StdEncoding.EncodeToString(sum[:]))
+}
@@ -0,0 +1,82 @@
+package dashgram
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"testing"
+)
+
+func TestDashgram_WithRequestBodyHashing(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		hashed  bool
+	}{
+		{
+			name:   "disabled by default",
+			hashed: false,
+		},
+		{
+			name:    "enabled",
+			options: []Option{WithRequestBodyHashing()},
+			hashed:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", append([]Option{WithHTTPClient(helper.MockHTTPClient())}, tt.options...)...)
+			defer d.Close()
+
+			if err := d.TrackEvent(map[string]any{"action": "click", "user_id": 42}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			request := helper.LastRequest()
+			if request == nil {
+				t.Fatal("expected a request")
+			}
+			header := request.Headers.Get("X-Content-SHA256")
+			if !tt.hashed {
+				if header != "" {
+					t.Errorf("expected no X-Content-SHA256 header, got %q", header)
+				}
+				return
+			}
+
+			sum := sha256.Sum256(request.Body)
+			if expected := base64.StdEncoding.EncodeToString(sum[:]); header != expected {
+				t.Errorf("expected X-Content-SHA256 %q, got %q", expected, header)
+			}
+		})
+	}
+}
+
+// fixedEncoder encodes every request as the same body
+type fixedEncoder []byte
+
+func (e fixedEncoder) Encode(any) ([]byte, error) { return e, nil }
+
+func (e fixedEncoder) MediaType() string { return "text/plain" }
+
+func TestDashgram_WithRequestBodyHashingKnownPayload(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithRequestBodyHashing(), WithEncoder(fixedEncoder("hello")))
+	defer d.Close()
+
+	if err := d.TrackEvent(map[string]any{"action": "click"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// sha256("hello")
+	expected := "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="
+	if header := helper.LastRequest().Headers.Get("X-Content-SHA256"); header != expected {
+		t.Errorf("expected X-Content-SHA256 %q, got %q", expected, header)
+	}
+}
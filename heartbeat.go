@@ -0,0 +1,109 @@
+package dashgram
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// modulePath is the module path of the SDK, used to find its version in the build info
+const modulePath = "github.com/dashgram/go-dashgram"
+
+// WithHeartbeat tracks a "heartbeat" event every interval while the client is
+// open, so the dashboard shows which instances are alive and what they run:
+//
+//	{"action": "heartbeat", "properties": {"hostname": "bot-7f9c", "sdk_version": "v1.8.0", "app_version": "v2.3.1", "go_version": "go1.22.1", "uptime_seconds": 3600}}
+//
+// Versions are read from the build info of the binary, "(devel)" or empty if
+// unknown, and uptime is counted from New. The first heartbeat is sent after
+// a random delay of up to interval, so a fleet started at once doesn't beat
+// in sync. Heartbeats are sent through the async queue, bypass sampling and
+// stop on Close. See WithHeartbeatProperties to add properties.
+func WithHeartbeat(interval time.Duration) Option {
+	return func(d *Dashgram) {
+		d.heartbeatInterval = interval
+	}
+}
+
+// WithHeartbeatProperties calls fn with the properties of every heartbeat
+// before it is sent, e.g. to add the region or the number of active chats
+func WithHeartbeatProperties(fn func(properties map[string]any)) Option {
+	return func(d *Dashgram) {
+		d.heartbeatProperties = fn
+	}
+}
+
+// startHeartbeat starts the goroutine sending the heartbeats
+func (d *Dashgram) startHeartbeat() {
+	if d.heartbeatInterval <= 0 {
+		return
+	}
+
+	hostname, _ := os.Hostname()
+	sdkVersion, appVersion := buildVersions()
+	startedAt := d.now()
+
+	d.workerWg.Add(1)
+	go func() {
+		defer d.workerWg.Done()
+
+		timer := time.NewTimer(heartbeatJitter(d.heartbeatInterval))
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-timer.C:
+				properties := map[string]any{
+					"hostname":       hostname,
+					"sdk_version":    sdkVersion,
+					"app_version":    appVersion,
+					"go_version":     runtime.Version(),
+					"uptime_seconds": int64(d.now().Sub(startedAt).Seconds()),
+				}
+				if d.heartbeatProperties != nil {
+					d.heartbeatProperties(properties)
+				}
+
+				event := map[string]any{"action": "heartbeat", "properties": properties}
+				if err := d.TrackEventAsyncWithContext(ForceTrack(context.Background()), event); err != nil {
+					d.log(LogLevelError, "failed to track heartbeat", "error", err)
+				}
+				timer.Reset(d.heartbeatInterval)
+			case <-d.workerCtx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// heartbeatJitter returns the random delay of the first heartbeat, in [0, interval)
+func heartbeatJitter(interval time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(interval)))
+}
+
+// buildVersions returns the versions of the SDK and of the application from
+// the build info of the binary
+func buildVersions() (sdkVersion, appVersion string) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", ""
+	}
+
+	appVersion = info.Main.Version
+	if info.Main.Path == modulePath {
+		return appVersion, appVersion
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			sdkVersion = dep.Version
+			if dep.Replace != nil {
+				sdkVersion = dep.Replace.Version
+			}
+			break
+		}
+	}
+	return sdkVersion, appVersion
+}
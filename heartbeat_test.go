@@ -0,0 +1,83 @@
+package dashgram
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WithHeartbeat(t *testing.T) {
+	var mu sync.Mutex
+	var heartbeats []map[string]any
+	client := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			var body struct {
+				Updates []map[string]any `json:"updates"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+
+			mu.Lock()
+			heartbeats = append(heartbeats, body.Updates...)
+			mu.Unlock()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(heartbeats)
+	}
+
+	d := New(123, "test-key",
+		WithHTTPClient(client),
+		WithSampleRate(0),
+		WithHeartbeat(20*time.Millisecond),
+		WithHeartbeatProperties(func(properties map[string]any) {
+			properties["region"] = "eu"
+		}),
+	)
+
+	waitFor(t, func() bool { return count() >= 2 })
+	d.Close()
+	closed := count()
+
+	mu.Lock()
+	heartbeat := heartbeats[0]
+	mu.Unlock()
+	if heartbeat["action"] != "heartbeat" {
+		t.Errorf("expected a heartbeat event, got %v", heartbeat["action"])
+	}
+	properties := heartbeat["properties"].(map[string]any)
+	for _, key := range []string{"hostname", "sdk_version", "app_version", "go_version", "uptime_seconds"} {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("expected the %s property, got %v", key, properties)
+		}
+	}
+	if properties["region"] != "eu" {
+		t.Errorf("expected the region added by the callback, got %v", properties["region"])
+	}
+
+	// No heartbeat is sent once the client is closed
+	time.Sleep(60 * time.Millisecond)
+	if after := count(); after != closed {
+		t.Errorf("expected no heartbeat after Close, got %d more", after-closed)
+	}
+}
+
+func TestHeartbeatJitter(t *testing.T) {
+	interval := 10 * time.Second
+	for i := 0; i < 100; i++ {
+		if jitter := heartbeatJitter(interval); jitter < 0 || jitter >= interval {
+			t.Fatalf("expected a jitter in [0, %v), got %v", interval, jitter)
+		}
+	}
+}
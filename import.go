@@ -0,0 +1,214 @@
+package dashgram
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// defaultImportBatchSize is the number of events sent per import request
+	defaultImportBatchSize = 100
+	// defaultImportRequestsPerSecond is the number of import requests sent per second
+	defaultImportRequestsPerSecond = 5
+	// defaultImportMaxRetries is the number of retries of a failed import batch
+	defaultImportMaxRetries = 3
+	// defaultImportRetryBackoff is the wait before the first retry of an import batch
+	defaultImportRetryBackoff = time.Second
+)
+
+// HistoricalEvent is an event imported by ImportEvents
+type HistoricalEvent struct {
+	// Event is the event as passed to TrackEvent
+	Event any
+	// OccurredAt is when the event happened, it is required
+	OccurredAt time.Time
+}
+
+// ImportOptions configures ImportEvents
+type ImportOptions struct {
+	// BatchSize is the number of events sent per request, 100 by default
+	BatchSize int
+	// RequestsPerSecond caps the request rate, 5 by default, negative for no limit
+	RequestsPerSecond float64
+	// MaxRetries is the number of retries of a failed batch, 3 by default,
+	// negative to disable retries. It replaces WithRetry for import requests,
+	// which are otherwise retried like other requests, see WithRetryBudget and
+	// WithRetryableStatuses.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled after each
+	// one, 1s by default
+	RetryBackoff time.Duration
+}
+
+// withDefaults returns the options with their zero values replaced by the defaults
+func (o ImportOptions) withDefaults() ImportOptions {
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaultImportBatchSize
+	}
+	if o.RequestsPerSecond == 0 {
+		o.RequestsPerSecond = defaultImportRequestsPerSecond
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = defaultImportMaxRetries
+	}
+	if o.RetryBackoff <= 0 {
+		o.RetryBackoff = defaultImportRetryBackoff
+	}
+	return o
+}
+
+// ImportReport is the outcome of ImportEvents
+type ImportReport struct {
+	// Total is the number of events read from the iterator
+	Total int
+	// Sent is the number of events imported
+	Sent int
+	// Skipped is the number of events suppressed by WithOptOut or filtered by WithBeforeSend
+	Skipped int
+	// Failed is the number of events that were invalid or whose batch failed
+	Failed int
+	// FailedIndices holds the index, in iteration order, of every failed event
+	FailedIndices []int
+	// Errors holds the error of the event at the same index of FailedIndices
+	Errors []error
+}
+
+// fail records the failure of the event at index
+func (r *ImportReport) fail(index int, err error) {
+	r.Failed++
+	r.FailedIndices = append(r.FailedIndices, index)
+	r.Errors = append(r.Errors, err)
+}
+
+// ImportEvents backfills historical events, e.g. when migrating from another
+// tracker, keeping the time each event occurred. Events are read from iter
+// until it returns false and sent in batches to the import endpoint, at most
+// opts.RequestsPerSecond requests per second. Batches failing with a transient
+// error are retried; batches that still fail, and invalid events, don't stop
+// the import and are listed in the report by their index, so they can be
+// imported again.
+//
+// Events go through WithOptOut, WithBeforeSend, the event transforms and
+// WithScrubFields like tracked events, but aren't sampled or debounced. The returned error is only
+// set if ctx is done, the report then covers the events read so far.
+func (d *Dashgram) ImportEvents(ctx context.Context, iter func() (HistoricalEvent, bool), opts ImportOptions) (*ImportReport, error) {
+	opts = opts.withDefaults()
+	var interval time.Duration
+	if opts.RequestsPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / opts.RequestsPerSecond)
+	}
+
+	report := &ImportReport{}
+	batch := make([]HistoricalUpdate, 0, opts.BatchSize)
+	indices := make([]int, 0, opts.BatchSize)
+	var lastSent time.Time
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if !lastSent.IsZero() {
+			if err := sleepContext(ctx, interval-time.Since(lastSent)); err != nil {
+				for _, index := range indices {
+					report.fail(index, err)
+				}
+				return err
+			}
+		}
+
+		err := d.sendImportBatch(ctx, batch, opts)
+		lastSent = time.Now()
+		if err != nil {
+			for _, index := range indices {
+				report.fail(index, err)
+			}
+		} else {
+			report.Sent += len(batch)
+		}
+
+		// The request is encoded before it returns, so the batch can be reused
+		batch, indices = batch[:0], indices[:0]
+		return ctx.Err()
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			// Events waiting for their batch are never sent
+			for _, index := range indices {
+				report.fail(index, err)
+			}
+			return report, err
+		}
+		event, ok := iter()
+		if !ok {
+			break
+		}
+
+		index := report.Total
+		report.Total++
+		update, err := d.historicalUpdate(event)
+		if err != nil {
+			report.fail(index, err)
+			continue
+		}
+		if update == nil {
+			report.Skipped++
+			continue
+		}
+
+		batch = append(batch, HistoricalUpdate{Update: update, OccurredAt: event.OccurredAt})
+		indices = append(indices, index)
+		if len(batch) == opts.BatchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// historicalUpdate validates and marshals an imported event, returning nil if
+// it is suppressed or filtered
+func (d *Dashgram) historicalUpdate(event HistoricalEvent) (any, error) {
+	if err := validateEvent(event.Event); err != nil {
+		return nil, err
+	}
+	if event.OccurredAt.IsZero() {
+		return nil, &ValidationError{Field: "OccurredAt", Message: "must be set"}
+	}
+	if d.suppressEvent(event.Event) {
+		return nil, nil
+	}
+	payload, ok := d.runBeforeSend("track", event.Event)
+	if !ok {
+		return nil, nil
+	}
+
+	return marshalUpdate(d.prepareUpdate(payload))
+}
+
+// sendImportBatch sends a batch of historical events with the retries of the import
+func (d *Dashgram) sendImportBatch(ctx context.Context, events []HistoricalUpdate, opts ImportOptions) error {
+	requestData := ImportEventsRequest{Events: events, Origin: d.Origin}
+	return d.request(ctx, "track/import", requestData, withCallRetry(opts.MaxRetries, opts.RetryBackoff))
+}
+
+// sleepContext waits for d, returning early with the error of ctx if it is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,223 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// historicalEvents returns an iterator over the events
+func historicalEvents(events ...HistoricalEvent) func() (HistoricalEvent, bool) {
+	return func() (HistoricalEvent, bool) {
+		if len(events) == 0 {
+			return HistoricalEvent{}, false
+		}
+		event := events[0]
+		events = events[1:]
+		return event, true
+	}
+}
+
+// clicks returns n historical click events, one per minute
+func clicks(n int) []HistoricalEvent {
+	occurredAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	events := make([]HistoricalEvent, n)
+	for i := range events {
+		events[i] = HistoricalEvent{
+			Event:      map[string]any{"action": "click", "user_id": i + 1},
+			OccurredAt: occurredAt.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	return events
+}
+
+func TestDashgram_ImportEvents(t *testing.T) {
+	ok := `{"status":"success","details":"ok"}`
+	tests := []struct {
+		name             string
+		events           []HistoricalEvent
+		statuses         []int
+		expectedRequests int
+		expectedSent     int
+		expectedFailed   []int
+	}{
+		{
+			name:             "batches",
+			events:           clicks(5),
+			statuses:         []int{http.StatusOK, http.StatusOK, http.StatusOK},
+			expectedRequests: 3,
+			expectedSent:     5,
+		},
+		{
+			name:             "invalid events",
+			events:           append(clicks(2), HistoricalEvent{Event: map[string]any{"action": "click"}}, HistoricalEvent{OccurredAt: time.Now()}),
+			statuses:         []int{http.StatusOK},
+			expectedRequests: 1,
+			expectedSent:     2,
+			expectedFailed:   []int{2, 3},
+		},
+		{
+			name:             "transient failure retried",
+			events:           clicks(2),
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedRequests: 2,
+			expectedSent:     2,
+		},
+		{
+			name:             "failed batch reported",
+			events:           clicks(4),
+			statuses:         []int{http.StatusOK, http.StatusBadRequest},
+			expectedRequests: 2,
+			expectedSent:     2,
+			expectedFailed:   []int{2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			for _, status := range tt.statuses {
+				if status == http.StatusOK {
+					helper.AddResponse(status, ok)
+				} else {
+					helper.AddResponse(status, `{"status":"error","details":"failed"}`)
+				}
+			}
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			report, err := d.ImportEvents(context.Background(), historicalEvents(tt.events...), ImportOptions{
+				BatchSize:         2,
+				RequestsPerSecond: -1,
+				RetryBackoff:      time.Millisecond,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if helper.RequestCount != tt.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tt.expectedRequests, helper.RequestCount)
+			}
+			if report.Total != len(tt.events) {
+				t.Errorf("expected %d events read, got %d", len(tt.events), report.Total)
+			}
+			if report.Sent != tt.expectedSent {
+				t.Errorf("expected %d events sent, got %d", tt.expectedSent, report.Sent)
+			}
+			if report.Failed != len(tt.expectedFailed) || !reflect.DeepEqual(report.FailedIndices, tt.expectedFailed) {
+				t.Errorf("expected failed events %v, got %d: %v", tt.expectedFailed, report.Failed, report.FailedIndices)
+			}
+			if len(report.Errors) != len(report.FailedIndices) {
+				t.Errorf("expected an error per failed event, got %d", len(report.Errors))
+			}
+		})
+	}
+}
+
+func TestDashgram_ImportEventsRetriesReplaceWithRetry(t *testing.T) {
+	helper := NewTestHelper()
+	for i := 0; i < 3; i++ {
+		helper.AddResponse(http.StatusServiceUnavailable, `{"status":"error","details":"unavailable"}`)
+	}
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithRetry(2, time.Millisecond))
+	defer d.Close()
+
+	report, err := d.ImportEvents(context.Background(), historicalEvents(clicks(1)...), ImportOptions{
+		RequestsPerSecond: -1,
+		MaxRetries:        1,
+		RetryBackoff:      time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if helper.RequestCount != 2 {
+		t.Errorf("expected 2 requests, got %d", helper.RequestCount)
+	}
+	if report.Failed != 1 {
+		t.Errorf("expected 1 failed event, got %d", report.Failed)
+	}
+}
+
+func TestDashgram_ImportEventsRequest(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	if _, err := d.ImportEvents(context.Background(), historicalEvents(clicks(2)...), ImportOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := helper.LastRequest()
+	if path := request.URL.Path; path != "/v1/123/track/import" {
+		t.Errorf("expected path /v1/123/track/import, got %s", path)
+	}
+	expected := `{"events":[{"update":{"action":"click","user_id":1},"occurred_at":"2023-05-01T12:00:00Z"},{"update":{"action":"click","user_id":2},"occurred_at":"2023-05-01T12:01:00Z"}],"origin":"Go + Dashgram SDK"}`
+	if body := string(request.Body); body != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+}
+
+func TestDashgram_ImportEventsRateLimit(t *testing.T) {
+	helper := NewTestHelper()
+	for i := 0; i < 3; i++ {
+		helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	}
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	start := time.Now()
+	report, err := d.ImportEvents(context.Background(), historicalEvents(clicks(3)...), ImportOptions{
+		BatchSize:         1,
+		RequestsPerSecond: 20,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Three requests at 20 per second take at least two intervals of 50ms
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected the import to take at least 100ms, got %v", elapsed)
+	}
+	if report.Sent != 3 {
+		t.Errorf("expected 3 events sent, got %d", report.Sent)
+	}
+}
+
+func TestDashgram_ImportEventsCancelled(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	read := 0
+	iter := func() (HistoricalEvent, bool) {
+		read++
+		if read == 3 {
+			cancel()
+		}
+		return HistoricalEvent{Event: map[string]any{"action": fmt.Sprint("click_", read)}, OccurredAt: time.Now()}, true
+	}
+
+	report, err := d.ImportEvents(ctx, iter, ImportOptions{BatchSize: 2, RequestsPerSecond: -1})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if report.Sent != 2 {
+		t.Errorf("expected 2 events sent, got %d", report.Sent)
+	}
+	if !reflect.DeepEqual(report.FailedIndices, []int{2}) {
+		t.Errorf("expected the pending event to fail, got %v", report.FailedIndices)
+	}
+}
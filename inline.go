@@ -0,0 +1,98 @@
+package dashgram
+
+import "context"
+
+// TrackInlineQuery tracks an inline query typed by a user, the first step of
+// the inline funnel. The query may be empty, when a user opens inline mode
+// without typing, and offset is only recorded when paginating:
+//
+//	{"action": "inline_query", "user_id": 42, "properties": {"query": "cats", "offset": "20"}}
+func (d *Dashgram) TrackInlineQuery(ctx context.Context, userID int64, query string, offset string) error {
+	event, err := inlineQueryEvent(userID, query, offset)
+	if err != nil {
+		return err
+	}
+
+	return d.TrackEventWithContext(ctx, event)
+}
+
+// TrackInlineQueryAsyncWithContext validates the arguments and enqueues the inline query to be tracked asynchronously
+func (d *Dashgram) TrackInlineQueryAsyncWithContext(ctx context.Context, userID int64, query string, offset string) error {
+	event, err := inlineQueryEvent(userID, query, offset)
+	if err != nil {
+		return err
+	}
+
+	return d.TrackEventAsyncWithContext(ctx, event)
+}
+
+func (d *Dashgram) TrackInlineQueryAsync(userID int64, query string, offset string) error {
+	return d.TrackInlineQueryAsyncWithContext(context.Background(), userID, query, offset)
+}
+
+// TrackChosenInlineResult tracks the inline result a user chose, the last
+// step of the inline funnel. Its "query" property matches the one of
+// TrackInlineQuery, so both can be joined into a conversion rate:
+//
+//	{"action": "chosen_inline_result", "user_id": 42, "properties": {"query": "cats", "result_id": "cat-7"}}
+func (d *Dashgram) TrackChosenInlineResult(ctx context.Context, userID int64, resultID, query string) error {
+	event, err := chosenInlineResultEvent(userID, resultID, query)
+	if err != nil {
+		return err
+	}
+
+	return d.TrackEventWithContext(ctx, event)
+}
+
+// TrackChosenInlineResultAsyncWithContext validates the arguments and enqueues the chosen result to be tracked asynchronously
+func (d *Dashgram) TrackChosenInlineResultAsyncWithContext(ctx context.Context, userID int64, resultID, query string) error {
+	event, err := chosenInlineResultEvent(userID, resultID, query)
+	if err != nil {
+		return err
+	}
+
+	return d.TrackEventAsyncWithContext(ctx, event)
+}
+
+func (d *Dashgram) TrackChosenInlineResultAsync(userID int64, resultID, query string) error {
+	return d.TrackChosenInlineResultAsyncWithContext(context.Background(), userID, resultID, query)
+}
+
+// inlineQueryEvent validates the arguments of TrackInlineQuery and returns the event to track
+func inlineQueryEvent(userID int64, query string, offset string) (map[string]any, error) {
+	if userID <= 0 {
+		return nil, &ValidationError{Field: "userID", Message: "must be positive"}
+	}
+
+	properties := map[string]any{
+		"query": query,
+	}
+	if offset != "" {
+		properties["offset"] = offset
+	}
+
+	return map[string]any{
+		"action":     "inline_query",
+		"user_id":    userID,
+		"properties": properties,
+	}, nil
+}
+
+// chosenInlineResultEvent validates the arguments of TrackChosenInlineResult and returns the event to track
+func chosenInlineResultEvent(userID int64, resultID, query string) (map[string]any, error) {
+	if userID <= 0 {
+		return nil, &ValidationError{Field: "userID", Message: "must be positive"}
+	}
+	if resultID == "" {
+		return nil, &ValidationError{Field: "resultID", Message: "must not be empty"}
+	}
+
+	return map[string]any{
+		"action":  "chosen_inline_result",
+		"user_id": userID,
+		"properties": map[string]any{
+			"query":     query,
+			"result_id": resultID,
+		},
+	}, nil
+}
@@ -0,0 +1,164 @@
+package dashgram
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestDashgram_TrackInlineQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        int64
+		query         string
+		offset        string
+		expected      string
+		expectedField string
+	}{
+		{
+			name:     "query",
+			userID:   12345,
+			query:    "cats",
+			expected: `{"updates":[{"action":"inline_query","properties":{"query":"cats"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "next page",
+			userID:   12345,
+			query:    "cats",
+			offset:   "20",
+			expected: `{"updates":[{"action":"inline_query","properties":{"offset":"20","query":"cats"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "empty query",
+			userID:   12345,
+			expected: `{"updates":[{"action":"inline_query","properties":{"query":""},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "invalid user",
+			userID:        0,
+			query:         "cats",
+			expectedField: "userID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			err := d.TrackInlineQuery(context.Background(), tt.userID, tt.query, tt.offset)
+
+			if tt.expectedField != "" {
+				assertValidationError(t, err, tt.expectedField, helper)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if body := string(helper.LastRequest().Body); body != tt.expected {
+				t.Errorf("expected body %s, got %s", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackChosenInlineResult(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        int64
+		resultID      string
+		query         string
+		expected      string
+		expectedField string
+	}{
+		{
+			name:     "chosen result",
+			userID:   12345,
+			resultID: "cat-7",
+			query:    "cats",
+			expected: `{"updates":[{"action":"chosen_inline_result","properties":{"query":"cats","result_id":"cat-7"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "empty query",
+			userID:   12345,
+			resultID: "cat-7",
+			expected: `{"updates":[{"action":"chosen_inline_result","properties":{"query":"","result_id":"cat-7"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "invalid user",
+			userID:        -1,
+			resultID:      "cat-7",
+			expectedField: "userID",
+		},
+		{
+			name:          "empty result ID",
+			userID:        12345,
+			query:         "cats",
+			expectedField: "resultID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			err := d.TrackChosenInlineResult(context.Background(), tt.userID, tt.resultID, tt.query)
+
+			if tt.expectedField != "" {
+				assertValidationError(t, err, tt.expectedField, helper)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if body := string(helper.LastRequest().Body); body != tt.expected {
+				t.Errorf("expected body %s, got %s", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackInlineAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithNumWorkers(1))
+
+	if err := d.TrackInlineQueryAsync(12345, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.TrackChosenInlineResultAsync(12345, "cat-7", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.TrackInlineQueryAsync(0, "cats", ""); err == nil {
+		t.Error("expected an invalid user to be rejected")
+	}
+	if err := d.TrackChosenInlineResultAsync(12345, "", "cats"); err == nil {
+		t.Error("expected an empty result ID to be rejected")
+	}
+	d.Close()
+
+	expected := []string{
+		`{"updates":[{"action":"inline_query","properties":{"query":""},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		`{"updates":[{"action":"chosen_inline_result","properties":{"query":"","result_id":"cat-7"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+	}
+	requests := helper.RecordedRequests()
+	if len(requests) != len(expected) {
+		t.Fatalf("expected %d requests, got %d", len(expected), len(requests))
+	}
+	for i, req := range requests {
+		if body := string(req.Body); body != expected[i] {
+			t.Errorf("expected body %s, got %s", expected[i], body)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package dashgram
+
+// LogLevel is the severity of a log message
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Logger receives the client's log messages, with context as alternating
+// key/value pairs
+type Logger interface {
+	Log(level LogLevel, msg string, keyvals ...any)
+}
+
+// WithLogger sets the logger receiving the client's log messages
+func WithLogger(logger Logger) Option {
+	return func(d *Dashgram) {
+		d.logger = logger
+	}
+}
+
+// WithLogLevel sets the minimum level of the messages passed to the logger (default LogLevelInfo)
+func WithLogLevel(level LogLevel) Option {
+	return func(d *Dashgram) {
+		d.logLevel = level
+	}
+}
+
+// log passes a message to the logger if its level meets the threshold
+func (d *Dashgram) log(level LogLevel, msg string, keyvals ...any) {
+	if d.logger == nil || level < d.logLevel {
+		return
+	}
+
+	// Never log the raw access key
+	redacted := make([]any, len(keyvals))
+	for i, value := range keyvals {
+		switch v := value.(type) {
+		case string:
+			redacted[i] = d.redact(v)
+		case error:
+			redacted[i] = d.redactError(v)
+		default:
+			redacted[i] = value
+		}
+	}
+	d.logger.Log(level, d.redact(msg), redacted...)
+}
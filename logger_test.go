@@ -0,0 +1,118 @@
+package dashgram
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingLogger records the messages it receives
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []loggedMessage
+}
+
+type loggedMessage struct {
+	level   LogLevel
+	msg     string
+	keyvals []any
+}
+
+func (l *recordingLogger) Log(level LogLevel, msg string, keyvals ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, loggedMessage{level: level, msg: msg, keyvals: keyvals})
+}
+
+func (l *recordingLogger) levels() map[LogLevel]int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	levels := make(map[LogLevel]int)
+	for _, m := range l.messages {
+		levels[m.level]++
+	}
+	return levels
+}
+
+func TestDashgram_WithLogLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		expected map[LogLevel]int
+	}{
+		{
+			name:     "debug messages suppressed at the default info level",
+			expected: map[LogLevel]int{LogLevelError: 1},
+		},
+		{
+			name:     "debug level",
+			options:  []Option{WithLogLevel(LogLevelDebug)},
+			expected: map[LogLevel]int{LogLevelDebug: 2, LogLevelError: 1},
+		},
+		{
+			name:     "error level",
+			options:  []Option{WithLogLevel(LogLevelError)},
+			expected: map[LogLevel]int{LogLevelError: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+			helper.AddResponse(400, `{"status":"error","details":"bad request"}`)
+
+			logger := &recordingLogger{}
+			options := append([]Option{WithHTTPClient(helper.MockHTTPClient()), WithLogger(logger)}, tt.options...)
+			d := New(123, "test-key", options...)
+
+			d.TrackEventAsync(map[string]any{"action": "first"})
+			d.TrackEventAsync(map[string]any{"action": "second"})
+
+			deadline := time.Now().Add(time.Second)
+			for time.Now().Before(deadline) && d.Stats().Delivered+d.Stats().Failed < 2 {
+				time.Sleep(10 * time.Millisecond)
+			}
+			d.Close()
+
+			levels := logger.levels()
+			if len(levels) != len(tt.expected) {
+				t.Errorf("expected levels %v, got %v", tt.expected, levels)
+			}
+			for level, count := range tt.expected {
+				if levels[level] != count {
+					t.Errorf("expected %d %s messages, got %d", count, level, levels[level])
+				}
+			}
+		})
+	}
+}
+
+func TestDashgram_LogDroppedTask(t *testing.T) {
+	logger := &recordingLogger{}
+	d := New(123, "test-key", WithLogger(logger))
+	d.Close()
+
+	d.TrackEventAsync(map[string]any{"action": "dropped"})
+
+	if levels := logger.levels(); levels[LogLevelWarn] != 1 {
+		t.Errorf("expected 1 warning for the dropped task, got %v", levels)
+	}
+}
+
+func TestLogLevel_String(t *testing.T) {
+	expected := map[LogLevel]string{
+		LogLevelDebug: "debug",
+		LogLevelInfo:  "info",
+		LogLevelWarn:  "warn",
+		LogLevelError: "error",
+		LogLevel(42):  "unknown",
+	}
+
+	for level, name := range expected {
+		if level.String() != name {
+			t.Errorf("expected '%s', got '%s'", name, level.String())
+		}
+	}
+}
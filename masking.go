@@ -0,0 +1,62 @@
+package dashgram
+
+import "strings"
+
+// AccessKeyMasker returns the form of the access key shown in logs and errors
+type AccessKeyMasker func(key string) string
+
+// WithAccessKeyMasker sets how the access key is redacted when it appears in
+// log messages or error strings, e.g. echoed back by the API. The default is
+// DefaultAccessKeyMasker.
+func WithAccessKeyMasker(fn AccessKeyMasker) Option {
+	return func(d *Dashgram) {
+		if fn != nil {
+			d.keyMasker = fn
+		}
+	}
+}
+
+// DefaultAccessKeyMasker reveals only the first and last 4 characters of the
+// key, as in "abcd...wxyz". Keys too short to be partially revealed are fully masked.
+func DefaultAccessKeyMasker(key string) string {
+	if len(key) <= 12 {
+		return "****"
+	}
+	return key[:4] + "..." + key[len(key)-4:]
+}
+
+// redact replaces every occurrence of the access key in s with its masked form
+func (d *Dashgram) redact(s string) string {
+	key := d.accessKey()
+	if key == "" || !strings.Contains(s, key) {
+		return s
+	}
+	return strings.ReplaceAll(s, key, d.keyMasker(key))
+}
+
+// redactError masks the access key in the message of err, keeping err unwrappable
+func (d *Dashgram) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	if redacted := d.redact(msg); redacted != msg {
+		return &redactedError{msg: redacted, err: err}
+	}
+	return err
+}
+
+// redactedError is an error whose message has the access key masked
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,95 @@
+package dashgram
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const secretKey = "abcd1234567890wxyz"
+
+func TestDefaultAccessKeyMasker(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{key: secretKey, expected: "abcd...wxyz"},
+		{key: "short-key", expected: "****"},
+		{key: "", expected: "****"},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultAccessKeyMasker(tt.key); got != tt.expected {
+			t.Errorf("expected '%s' for key '%s', got '%s'", tt.expected, tt.key, got)
+		}
+	}
+}
+
+func TestDashgram_AccessKeyMasking(t *testing.T) {
+	t.Run("API error details", func(t *testing.T) {
+		helper := NewTestHelper()
+		helper.AddResponse(http.StatusBadRequest, `{"status":"error","details":"unknown key `+secretKey+`"}`)
+
+		d := New(123, secretKey, WithHTTPClient(helper.MockHTTPClient()))
+		defer d.Close()
+
+		err := d.TrackEvent(map[string]any{"action": "click"})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		expected := "dashgram API error (status: 400): unknown key abcd...wxyz"
+		if err.Error() != expected {
+			t.Errorf("expected error '%s', got '%s'", expected, err.Error())
+		}
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		cause := fmt.Errorf("proxy rejected Bearer %s", secretKey)
+		helper := NewTestHelper()
+		helper.AddError(cause)
+
+		d := New(123, secretKey, WithHTTPClient(helper.MockHTTPClient()), WithAccessKeyMasker(func(string) string {
+			return "[REDACTED]"
+		}))
+		defer d.Close()
+
+		err := d.TrackEvent(map[string]any{"action": "click"})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		expected := "request failed: proxy rejected Bearer [REDACTED]"
+		if err.Error() != expected {
+			t.Errorf("expected error '%s', got '%s'", expected, err.Error())
+		}
+		if !errors.Is(err, cause) {
+			t.Errorf("expected the masked error to unwrap to its cause")
+		}
+	})
+
+	t.Run("log messages", func(t *testing.T) {
+		helper := NewTestHelper()
+		helper.AddError(fmt.Errorf("proxy rejected Bearer %s", secretKey))
+
+		logger := &recordingLogger{}
+		d := New(123, secretKey, WithHTTPClient(helper.MockHTTPClient()), WithLogger(logger))
+
+		d.TrackEventAsync(map[string]any{"action": "click"})
+		helper.WaitForRequests(1, time.Second)
+		d.Close()
+
+		logger.mu.Lock()
+		defer logger.mu.Unlock()
+		if len(logger.messages) == 0 {
+			t.Fatal("expected the failure to be logged")
+		}
+		for _, m := range logger.messages {
+			line := fmt.Sprint(m.msg, m.keyvals)
+			if strings.Contains(line, secretKey) {
+				t.Errorf("expected the access key to be masked, got '%s'", line)
+			}
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// messageUpdate is the update envelope of a message tracked with TrackMessage
+type messageUpdate struct {
+	Message  json.RawMessage `json:"message"`
+	UpdateID int64           `json:"update_id"`
+}
+
+// TrackMessage tracks a Telegram message received without its surrounding
+// update, e.g. from a business connection or a userbot. msg is either raw JSON
+// ([]byte or json.RawMessage) or a value encoding to a Telegram Message
+// object, such as the Message type of a bot library. It is wrapped in an
+// update with a synthetic update_id:
+//
+//	{"message": {...}, "update_id": 1718000000000}
+//
+// Synthetic update IDs are unique, increasing Unix milliseconds, far above the
+// IDs Telegram assigns. A msg that already has an "update_id" is an update and
+// is tracked as is.
+func (d *Dashgram) TrackMessage(ctx context.Context, msg any) error {
+	update, err := d.messageUpdate(msg)
+	if err != nil {
+		return err
+	}
+
+	return d.TrackEventWithContext(ctx, update)
+}
+
+// messageUpdate validates the message and wraps it in an update
+func (d *Dashgram) messageUpdate(msg any) (json.RawMessage, error) {
+	var raw []byte
+	switch m := msg.(type) {
+	case nil:
+		return nil, &ValidationError{Field: "msg", Message: "must not be nil"}
+	case json.RawMessage:
+		raw = m
+	case []byte:
+		raw = m
+	default:
+		var err error
+		if raw, err = json.Marshal(msg); err != nil {
+			return nil, &ValidationError{Field: "msg", Message: "must be JSON-serializable: " + err.Error()}
+		}
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		return nil, &ValidationError{Field: "msg", Message: "must be a JSON object"}
+	}
+	if _, ok := fields["update_id"]; ok {
+		return raw, nil
+	}
+
+	return json.Marshal(messageUpdate{Message: raw, UpdateID: d.nextUpdateID()})
+}
+
+// nextUpdateID returns a synthetic update ID, the current Unix time in
+// milliseconds or one more than the last ID if that is later
+func (d *Dashgram) nextUpdateID() int64 {
+	for {
+		last := d.lastUpdateID.Load()
+		id := d.now().UnixMilli()
+		if id <= last {
+			id = last + 1
+		}
+		if d.lastUpdateID.CompareAndSwap(last, id) {
+			return id
+		}
+	}
+}
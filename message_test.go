@@ -0,0 +1,115 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestDashgram_TrackMessage(t *testing.T) {
+	type chat struct {
+		ID   int64  `json:"id"`
+		Type string `json:"type"`
+	}
+	type message struct {
+		MessageID int64  `json:"message_id"`
+		Chat      chat   `json:"chat"`
+		Text      string `json:"text"`
+	}
+
+	tests := []struct {
+		name     string
+		msg      any
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "raw JSON",
+			msg:      json.RawMessage(`{"message_id":7,"chat":{"id":42,"type":"private"},"text":"hi"}`),
+			expected: `{"updates":[{"message":{"message_id":7,"chat":{"id":42,"type":"private"},"text":"hi"},"update_id":1714564800000}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "bytes",
+			msg:      []byte(`{"message_id":7,"text":"hi"}`),
+			expected: `{"updates":[{"message":{"message_id":7,"text":"hi"},"update_id":1714564800000}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "typed message",
+			msg:      message{MessageID: 7, Chat: chat{ID: 42, Type: "private"}, Text: "hi"},
+			expected: `{"updates":[{"message":{"message_id":7,"chat":{"id":42,"type":"private"},"text":"hi"},"update_id":1714564800000}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "already an update",
+			msg:      json.RawMessage(`{"update_id":5,"message":{"message_id":7}}`),
+			expected: `{"updates":[{"update_id":5,"message":{"message_id":7}}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:    "nil",
+			msg:     nil,
+			wantErr: true,
+		},
+		{
+			name:    "not an object",
+			msg:     json.RawMessage(`[1,2]`),
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			msg:     []byte(`{"message_id":`),
+			wantErr: true,
+		},
+		{
+			name:    "unserializable",
+			msg:     make(chan int),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), withClock(newFakeClock().Now))
+			defer d.Close()
+
+			err := d.TrackMessage(context.Background(), tt.msg)
+
+			if tt.wantErr {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != "msg" {
+					t.Fatalf("expected ValidationError on msg, got %v", err)
+				}
+				if helper.RequestCount != 0 {
+					t.Errorf("expected no request, got %d", helper.RequestCount)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if body := string(helper.LastRequest().Body); body != tt.expected {
+				t.Errorf("expected body %s, got %s", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestDashgram_nextUpdateID(t *testing.T) {
+	clock := newFakeClock()
+	d := New(123, "test-key", withClock(clock.Now))
+	defer d.Close()
+
+	// IDs stay unique while the clock doesn't move
+	first := d.nextUpdateID()
+	second := d.nextUpdateID()
+	if first != 1714564800000 {
+		t.Errorf("expected the first ID to be the time in milliseconds, got %d", first)
+	}
+	if second != first+1 {
+		t.Errorf("expected %d, got %d", first+1, second)
+	}
+}
@@ -0,0 +1,27 @@
+package dashgram
+
+import "net/http"
+
+// HttpClientFunc adapts a function to the HttpClient interface, e.g. to write middleware
+type HttpClientFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req)
+func (f HttpClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// NewHTTPClientWithMiddleware wraps base with middlewares, like http.RoundTripper
+// middleware. The first middleware is the outermost: a request goes through
+// the middlewares in order before reaching base, and the response comes back
+// in reverse order. Pass the result to WithHTTPClient:
+//
+//	client := dashgram.New(projectID, accessKey, dashgram.WithHTTPClient(
+//		dashgram.NewHTTPClientWithMiddleware(http.DefaultClient, logging, metrics),
+//	))
+func NewHTTPClientWithMiddleware(base HttpClient, middlewares ...func(HttpClient) HttpClient) HttpClient {
+	client := base
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		client = middlewares[i](client)
+	}
+	return client
+}
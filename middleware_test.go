@@ -0,0 +1,52 @@
+package dashgram
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNewHTTPClientWithMiddleware(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	var calls []string
+	middleware := func(name string) func(HttpClient) HttpClient {
+		return func(next HttpClient) HttpClient {
+			return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				req.Header.Add("X-Middleware", name)
+				resp, err := next.Do(req)
+				calls = append(calls, name+" after")
+				return resp, err
+			})
+		}
+	}
+
+	client := NewHTTPClientWithMiddleware(helper.MockHTTPClient(), middleware("first"), middleware("second"))
+
+	d := New(123, "test-key", WithHTTPClient(client))
+	defer d.Close()
+
+	if err := d.TrackEvent(map[string]any{"action": "click"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"first before", "second before", "second after", "first after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+
+	headers := helper.LastRequest().Headers.Values("X-Middleware")
+	if !reflect.DeepEqual(headers, []string{"first", "second"}) {
+		t.Errorf("expected the request to reach the base client through both middlewares, got %v", headers)
+	}
+}
+
+func TestNewHTTPClientWithMiddleware_NoMiddleware(t *testing.T) {
+	base := NewTestHelper().MockHTTPClient()
+
+	if client := NewHTTPClientWithMiddleware(base); client != HttpClient(base) {
+		t.Error("expected the base client to be returned as is")
+	}
+}
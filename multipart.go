@@ -0,0 +1,133 @@
+package dashgram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+)
+
+// multipartPayloadField is the name of the form part holding the JSON request
+const multipartPayloadField = "payload"
+
+// Attachment is a file sent along with an event by TrackEventMultipart
+type Attachment struct {
+	// Name is the form field name of the part
+	Name     string
+	FileName string
+	// ContentType defaults to application/octet-stream
+	ContentType string
+	Data        []byte
+}
+
+// multipartRequest is request data sent as multipart/form-data instead of JSON
+type multipartRequest struct {
+	payload     any
+	attachments []Attachment
+	// boundary is kept across retries, so every attempt sends the same body
+	boundary string
+}
+
+// encode writes the JSON payload and the attachments as form parts and
+// returns the body with its content type, including the boundary
+func (r *multipartRequest) encode() (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if err := writer.SetBoundary(r.boundary); err != nil {
+		return nil, "", err
+	}
+
+	payload, err := newRequestBody(r.payload)
+	if err != nil {
+		return nil, "", err
+	}
+	defer payload.Close()
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, multipartPayloadField))
+	header.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := payload.buf.WriteTo(part); err != nil {
+		return nil, "", err
+	}
+
+	for _, attachment := range r.attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(attachment.Name), escapeQuotes(attachment.FileName)))
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(attachment.Data); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body, writer.FormDataContentType(), nil
+}
+
+// escapeQuotes escapes a Content-Disposition parameter value, like mime/multipart does
+func escapeQuotes(s string) string {
+	var b bytes.Buffer
+	for _, r := range s {
+		if r == '\\' || r == '"' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// TrackEventMultipart tracks an event with file attachments, sent as a
+// multipart/form-data request: a "payload" part holding the JSON request
+// followed by one part per attachment. The attachments are held in memory, so
+// it always runs synchronously, even with WithUseAsync.
+func (d *Dashgram) TrackEventMultipart(ctx context.Context, event any, attachments []Attachment, opts ...CallOption) error {
+	if err := validateEvent(event); err != nil {
+		return err
+	}
+	for i, attachment := range attachments {
+		if attachment.Name == "" {
+			return &ValidationError{Field: fmt.Sprintf("attachments[%d].Name", i), Message: "must not be empty"}
+		}
+		if attachment.Name == multipartPayloadField {
+			return &ValidationError{Field: fmt.Sprintf("attachments[%d].Name", i), Message: fmt.Sprintf("must not be %q", multipartPayloadField)}
+		}
+	}
+	if d.skipEvent(ctx, event, opts) {
+		return nil
+	}
+	opts = d.shardOptions(event, opts)
+	event, ok := d.runBeforeSend("track", event)
+	if !ok {
+		return nil
+	}
+
+	requestData := &multipartRequest{
+		payload: TrackEventRequest{
+			Origin:  newCallOptions(opts).originOr(d.Origin),
+			Updates: []any{d.prepareUpdate(event)},
+		},
+		attachments: attachments,
+		boundary:    multipart.NewWriter(nil).Boundary(),
+	}
+
+	_, err := d.do(ctx, http.MethodPost, "track", nil, requestData, nil, opts...)
+	return err
+}
@@ -0,0 +1,153 @@
+package dashgram
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDashgram_TrackEventMultipart(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithUseAsync())
+	defer d.Close()
+
+	err := d.TrackEventMultipart(context.Background(), map[string]any{"action": "upload"}, []Attachment{
+		{Name: "screenshot", FileName: "screen.png", ContentType: "image/png", Data: []byte{0x89, 'P', 'N', 'G'}},
+		{Name: "log", FileName: "app.log", Data: []byte("line 1\n")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := helper.LastRequest()
+	if req == nil {
+		t.Fatal("expected a request to be sent synchronously")
+	}
+	if req.URL.Path != "/v1/123/track" {
+		t.Errorf("expected path '/v1/123/track', got '%s'", req.URL.Path)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(req.Headers.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("failed to parse Content-Type: %v", err)
+	}
+	if mediaType != "multipart/form-data" {
+		t.Fatalf("expected multipart/form-data, got %s", mediaType)
+	}
+	if params["boundary"] == "" {
+		t.Fatal("expected a boundary")
+	}
+	if !bytes.HasSuffix(req.Body, []byte("--"+params["boundary"]+"--\r\n")) {
+		t.Error("expected the body to end with the closing boundary")
+	}
+
+	expected := []struct {
+		name        string
+		fileName    string
+		contentType string
+		data        string
+	}{
+		{name: "payload", contentType: "application/json", data: `{"updates":[{"action":"upload"}],"origin":"Go + Dashgram SDK"}`},
+		{name: "screenshot", fileName: "screen.png", contentType: "image/png", data: "\x89PNG"},
+		{name: "log", fileName: "app.log", contentType: "application/octet-stream", data: "line 1\n"},
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(req.Body), params["boundary"])
+	for _, want := range expected {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("expected part %q, got error: %v", want.name, err)
+		}
+
+		if part.FormName() != want.name {
+			t.Errorf("expected part %q, got %q", want.name, part.FormName())
+		}
+		if part.FileName() != want.fileName {
+			t.Errorf("expected file name %q, got %q", want.fileName, part.FileName())
+		}
+		if contentType := part.Header.Get("Content-Type"); contentType != want.contentType {
+			t.Errorf("expected Content-Type %q, got %q", want.contentType, contentType)
+		}
+		data, _ := io.ReadAll(part)
+		if string(data) != want.data {
+			t.Errorf("expected part %q data %q, got %q", want.name, want.data, data)
+		}
+	}
+
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("expected no more parts, got %v", err)
+	}
+}
+
+func TestDashgram_TrackEventMultipartRetry(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusServiceUnavailable, `{"status":"error","details":"unavailable"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithRetry(1, 0))
+	defer d.Close()
+
+	attachments := []Attachment{{Name: "file", FileName: "a.bin", Data: []byte("data")}}
+	if err := d.TrackEventMultipart(context.Background(), map[string]any{"action": "upload"}, attachments); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := helper.RecordedRequests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if !bytes.Equal(requests[0].Body, requests[1].Body) {
+		t.Error("expected the retried request to have the same body")
+	}
+}
+
+func TestDashgram_TrackEventMultipartValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		event         any
+		attachments   []Attachment
+		expectedField string
+	}{
+		{name: "nil event", event: nil, expectedField: "event"},
+		{name: "unnamed attachment", event: map[string]any{"action": "upload"}, attachments: []Attachment{{FileName: "a.bin"}}, expectedField: "attachments[0].Name"},
+		{name: "reserved name", event: map[string]any{"action": "upload"}, attachments: []Attachment{{Name: "file"}, {Name: "payload"}}, expectedField: "attachments[1].Name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			err := d.TrackEventMultipart(context.Background(), tt.event, tt.attachments)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if validationErr.Field != tt.expectedField {
+				t.Errorf("expected field %q, got %q", tt.expectedField, validationErr.Field)
+			}
+			if helper.RequestCount != 0 {
+				t.Errorf("expected no request, got %d", helper.RequestCount)
+			}
+		})
+	}
+}
+
+func TestEscapeQuotes(t *testing.T) {
+	if got := escapeQuotes(`a "b"\c`); got != `a \"b\"\\c` {
+		t.Errorf("expected %q, got %q", `a \"b\"\\c`, got)
+	}
+	if strings.Contains(escapeQuotes("plain.txt"), `\`) {
+		t.Error("expected plain names to be left untouched")
+	}
+}
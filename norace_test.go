@@ -0,0 +1,5 @@
+//go:build !race
+
+package dashgram
+
+const raceEnabled = false
@@ -0,0 +1,118 @@
+package dashgram
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// UserIDExtractor returns the ID of the user an event is about, and false if
+// the event isn't about a user
+type UserIDExtractor func(event any) (int64, bool)
+
+// WithOptOut suppresses every event about a user for whom fn returns true,
+// e.g. users who opted out of analytics. Suppressed events are dropped before
+// being marshaled, so nothing about these users leaves the process; they are
+// counted in Stats().Suppressed and the tracking methods return nil.
+//
+// The user of an event is found with the UserIDExtractor set by
+// WithUserIDExtractor, DefaultUserIDExtractor by default. Invitations are
+// suppressed if either user opted out, and user properties, aliases and
+// segments if the user did. DeleteUserData is never suppressed.
+func WithOptOut(fn func(userID int64) bool) Option {
+	return func(d *Dashgram) {
+		d.optOut = fn
+	}
+}
+
+// WithUserIDExtractor sets how WithOptOut finds the user of an event
+func WithUserIDExtractor(fn UserIDExtractor) Option {
+	return func(d *Dashgram) {
+		d.userIDExtractor = fn
+	}
+}
+
+// DefaultUserIDExtractor reads the "user_id" field of map and raw JSON
+// events, as set by TrackEventWithUserID. The ID may be an int, int32 or
+// int64, an integral float64, a json.Number or a numeric string.
+func DefaultUserIDExtractor(event any) (int64, bool) {
+	switch e := event.(type) {
+	case map[string]any:
+		return userIDValue(e["user_id"])
+	case json.RawMessage:
+		var fields struct {
+			UserID any `json:"user_id"`
+		}
+		decoder := json.NewDecoder(bytes.NewReader(e))
+		decoder.UseNumber()
+		if err := decoder.Decode(&fields); err != nil {
+			return 0, false
+		}
+		return userIDValue(fields.UserID)
+	default:
+		return 0, false
+	}
+}
+
+// userIDValue converts a "user_id" field value to an int64
+func userIDValue(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, false
+		}
+		return int64(v), true
+	case json.Number:
+		id, err := v.Int64()
+		return id, err == nil
+	case string:
+		id, err := strconv.ParseInt(v, 10, 64)
+		return id, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// suppressEvent reports whether the event is about an opted-out user, counting it as suppressed
+func (d *Dashgram) suppressEvent(event any) bool {
+	if d.optOut == nil {
+		return false
+	}
+
+	userID, ok := d.extractUserID(event)
+	if !ok {
+		return false
+	}
+
+	return d.suppressUsers(userID)
+}
+
+// extractUserID returns the user of an event with the configured UserIDExtractor
+func (d *Dashgram) extractUserID(event any) (int64, bool) {
+	if d.userIDExtractor != nil {
+		return d.userIDExtractor(event)
+	}
+	return DefaultUserIDExtractor(event)
+}
+
+// suppressUsers reports whether any of the users opted out, counting the event as suppressed
+func (d *Dashgram) suppressUsers(userIDs ...int64) bool {
+	if d.optOut == nil {
+		return false
+	}
+
+	for _, userID := range userIDs {
+		if d.optOut(userID) {
+			d.stats.suppressed.Add(1)
+			d.log(LogLevelDebug, "event suppressed for opted-out user")
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDashgram_WithOptOut(t *testing.T) {
+	optedOut := func(userID int64) bool { return userID == 42 }
+
+	tests := []struct {
+		name  string
+		async bool
+		call  func(d *Dashgram) error
+	}{
+		{
+			name: "track event",
+			call: func(d *Dashgram) error { return d.TrackEvent(map[string]any{"action": "click", "user_id": int64(42)}) },
+		},
+		{
+			name: "track event with user ID",
+			call: func(d *Dashgram) error { return d.TrackEventWithUserID(42, map[string]any{"action": "click"}) },
+		},
+		{
+			name: "track event JSON",
+			call: func(d *Dashgram) error { return d.TrackEventJSON([]byte(`{"action":"click","user_id":42}`)) },
+		},
+		{
+			name:  "track event async",
+			async: true,
+			call:  func(d *Dashgram) error { return d.TrackEvent(map[string]any{"action": "click", "user_id": 42}) },
+		},
+		{
+			name: "track event with response",
+			call: func(d *Dashgram) error {
+				resp, err := d.TrackEventWithResponse(context.Background(), map[string]any{"user_id": 42.0})
+				if resp != nil {
+					t.Errorf("expected no response, got %+v", resp)
+				}
+				return err
+			},
+		},
+		{
+			name: "track group event",
+			call: func(d *Dashgram) error { return d.TrackGroupEvent(7, map[string]any{"user_id": "42"}) },
+		},
+		{
+			name: "track payment",
+			call: func(d *Dashgram) error {
+				return d.TrackPayment(context.Background(), Payment{UserID: 42, Amount: 100, Currency: "USD"})
+			},
+		},
+		{
+			name: "track event multipart",
+			call: func(d *Dashgram) error {
+				return d.TrackEventMultipart(context.Background(), map[string]any{"user_id": 42}, []Attachment{{Name: "file"}})
+			},
+		},
+		{
+			name: "invited user opted out",
+			call: func(d *Dashgram) error { return d.InvitedBy(42, 1) },
+		},
+		{
+			name: "inviting user opted out",
+			call: func(d *Dashgram) error { return d.InvitedBy(1, 42) },
+		},
+		{
+			name:  "invited by async",
+			async: true,
+			call: func(d *Dashgram) error {
+				d.InvitedByAsync(1, 42)
+				return nil
+			},
+		},
+		{
+			name: "set user properties",
+			call: func(d *Dashgram) error { return d.SetUserProperties(42, map[string]any{"plan": "pro"}) },
+		},
+		{
+			name: "invited by batch",
+			call: func(d *Dashgram) error {
+				errs := d.InvitedByBatch([]InvitedByRequest{{UserID: 42, InvitedBy: 1}})
+				return errs[0]
+			},
+		},
+		{
+			name: "import invited by",
+			call: func(d *Dashgram) error {
+				return d.ImportInvitedBy(context.Background(), []InvitePair{{UserID: 1, InvitedBy: 42}}, InviteImportOptions{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			options := []Option{WithHTTPClient(helper.MockHTTPClient()), WithOptOut(optedOut)}
+			if tt.async {
+				options = append(options, WithUseAsync())
+			}
+			d := New(123, "test-key", options...)
+
+			if err := tt.call(d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			d.Close()
+
+			if helper.RequestCount != 0 {
+				t.Errorf("expected no request, got %d", helper.RequestCount)
+			}
+			stats := d.Stats()
+			if stats.Suppressed != 1 {
+				t.Errorf("expected 1 suppressed event, got %d", stats.Suppressed)
+			}
+			if stats.Enqueued != 0 {
+				t.Errorf("expected no enqueued task, got %d", stats.Enqueued)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithOptOutSendsOtherUsers(t *testing.T) {
+	helper := NewTestHelper()
+	for i := 0; i < 3; i++ {
+		helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	}
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithOptOut(func(userID int64) bool { return userID == 42 }),
+	)
+	defer d.Close()
+
+	if err := d.TrackEventWithUserID(7, map[string]any{"action": "click"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.TrackEvent(map[string]any{"action": "anonymous"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := d.TrackEventBatch([]any{
+		map[string]any{"action": "a", "user_id": 42},
+		map[string]any{"action": "b", "user_id": 7},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := helper.RecordedRequests()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	if body := string(requests[2].Body); strings.Contains(body, `"a"`) || !strings.Contains(body, `"b"`) {
+		t.Errorf("expected only the event of user 7 in the batch, got %s", body)
+	}
+	if suppressed := d.Stats().Suppressed; suppressed != 1 {
+		t.Errorf("expected 1 suppressed event, got %d", suppressed)
+	}
+}
+
+type optOutTestEvent struct {
+	Action string `json:"action"`
+	From   int64  `json:"from"`
+}
+
+func TestDashgram_WithUserIDExtractor(t *testing.T) {
+	helper := NewTestHelper()
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithOptOut(func(userID int64) bool { return userID == 42 }),
+		WithUserIDExtractor(func(event any) (int64, bool) {
+			e, ok := event.(optOutTestEvent)
+			return e.From, ok
+		}),
+	)
+	defer d.Close()
+
+	if err := d.TrackEvent(optOutTestEvent{Action: "click", From: 42}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if helper.RequestCount != 0 {
+		t.Errorf("expected no request, got %d", helper.RequestCount)
+	}
+	if suppressed := d.Stats().Suppressed; suppressed != 1 {
+		t.Errorf("expected 1 suppressed event, got %d", suppressed)
+	}
+}
+
+type unmarshalableEvent struct {
+	UserID int64  `json:"user_id"`
+	Fn     func() `json:"fn"`
+}
+
+func TestDashgram_WithOptOutNeverMarshals(t *testing.T) {
+	helper := NewTestHelper()
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithUseAsync(),
+		WithOptOut(func(userID int64) bool { return true }),
+		WithUserIDExtractor(func(event any) (int64, bool) {
+			return event.(unmarshalableEvent).UserID, true
+		}),
+	)
+	defer d.Close()
+
+	// Marshaling the event would fail, so no error means it never was
+	if err := d.TrackEvent(unmarshalableEvent{UserID: 42, Fn: func() {}}); err != nil {
+		t.Errorf("expected the event to be suppressed before marshaling, got %v", err)
+	}
+}
+
+func TestDefaultUserIDExtractor(t *testing.T) {
+	tests := []struct {
+		name       string
+		event      any
+		expectedID int64
+		expectedOK bool
+	}{
+		{name: "int", event: map[string]any{"user_id": 42}, expectedID: 42, expectedOK: true},
+		{name: "int64", event: map[string]any{"user_id": int64(42)}, expectedID: 42, expectedOK: true},
+		{name: "float64", event: map[string]any{"user_id": 42.0}, expectedID: 42, expectedOK: true},
+		{name: "fractional float64", event: map[string]any{"user_id": 4.2}},
+		{name: "string", event: map[string]any{"user_id": "42"}, expectedID: 42, expectedOK: true},
+		{name: "non-numeric string", event: map[string]any{"user_id": "alice"}},
+		{name: "json number", event: map[string]any{"user_id": json.Number("42")}, expectedID: 42, expectedOK: true},
+		{name: "missing", event: map[string]any{"action": "click"}},
+		{name: "raw JSON", event: json.RawMessage(`{"action":"click","user_id":9007199254740993}`), expectedID: 9007199254740993, expectedOK: true},
+		{name: "raw JSON without user ID", event: json.RawMessage(`{"action":"click"}`)},
+		{name: "raw JSON array", event: json.RawMessage(`[1,2]`)},
+		{name: "struct", event: optOutTestEvent{From: 42}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := DefaultUserIDExtractor(tt.event)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok %v, got %v", tt.expectedOK, ok)
+			}
+			if id != tt.expectedID {
+				t.Errorf("expected ID %d, got %d", tt.expectedID, id)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package dashgram
+
+import "hash/fnv"
+
+// WithAsyncEventOrdering keeps the async events of each user in order: instead
+// of sharing the queues of their pool, the workers each get their own queues,
+// and the events of a user, found as with WithUserIDExtractor, are always
+// routed to the same worker. Events without a user, and other async tasks,
+// are spread round-robin. Users are still processed in parallel, but a slow
+// request delays the other users of its worker. Each worker queue holds
+// WithQueueSize tasks, and events of different priorities may still overtake
+// each other, see WithPriority. The events of a user wait for room in a full
+// worker queue even with WithAsyncFallbackToSync.
+func WithAsyncEventOrdering() Option {
+	return func(d *Dashgram) {
+		d.orderedAsync = true
+	}
+}
+
+// newOrderedWorkerPool creates a pool whose workers each consume their own
+// lane, a single-worker pool, see WithAsyncEventOrdering
+func newOrderedWorkerPool(size int, queueSize int) *workerPool {
+	if size < 1 {
+		size = 1
+	}
+
+	pool := &workerPool{size: size, lanes: make([]*workerPool, size)}
+	for i := range pool.lanes {
+		pool.lanes[i] = newWorkerPool(1, queueSize)
+	}
+	return pool
+}
+
+// laneFor returns the lane of the pool that tasks with the given ordering key
+// are routed to, the pool itself if it has no lanes. Tasks without a key are
+// spread round-robin.
+func (p *workerPool) laneFor(key string) *workerPool {
+	if len(p.lanes) == 0 {
+		return p
+	}
+
+	if key == "" {
+		return p.lanes[(p.nextLane.Add(1)-1)%uint64(len(p.lanes))]
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return p.lanes[h.Sum32()%uint32(len(p.lanes))]
+}
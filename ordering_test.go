@@ -0,0 +1,133 @@
+package dashgram
+
+import (
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WithAsyncEventOrdering(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:    "worker batches",
+			options: []Option{WithWorkerBatchSize(3), WithWorkerBatchFlushInterval(time.Millisecond)},
+		},
+		{
+			name:    "full queues with fallback to sync",
+			options: []Option{WithQueueSize(1), WithAsyncFallbackToSync()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testAsyncEventOrdering(t, tt.options...)
+		})
+	}
+}
+
+// testAsyncEventOrdering tracks the events of several users and checks each
+// user's events arrive in order
+func testAsyncEventOrdering(t *testing.T, options ...Option) {
+	const users = 5
+	const eventsPerUser = 40
+
+	var mu sync.Mutex
+	received := make(map[int64][]int)
+	client := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			var body struct {
+				Updates []struct {
+					UserID int64 `json:"user_id"`
+					Seq    int   `json:"seq"`
+				} `json:"updates"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+
+			// Random latency makes workers overtake each other
+			time.Sleep(time.Duration(rand.Intn(500)) * time.Microsecond)
+
+			mu.Lock()
+			for _, update := range body.Updates {
+				received[update.UserID] = append(received[update.UserID], update.Seq)
+			}
+			mu.Unlock()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	options = append([]Option{WithHTTPClient(client), WithUseAsync(), WithNumWorkers(4), WithAsyncEventOrdering()}, options...)
+	d := New(123, "test-key", options...)
+	for seq := 0; seq < eventsPerUser; seq++ {
+		for userID := int64(1); userID <= users; userID++ {
+			var opts []CallOption
+			if seq%3 == 2 {
+				// Call headers keep the event out of merged batches
+				opts = append(opts, WithCallHeader("X-Seq", strconv.Itoa(seq)))
+			}
+			if err := d.TrackEventAsync(map[string]any{"user_id": userID, "seq": seq}, opts...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	d.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for userID := int64(1); userID <= users; userID++ {
+		seqs := received[userID]
+		if len(seqs) != eventsPerUser {
+			t.Fatalf("user %d: expected %d events, got %d", userID, eventsPerUser, len(seqs))
+		}
+		for i, seq := range seqs {
+			if seq != i {
+				t.Fatalf("user %d: expected events in order, got %v", userID, seqs)
+			}
+		}
+	}
+}
+
+func TestWorkerPool_LaneFor(t *testing.T) {
+	pool := newOrderedWorkerPool(4, 10)
+
+	for userID := 0; userID < 20; userID++ {
+		key := strconv.Itoa(userID)
+		if first, second := pool.laneFor(key), pool.laneFor(key); first != second {
+			t.Errorf("expected user %s to always use the same lane", key)
+		}
+	}
+
+	used := make(map[*workerPool]int)
+	for i := 0; i < 8; i++ {
+		used[pool.laneFor("")]++
+	}
+	if len(used) != 4 {
+		t.Errorf("expected tasks without a key to use all 4 lanes, got %d", len(used))
+	}
+	for _, count := range used {
+		if count != 2 {
+			t.Errorf("expected tasks without a key to be spread evenly, got %v", used)
+			break
+		}
+	}
+
+	if unordered := newWorkerPool(4, 10); unordered.laneFor("42") != unordered {
+		t.Error("expected a pool without lanes to route to itself")
+	}
+}
@@ -0,0 +1,87 @@
+package dashgram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// WithSwallowPanics makes RecoverAndTrack and RecoverMiddleware swallow the
+// panics they track instead of re-panicking, e.g. so a bot keeps serving
+// other users after a handler crashed
+func WithSwallowPanics() Option {
+	return func(d *Dashgram) {
+		d.swallowPanics = true
+	}
+}
+
+// RecoverAndTrack wraps handler so its panics are tracked as events:
+//
+//	{"action": "panic", "properties": {"value": "runtime error: index out of range [3] with length 3", "stack": ["main.handle (bot.go:42)", ...]}}
+//
+// The event is sent synchronously, even with WithUseAsync, before the panic
+// is propagated, so the crash report isn't lost when the panic ends the
+// process. The panic is then re-raised with the recovered value, or swallowed
+// with WithSwallowPanics. Sampling and WithBeforeSend apply to the event.
+func (d *Dashgram) RecoverAndTrack(handler func()) func() {
+	return func() {
+		defer d.recoverPanic(nil, nil)
+		handler()
+	}
+}
+
+// RecoverMiddleware is RecoverAndTrack for HTTP handlers. The event also holds
+// the method and path of the request. Swallowed panics are answered with a
+// 500 Internal Server Error; re-raised ones are left to the http.Server, which
+// logs them and aborts the connection.
+func (d *Dashgram) RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer d.recoverPanic(func(properties map[string]any) {
+			properties["method"] = r.Method
+			properties["path"] = r.URL.Path
+		}, func() {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		})
+		next.ServeHTTP(w, r)
+	})
+}
+
+// recoverPanic recovers a panic of the deferring function and tracks it. The
+// properties of the event are passed to describe, and swallowed, if any, is
+// called when the panic is swallowed. It must be deferred directly.
+func (d *Dashgram) recoverPanic(describe func(properties map[string]any), swallowed func()) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	// Skip runtime.Callers, stackTrace and recoverPanic, the runtime frames
+	// of the panic are dropped by stackTrace
+	properties := map[string]any{
+		"value": fmt.Sprint(value),
+		"stack": stackTrace(3),
+	}
+	if describe != nil {
+		describe(properties)
+	}
+	d.trackPanic(map[string]any{"action": "panic", "properties": properties})
+
+	if !d.swallowPanics {
+		panic(value)
+	}
+	if swallowed != nil {
+		swallowed()
+	}
+}
+
+// trackPanic sends the panic event synchronously, bypassing the async queue
+// and WithDebounce
+func (d *Dashgram) trackPanic(event map[string]any) {
+	ctx := context.Background()
+	if d.skipEvent(ctx, event, nil) {
+		return
+	}
+	if err := d.sendEvent(ctx, event, nil); err != nil {
+		d.log(LogLevelError, "failed to track panic", "error", err)
+	}
+}
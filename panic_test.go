@@ -0,0 +1,130 @@
+package dashgram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// panicEvent decodes the panic event of the last request
+func panicEvent(t *testing.T, helper *TestHelper) map[string]any {
+	t.Helper()
+
+	var body struct {
+		Updates []map[string]any `json:"updates"`
+	}
+	if err := json.Unmarshal(helper.LastRequest().Body, &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if len(body.Updates) != 1 || body.Updates[0]["action"] != "panic" {
+		t.Fatalf("expected a panic event, got %v", body.Updates)
+	}
+	return body.Updates[0]["properties"].(map[string]any)
+}
+
+func TestDashgram_RecoverAndTrack(t *testing.T) {
+	tests := []struct {
+		name            string
+		options         []Option
+		expectedRepanic bool
+	}{
+		{
+			name:            "re-panics",
+			expectedRepanic: true,
+		},
+		{
+			name:            "re-panics after sending with async",
+			options:         []Option{WithUseAsync()},
+			expectedRepanic: true,
+		},
+		{
+			name:    "swallows",
+			options: []Option{WithSwallowPanics()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", append([]Option{WithHTTPClient(helper.MockHTTPClient())}, tt.options...)...)
+			defer d.Close()
+
+			var recovered any
+			func() {
+				defer func() {
+					recovered = recover()
+				}()
+				d.RecoverAndTrack(func() {
+					panic("boom")
+				})()
+			}()
+
+			if repanicked := recovered != nil; repanicked != tt.expectedRepanic {
+				t.Errorf("expected re-panic %v, got %v", tt.expectedRepanic, recovered)
+			}
+			if tt.expectedRepanic && recovered != "boom" {
+				t.Errorf("expected the recovered value boom, got %v", recovered)
+			}
+
+			// The event was sent before RecoverAndTrack returned or re-panicked
+			if helper.RequestCount != 1 {
+				t.Fatalf("expected 1 request, got %d", helper.RequestCount)
+			}
+			properties := panicEvent(t, helper)
+			if properties["value"] != "boom" {
+				t.Errorf("expected value boom, got %v", properties["value"])
+			}
+			stack, _ := properties["stack"].([]any)
+			if len(stack) == 0 || !strings.Contains(stack[0].(string), "TestDashgram_RecoverAndTrack") {
+				t.Errorf("expected the stack to start at the panic, got %v", stack)
+			}
+		})
+	}
+}
+
+func TestDashgram_RecoverAndTrackNoPanic(t *testing.T) {
+	helper := NewTestHelper()
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	called := false
+	d.RecoverAndTrack(func() { called = true })()
+
+	if !called {
+		t.Error("expected the handler to be called")
+	}
+	if helper.RequestCount != 0 {
+		t.Errorf("expected no request, got %d", helper.RequestCount)
+	}
+}
+
+func TestDashgram_RecoverMiddleware(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithSwallowPanics())
+	defer d.Close()
+
+	handler := d.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var users []string
+		_ = users[3]
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", rec.Code)
+	}
+	properties := panicEvent(t, helper)
+	if value, _ := properties["value"].(string); !strings.Contains(value, "index out of range") {
+		t.Errorf("expected an index out of range value, got %v", properties["value"])
+	}
+	if properties["method"] != http.MethodPost || properties["path"] != "/webhook" {
+		t.Errorf("expected method POST and path /webhook, got %v %v", properties["method"], properties["path"])
+	}
+}
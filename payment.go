@@ -0,0 +1,86 @@
+package dashgram
+
+import "context"
+
+// Payment describes a revenue event
+type Payment struct {
+	UserID int64
+	// Amount is the price in the smallest units of the currency (e.g. cents)
+	Amount int64
+	// Currency is the three-letter ISO 4217 currency code, or "XTR" for Telegram Stars
+	Currency       string
+	Product        string
+	InvoicePayload string
+	IsRecurring    bool
+}
+
+// NewPaymentFromSuccessfulPayment creates a Payment from a Telegram SuccessfulPayment
+func NewPaymentFromSuccessfulPayment(userID int64, sp SuccessfulPayment) Payment {
+	return Payment{
+		UserID:         userID,
+		Amount:         sp.TotalAmount,
+		Currency:       sp.Currency,
+		InvoicePayload: sp.InvoicePayload,
+		IsRecurring:    sp.IsRecurring,
+	}
+}
+
+// validate checks the payment before it is tracked
+func (p Payment) validate() error {
+	if p.UserID <= 0 {
+		return &ValidationError{Field: "UserID", Message: "must be positive"}
+	}
+	if p.Amount < 0 {
+		return &ValidationError{Field: "Amount", Message: "must not be negative"}
+	}
+	if p.Currency == "" {
+		return &ValidationError{Field: "Currency", Message: "must not be empty"}
+	}
+	return nil
+}
+
+// event returns the payment in the conventional event shape
+func (p Payment) event() map[string]any {
+	properties := map[string]any{
+		"amount":       p.Amount,
+		"currency":     p.Currency,
+		"is_recurring": p.IsRecurring,
+	}
+	if p.Product != "" {
+		properties["product"] = p.Product
+	}
+	if p.InvoicePayload != "" {
+		properties["invoice_payload"] = p.InvoicePayload
+	}
+
+	return map[string]any{
+		"action":     "payment",
+		"user_id":    p.UserID,
+		"properties": properties,
+	}
+}
+
+// TrackPayment tracks a payment made by a user, as a "payment" event whose
+// properties hold the amount, currency and, if set, product and invoice payload:
+//
+//	{"action": "payment", "user_id": 42, "properties": {"amount": 499, "currency": "USD", "is_recurring": false}}
+func (d *Dashgram) TrackPayment(ctx context.Context, p Payment) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	return d.TrackEventWithContext(ctx, p.event())
+}
+
+// TrackPaymentAsyncWithContext validates the payment and enqueues it to be tracked asynchronously
+func (d *Dashgram) TrackPaymentAsyncWithContext(ctx context.Context, p Payment) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	return d.TrackEventAsyncWithContext(ctx, p.event())
+}
+
+func (d *Dashgram) TrackPaymentAsync(p Payment) error {
+	return d.TrackPaymentAsyncWithContext(context.Background(), p)
+}
@@ -0,0 +1,144 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashgram_TrackPayment(t *testing.T) {
+	tests := []struct {
+		name          string
+		payment       Payment
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "one-off payment",
+			payment: Payment{
+				UserID:         12345,
+				Amount:         499,
+				Currency:       "USD",
+				Product:        "premium_month",
+				InvoicePayload: "invoice-1",
+			},
+			expected: `{"updates":[{"action":"payment","properties":{"amount":499,"currency":"USD","invoice_payload":"invoice-1","is_recurring":false,"product":"premium_month"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name: "recurring stars payment",
+			payment: Payment{
+				UserID:      12345,
+				Amount:      100,
+				Currency:    "XTR",
+				IsRecurring: true,
+			},
+			expected: `{"updates":[{"action":"payment","properties":{"amount":100,"currency":"XTR","is_recurring":true},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "missing user ID",
+			payment:       Payment{Amount: 100, Currency: "USD"},
+			expectedError: "invalid UserID: must be positive",
+		},
+		{
+			name:          "negative amount",
+			payment:       Payment{UserID: 12345, Amount: -1, Currency: "USD"},
+			expectedError: "invalid Amount: must not be negative",
+		},
+		{
+			name:          "missing currency",
+			payment:       Payment{UserID: 12345, Amount: 100},
+			expectedError: "invalid Currency: must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					body, _ = io.ReadAll(req.Body)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+					}, nil
+				},
+			}
+
+			d := New(123, "test-key", WithHTTPClient(mockClient))
+			defer d.Close()
+
+			err := d.TrackPayment(context.Background(), tt.payment)
+
+			if tt.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error '%s', got nil", tt.expectedError)
+				} else if err.Error() != tt.expectedError {
+					t.Errorf("expected error '%s', got '%s'", tt.expectedError, err.Error())
+				}
+				if body != nil {
+					t.Errorf("expected no request for invalid payment")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(body) != tt.expected {
+				t.Errorf("expected body '%s', got '%s'", tt.expected, string(body))
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackPaymentAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	if err := d.TrackPaymentAsync(Payment{UserID: 12345, Amount: 100}); err == nil {
+		t.Errorf("expected validation error for missing currency")
+	}
+
+	if err := d.TrackPaymentAsync(Payment{UserID: 12345, Amount: 100, Currency: "USD"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if !helper.WaitForRequests(1, time.Second) {
+		t.Errorf("expected payment to be tracked")
+	}
+}
+
+func TestNewPaymentFromSuccessfulPayment(t *testing.T) {
+	var sp SuccessfulPayment
+	err := json.Unmarshal([]byte(`{
+		"currency": "EUR",
+		"total_amount": 1999,
+		"invoice_payload": "order-42",
+		"telegram_payment_charge_id": "tg-charge",
+		"provider_payment_charge_id": "provider-charge",
+		"is_recurring": true
+	}`), &sp)
+	if err != nil {
+		t.Fatalf("failed to unmarshal SuccessfulPayment: %v", err)
+	}
+
+	p := NewPaymentFromSuccessfulPayment(12345, sp)
+
+	expected := Payment{
+		UserID:         12345,
+		Amount:         1999,
+		Currency:       "EUR",
+		InvoicePayload: "order-42",
+		IsRecurring:    true,
+	}
+	if p != expected {
+		t.Errorf("expected payment %+v, got %+v", expected, p)
+	}
+}
@@ -0,0 +1,83 @@
+package dashgram
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pingCache holds the last outcome of Ping for WithPingCache
+type pingCache struct {
+	mu  sync.Mutex
+	ttl time.Duration
+	err error
+	at  time.Time
+	ok  bool
+	// generation changes on every invalidation, so a Ping racing with an
+	// auth failure doesn't cache a stale outcome
+	generation uint64
+}
+
+// WithPingCache makes Ping return the outcome of the previous call, success
+// or failure, for ttl instead of hitting the network again, e.g. for
+// frequent readiness probes. The cache is cleared whenever a request fails
+// with an InvalidCredentialsError or ForbiddenError. ValidateCredentials is
+// never cached.
+func WithPingCache(ttl time.Duration) Option {
+	return func(d *Dashgram) {
+		d.pingCache.ttl = ttl
+	}
+}
+
+// cachedPing returns the cache generation to store a new outcome with, and
+// the cached Ping outcome if there is one
+func (d *Dashgram) cachedPing() (generation uint64, ok bool, err error) {
+	d.pingCache.mu.Lock()
+	defer d.pingCache.mu.Unlock()
+
+	if d.pingCache.ok && d.now().Sub(d.pingCache.at) < d.pingCache.ttl {
+		return d.pingCache.generation, true, d.pingCache.err
+	}
+	return d.pingCache.generation, false, nil
+}
+
+// storePing caches a Ping outcome unless the cache was invalidated since generation
+func (d *Dashgram) storePing(err error, generation uint64) {
+	d.pingCache.mu.Lock()
+	defer d.pingCache.mu.Unlock()
+
+	if d.pingCache.generation != generation {
+		return
+	}
+	d.pingCache.err = err
+	d.pingCache.at = d.now()
+	d.pingCache.ok = true
+}
+
+// invalidatePing clears the cached Ping outcome
+func (d *Dashgram) invalidatePing() {
+	if d.pingCache.ttl <= 0 {
+		return
+	}
+
+	d.pingCache.mu.Lock()
+	defer d.pingCache.mu.Unlock()
+
+	d.pingCache.ok = false
+	d.pingCache.generation++
+}
+
+// pingWithCache is Ping with WithPingCache
+func (d *Dashgram) pingWithCache(ctx context.Context) error {
+	generation, ok, err := d.cachedPing()
+	if ok {
+		return err
+	}
+
+	err = d.ValidateCredentials(ctx)
+	if ctx.Err() == nil {
+		// A cancelled context says nothing about the API
+		d.storePing(err, generation)
+	}
+	return err
+}
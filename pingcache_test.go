@@ -0,0 +1,110 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WithPingCache(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	clock := newFakeClock()
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithPingCache(time.Minute), withClock(clock.Now))
+	defer d.Close()
+
+	for i := 0; i < 10; i++ {
+		if err := d.Ping(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if helper.RequestCount != 1 {
+		t.Fatalf("expected 1 request for pings within the TTL, got %d", helper.RequestCount)
+	}
+
+	clock.Advance(time.Minute)
+	if err := d.Ping(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if helper.RequestCount != 2 {
+		t.Errorf("expected a new request once the TTL elapsed, got %d requests", helper.RequestCount)
+	}
+}
+
+func TestDashgram_WithPingCacheCachesFailures(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusInternalServerError, `{"status":"error","details":"down"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithPingCache(time.Minute))
+	defer d.Close()
+
+	for i := 0; i < 3; i++ {
+		var apiErr *DashgramAPIError
+		if err := d.Ping(context.Background()); !errors.As(err, &apiErr) {
+			t.Fatalf("expected DashgramAPIError, got %v", err)
+		}
+	}
+	if helper.RequestCount != 1 {
+		t.Errorf("expected the failure to be cached, got %d requests", helper.RequestCount)
+	}
+}
+
+func TestDashgram_WithPingCacheInvalidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+	}{
+		{name: "unauthorized", statusCode: http.StatusUnauthorized},
+		{name: "forbidden", statusCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+			helper.AddResponse(tt.statusCode, `{"status":"error","details":"denied"}`)
+			helper.AddResponse(tt.statusCode, `{"status":"error","details":"denied"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithPingCache(time.Minute))
+			defer d.Close()
+
+			if err := d.Ping(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// An auth failure seen by another call clears the cached success
+			if err := d.TrackEvent(map[string]any{"action": "click"}); err == nil {
+				t.Fatal("expected the event to be rejected")
+			}
+
+			if err := d.Ping(context.Background()); err == nil {
+				t.Error("expected Ping to hit the network again and fail")
+			}
+			if helper.RequestCount != 3 {
+				t.Errorf("expected 3 requests, got %d", helper.RequestCount)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithPingCacheSkipsCancelledContext(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddError(context.Canceled)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithPingCache(time.Minute))
+	defer d.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d.Ping(ctx)
+
+	if err := d.Ping(context.Background()); err != nil {
+		t.Errorf("expected the cancelled ping not to be cached, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package dashgram
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// requestBody is a pooled request body. It's returned to the pool when the
+// HTTP client closes it, so the buffer is never reused while still being sent.
+type requestBody struct {
+	buf      bytes.Buffer
+	enc      *json.Encoder
+	released bool
+}
+
+var requestBodyPool = sync.Pool{
+	New: func() any {
+		body := &requestBody{}
+		body.enc = json.NewEncoder(&body.buf)
+		return body
+	},
+}
+
+// newRequestBody encodes data into a pooled request body
+func newRequestBody(data any) (*requestBody, error) {
+	body := requestBodyPool.Get().(*requestBody)
+	body.buf.Reset()
+	body.released = false
+
+	if err := body.enc.Encode(data); err != nil {
+		body.Close()
+		return nil, err
+	}
+	// Encode terminates the value with a newline, json.Marshal doesn't
+	body.buf.Truncate(body.buf.Len() - 1)
+
+	return body, nil
+}
+
+// Bytes returns the encoded body, valid until the body is read or closed
+func (b *requestBody) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+func (b *requestBody) Read(p []byte) (int, error) {
+	return b.buf.Read(p)
+}
+
+// Close returns the body to the pool, it's safe to call more than once
+func (b *requestBody) Close() error {
+	if b.released {
+		return nil
+	}
+	b.released = true
+
+	// Don't keep oversized buffers alive in the pool
+	if b.buf.Cap() > 64<<10 {
+		return nil
+	}
+	b.buf.Reset()
+	requestBodyPool.Put(b)
+	return nil
+}
+
+var trackEventRequestPool = sync.Pool{
+	New: func() any {
+		return &TrackEventRequest{Updates: make([]any, 0, 1)}
+	},
+}
+
+// getTrackEventRequest returns a pooled single-update track request. It must
+// only be used for synchronous requests and released with putTrackEventRequest.
+func getTrackEventRequest(origin string, update any) *TrackEventRequest {
+	req := trackEventRequestPool.Get().(*TrackEventRequest)
+	req.Origin = origin
+	req.Updates = append(req.Updates[:0], update)
+	return req
+}
+
+// putTrackEventRequest clears the request so the pool doesn't retain the event
+func putTrackEventRequest(req *TrackEventRequest) {
+	req.Origin = ""
+	req.Updates[0] = nil
+	req.Updates = req.Updates[:0]
+	trackEventRequestPool.Put(req)
+}
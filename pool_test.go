@@ -0,0 +1,92 @@
+package dashgram
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestNewRequestBody(t *testing.T) {
+	data := TrackEventRequest{
+		Origin:  "Test App",
+		Updates: []any{map[string]any{"action": "click", "html": "<b>"}},
+	}
+
+	body, err := newRequestBody(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, _ := json.Marshal(data)
+	if !bytes.Equal(got, expected) {
+		t.Errorf("expected body '%s', got '%s'", expected, got)
+	}
+
+	if err := body.Close(); err != nil {
+		t.Errorf("expected no error on close, got %v", err)
+	}
+	if err := body.Close(); err != nil {
+		t.Errorf("expected no error on second close, got %v", err)
+	}
+}
+
+func TestNewRequestBody_Error(t *testing.T) {
+	if _, err := newRequestBody(make(chan int)); err == nil {
+		t.Error("expected error for unsupported value, got nil")
+	}
+}
+
+func TestTrackEventRequestPool(t *testing.T) {
+	req := getTrackEventRequest("Test App", "event")
+	if req.Origin != "Test App" || len(req.Updates) != 1 || req.Updates[0] != "event" {
+		t.Fatalf("unexpected pooled request: %+v", req)
+	}
+
+	updates := req.Updates[:1]
+	putTrackEventRequest(req)
+	if updates[0] != nil {
+		t.Errorf("expected released request not to retain the event, got %v", updates[0])
+	}
+}
+
+func TestRequestBodyAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items at random under the race detector")
+	}
+
+	data := getTrackEventRequest("Test App", TestEventData)
+	defer putTrackEventRequest(data)
+
+	baseline := testing.AllocsPerRun(100, func() {
+		jsonData, _ := json.Marshal(data)
+		_ = bytes.NewBuffer(jsonData)
+	})
+	pooled := testing.AllocsPerRun(100, func() {
+		body, _ := newRequestBody(data)
+		body.Close()
+	})
+
+	if pooled >= baseline {
+		t.Errorf("expected fewer than %.1f allocs/op, got %.1f", baseline, pooled)
+	}
+}
+
+func BenchmarkRequestBody(b *testing.B) {
+	data := getTrackEventRequest("Test App", TestEventData)
+	defer putTrackEventRequest(data)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		body, err := newRequestBody(data)
+		if err != nil {
+			b.Fatalf("newRequestBody failed: %v", err)
+		}
+		body.Close()
+	}
+}
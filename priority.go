@@ -0,0 +1,35 @@
+package dashgram
+
+// Priority orders the async tasks waiting in a worker pool's queue. Higher
+// priority tasks are sent first; tasks of the same priority keep their order.
+type Priority int
+
+const (
+	// PriorityLow is for tasks that can wait, such as page views
+	PriorityLow Priority = -1
+	// PriorityNormal is the default priority
+	PriorityNormal Priority = 0
+	// PriorityHigh is for critical tasks, such as purchases
+	PriorityHigh Priority = 1
+)
+
+// WithPriority sets the priority of an async task in the queue. It has no
+// effect on synchronous calls.
+func WithPriority(priority Priority) CallOption {
+	return func(co *callOptions) {
+		co.priority = priority
+	}
+}
+
+// index returns the position of the priority's queue in a worker pool, out of
+// range priorities are clamped to the nearest level
+func (p Priority) index() int {
+	switch {
+	case p >= PriorityHigh:
+		return 0
+	case p <= PriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,90 @@
+package dashgram
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WithPriority(t *testing.T) {
+	var mu sync.Mutex
+	var actions []string
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	helper := NewTestHelper()
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			var body struct {
+				Updates []map[string]string `json:"updates"`
+			}
+			json.NewDecoder(req.Body).Decode(&body)
+
+			mu.Lock()
+			actions = append(actions, body.Updates[0]["action"])
+			mu.Unlock()
+
+			select {
+			case started <- struct{}{}:
+				// Pause the worker on the first task
+				<-release
+			default:
+			}
+
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+			return helper.MockHTTPClient().Do(req)
+		},
+	}
+
+	d := New(123, "test-key", WithHTTPClient(mockClient))
+	defer d.Close()
+
+	d.TrackEventAsync(map[string]any{"action": "blocking"})
+	<-started
+
+	d.TrackEventAsync(map[string]any{"action": "view_1"}, WithPriority(PriorityLow))
+	d.TrackEventAsync(map[string]any{"action": "click_1"})
+	d.TrackEventAsync(map[string]any{"action": "purchase_1"}, WithPriority(PriorityHigh))
+	d.TrackEventAsync(map[string]any{"action": "view_2"}, WithPriority(PriorityLow))
+	d.TrackEventAsync(map[string]any{"action": "click_2"}, WithPriority(PriorityNormal))
+	d.TrackEventAsync(map[string]any{"action": "purchase_2"}, WithPriority(PriorityHigh))
+
+	close(release)
+	if !helper.WaitForRequests(7, time.Second) {
+		t.Fatal("expected all tasks to be processed")
+	}
+
+	expected := []string{"blocking", "purchase_1", "purchase_2", "click_1", "click_2", "view_1", "view_2"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(actions) != len(expected) {
+		t.Fatalf("expected %d requests, got %d", len(expected), len(actions))
+	}
+	for i := range expected {
+		if actions[i] != expected[i] {
+			t.Errorf("expected processing order %v, got %v", expected, actions)
+			break
+		}
+	}
+}
+
+func TestPriority_index(t *testing.T) {
+	tests := []struct {
+		priority Priority
+		expected int
+	}{
+		{priority: PriorityHigh, expected: 0},
+		{priority: 5, expected: 0},
+		{priority: PriorityNormal, expected: 1},
+		{priority: PriorityLow, expected: 2},
+		{priority: -5, expected: 2},
+	}
+
+	for _, tt := range tests {
+		if got := tt.priority.index(); got != tt.expected {
+			t.Errorf("expected index %d for priority %d, got %d", tt.expected, tt.priority, got)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package dashgram
+
+import (
+	"io"
+	"net/http"
+)
+
+// WithHTTPKeepAliveProbe pre-warms a connection to the API when the client is
+// created, so the first event doesn't pay for the TCP and TLS handshakes. New
+// sends an unauthenticated OPTIONS request to the API URL in the background
+// and ignores its outcome.
+func WithHTTPKeepAliveProbe() Option {
+	return func(d *Dashgram) {
+		d.keepAliveProbe = true
+	}
+}
+
+// startKeepAliveProbe sends the warm-up request of WithHTTPKeepAliveProbe
+func (d *Dashgram) startKeepAliveProbe() {
+	if !d.keepAliveProbe || d.configErr != nil {
+		return
+	}
+
+	d.workerWg.Add(1)
+	go func() {
+		defer d.workerWg.Done()
+
+		req, err := http.NewRequestWithContext(d.workerCtx, http.MethodOptions, d.APIURL, nil)
+		if err != nil {
+			return
+		}
+
+		resp, err := d.client.Do(req)
+		if err != nil {
+			d.log(LogLevelDebug, "keep-alive probe failed", "error", d.redactError(err))
+			return
+		}
+		// Drain the body so the connection goes back to the pool
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+}
@@ -0,0 +1,52 @@
+package dashgram
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WithHTTPKeepAliveProbe(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			requests <- req
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		},
+	}
+
+	d := New(123, "test-key", WithHTTPClient(mockClient), WithHTTPKeepAliveProbe())
+	defer d.Close()
+
+	select {
+	case req := <-requests:
+		if req.Method != http.MethodOptions {
+			t.Errorf("expected method OPTIONS, got %s", req.Method)
+		}
+		if expected := "https://api.dashgram.io/v1/123"; req.URL.String() != expected {
+			t.Errorf("expected URL %s, got %s", expected, req.URL)
+		}
+		if auth := req.Header.Get("Authorization"); auth != "" {
+			t.Errorf("expected no Authorization header, got %q", auth)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("expected an OPTIONS request within 50ms of construction")
+	}
+}
+
+func TestDashgram_WithoutHTTPKeepAliveProbe(t *testing.T) {
+	helper := NewTestHelper()
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	time.Sleep(20 * time.Millisecond)
+	d.Close()
+
+	if helper.RequestCount != 0 {
+		t.Errorf("expected no request, got %d", helper.RequestCount)
+	}
+}
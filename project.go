@@ -0,0 +1,40 @@
+package dashgram
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ProjectInfo describes the Dashgram project the client is configured for
+type ProjectInfo struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	// WriteAccess reports whether the access key can track events
+	WriteAccess bool `json:"write_access"`
+	// Plan and Limits are empty if the API doesn't expose them for the project
+	Plan   string           `json:"plan,omitempty"`
+	Limits map[string]int64 `json:"limits,omitempty"`
+}
+
+// GetProjectInfo returns the metadata of the project, e.g. to check which
+// project a bot is wired to. A rejected key is reported with an
+// InvalidCredentialsError or ForbiddenError. It always runs synchronously.
+func (d *Dashgram) GetProjectInfo(ctx context.Context) (*ProjectInfo, error) {
+	var response struct {
+		Project *ProjectInfo `json:"project"`
+	}
+	if _, err := d.do(ctx, http.MethodGet, "project", nil, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Project == nil {
+		return nil, &DashgramAPIError{
+			StatusCode: http.StatusOK,
+			Details:    "missing project in response",
+		}
+	}
+
+	return response.Project, nil
+}
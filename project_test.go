@@ -0,0 +1,95 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDashgram_GetProjectInfo(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok","project":{"id":123,"name":"My Bot","created_at":"2024-05-01T12:00:00Z","write_access":true,"plan":"pro","limits":{"events_per_month":1000000}}}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	info, err := d.GetProjectInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.ID != 123 || info.Name != "My Bot" || !info.WriteAccess || info.Plan != "pro" {
+		t.Errorf("unexpected project info: %+v", info)
+	}
+	if !info.CreatedAt.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected CreatedAt 2024-05-01T12:00:00Z, got %v", info.CreatedAt)
+	}
+	if info.Limits["events_per_month"] != 1000000 {
+		t.Errorf("expected events_per_month limit 1000000, got %d", info.Limits["events_per_month"])
+	}
+
+	req := helper.LastRequest()
+	if req.Method != http.MethodGet {
+		t.Errorf("expected method GET, got %s", req.Method)
+	}
+	if req.URL.Path != "/v1/123/project" {
+		t.Errorf("expected path '/v1/123/project', got '%s'", req.URL.Path)
+	}
+}
+
+func TestDashgram_GetProjectInfoErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		checkError func(error) bool
+	}{
+		{
+			name:       "invalid credentials",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"status":"error","details":"unauthorized"}`,
+			checkError: func(err error) bool {
+				var credErr *InvalidCredentialsError
+				return errors.As(err, &credErr)
+			},
+		},
+		{
+			name:       "forbidden",
+			statusCode: http.StatusForbidden,
+			body:       `{"status":"error","details":"forbidden"}`,
+			checkError: func(err error) bool {
+				var forbiddenErr *ForbiddenError
+				return errors.As(err, &forbiddenErr)
+			},
+		},
+		{
+			name:       "missing project",
+			statusCode: http.StatusOK,
+			body:       `{"status":"success","details":"ok"}`,
+			checkError: func(err error) bool {
+				var apiErr *DashgramAPIError
+				return errors.As(err, &apiErr)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(tt.statusCode, tt.body)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			info, err := d.GetProjectInfo(context.Background())
+			if !tt.checkError(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if info != nil {
+				t.Errorf("expected nil project info, got %+v", info)
+			}
+		})
+	}
+}
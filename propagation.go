@@ -0,0 +1,61 @@
+package dashgram
+
+import "context"
+
+// ContextPropagator extracts values from a request context to send as HTTP headers
+type ContextPropagator interface {
+	Extract(ctx context.Context) map[string]string
+}
+
+// WithContextPropagation sets the headers returned by p on every request.
+// It can be used several times to combine propagators.
+func WithContextPropagation(p ContextPropagator) Option {
+	return func(d *Dashgram) {
+		if p != nil {
+			d.propagators = append(d.propagators, p)
+		}
+	}
+}
+
+// WithTracePropagation forwards the W3C Trace Context of the request context,
+// set with ContextWithW3CTrace, as the traceparent and tracestate headers so
+// server-side logs can be correlated. Requests whose context carries no trace
+// are sent without them. It is WithContextPropagation(W3CTracePropagator{}).
+func WithTracePropagation() Option {
+	return WithContextPropagation(W3CTracePropagator{})
+}
+
+// w3cTraceKey is the context key of the W3C Trace Context values
+type w3cTraceKey struct{}
+
+// w3cTrace holds the W3C Trace Context header values
+type w3cTrace struct {
+	traceparent string
+	tracestate  string
+}
+
+// ContextWithW3CTrace returns a copy of ctx carrying W3C Trace Context values,
+// sent by W3CTracePropagator as the traceparent and tracestate headers
+func ContextWithW3CTrace(ctx context.Context, traceparent, tracestate string) context.Context {
+	return context.WithValue(ctx, w3cTraceKey{}, w3cTrace{
+		traceparent: traceparent,
+		tracestate:  tracestate,
+	})
+}
+
+// W3CTracePropagator propagates the W3C Trace Context values stored with ContextWithW3CTrace
+type W3CTracePropagator struct{}
+
+// Extract returns the traceparent and tracestate headers, or nil if ctx has no trace
+func (W3CTracePropagator) Extract(ctx context.Context) map[string]string {
+	trace, ok := ctx.Value(w3cTraceKey{}).(w3cTrace)
+	if !ok || trace.traceparent == "" {
+		return nil
+	}
+
+	headers := map[string]string{"traceparent": trace.traceparent}
+	if trace.tracestate != "" {
+		headers["tracestate"] = trace.tracestate
+	}
+	return headers
+}
@@ -0,0 +1,137 @@
+package dashgram
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+type staticPropagator map[string]string
+
+func (p staticPropagator) Extract(ctx context.Context) map[string]string {
+	return p
+}
+
+func TestW3CTracePropagator(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected map[string]string
+	}{
+		{
+			name:     "no trace",
+			ctx:      context.Background(),
+			expected: nil,
+		},
+		{
+			name: "traceparent only",
+			ctx:  ContextWithW3CTrace(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ""),
+			expected: map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+		},
+		{
+			name: "traceparent and tracestate",
+			ctx:  ContextWithW3CTrace(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "congo=t61rcWkgMzE"),
+			expected: map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"tracestate":  "congo=t61rcWkgMzE",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := W3CTracePropagator{}.Extract(tt.ctx)
+			if len(headers) != len(tt.expected) {
+				t.Fatalf("expected %d headers, got %v", len(tt.expected), headers)
+			}
+			for key, value := range tt.expected {
+				if headers[key] != value {
+					t.Errorf("expected header %s '%s', got '%s'", key, value, headers[key])
+				}
+			}
+		})
+	}
+}
+
+func TestDashgram_WithContextPropagation(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithContextPropagation(W3CTracePropagator{}),
+		WithContextPropagation(staticPropagator{"X-B3-Sampled": "1"}),
+	)
+	defer d.Close()
+
+	ctx := ContextWithW3CTrace(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "congo=t61rcWkgMzE")
+	if err := d.TrackEventWithContext(ctx, map[string]any{"action": "click"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	headers := helper.LastRequest().Headers
+	expected := map[string]string{
+		"traceparent":  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"tracestate":   "congo=t61rcWkgMzE",
+		"X-B3-Sampled": "1",
+	}
+	for key, value := range expected {
+		if got := headers.Get(key); got != value {
+			t.Errorf("expected header %s '%s', got '%s'", key, value, got)
+		}
+	}
+}
+
+func TestDashgram_WithTracePropagation(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tests := []struct {
+		name                string
+		ctx                 context.Context
+		async               bool
+		expectedTraceparent string
+	}{
+		{
+			name:                "trace in context",
+			ctx:                 ContextWithW3CTrace(context.Background(), traceparent, ""),
+			expectedTraceparent: traceparent,
+		},
+		{
+			name:                "trace in async task context",
+			ctx:                 ContextWithW3CTrace(context.Background(), traceparent, ""),
+			async:               true,
+			expectedTraceparent: traceparent,
+		},
+		{
+			name: "no trace",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			options := []Option{WithHTTPClient(helper.MockHTTPClient()), WithTracePropagation()}
+			if tt.async {
+				options = append(options, WithUseAsync())
+			}
+			d := New(123, "test-key", options...)
+
+			if err := d.TrackEventWithContext(tt.ctx, map[string]any{"action": "click"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			d.Close()
+
+			headers := helper.LastRequest().Headers
+			if got := headers.Get("traceparent"); got != tt.expectedTraceparent {
+				t.Errorf("expected traceparent '%s', got '%s'", tt.expectedTraceparent, got)
+			}
+			if _, ok := headers["Tracestate"]; ok {
+				t.Errorf("expected no tracestate header, got '%s'", headers.Get("tracestate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package dashgram
+
+// raceEnabled reports whether the race detector is on, it makes sync.Pool drop items at random
+const raceEnabled = true
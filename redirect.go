@@ -0,0 +1,47 @@
+package dashgram
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// WithHTTPRedirectPolicy sets how the HTTP client handles 3xx redirects, as
+// its CheckRedirect function, e.g. NoFollow to treat a redirect as a
+// misconfigured APIURL. By default up to 10 redirects are followed. It only
+// applies to *http.Client clients, which are copied so other users of the
+// client are unaffected.
+func WithHTTPRedirectPolicy(policy func(req *http.Request, via []*http.Request) error) Option {
+	return func(d *Dashgram) {
+		d.redirectPolicy = policy
+	}
+}
+
+// NoFollow is a redirect policy that fails the request on any redirect
+func NoFollow(req *http.Request, via []*http.Request) error {
+	return fmt.Errorf("%w to %s", ErrRedirect, req.URL.Redacted())
+}
+
+// FollowOnce is a redirect policy that follows a single redirect and fails
+// the request on the next one
+func FollowOnce(req *http.Request, via []*http.Request) error {
+	if len(via) > 1 {
+		return fmt.Errorf("%w to %s", ErrRedirect, req.URL.Redacted())
+	}
+	return nil
+}
+
+// configureRedirects sets the redirect policy on the HTTP client
+func (d *Dashgram) configureRedirects() {
+	if d.redirectPolicy == nil {
+		return
+	}
+
+	client, ok := d.client.(*http.Client)
+	if !ok {
+		return
+	}
+
+	configured := *client
+	configured.CheckRedirect = d.redirectPolicy
+	d.client = &configured
+}
@@ -0,0 +1,106 @@
+package dashgram
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WithHTTPRedirectPolicy(t *testing.T) {
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/twice/"):
+			http.Redirect(w, r, "/once/"+strings.TrimPrefix(r.URL.Path, "/twice/"), http.StatusMovedPermanently)
+		case strings.HasPrefix(r.URL.Path, "/once/"):
+			http.Redirect(w, r, "/final", http.StatusMovedPermanently)
+		default:
+			w.Write([]byte(`{"status":"success","details":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		path         string
+		options      []Option
+		expectedHits int64
+		wantErr      bool
+	}{
+		{
+			name:         "follows by default",
+			path:         "/twice",
+			expectedHits: 3,
+		},
+		{
+			name:         "no follow",
+			path:         "/once",
+			options:      []Option{WithHTTPRedirectPolicy(NoFollow)},
+			expectedHits: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "follow once",
+			path:         "/once",
+			options:      []Option{WithHTTPRedirectPolicy(FollowOnce)},
+			expectedHits: 2,
+		},
+		{
+			name:         "follow once refuses second redirect",
+			path:         "/twice",
+			options:      []Option{WithHTTPRedirectPolicy(FollowOnce)},
+			expectedHits: 2,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits.Store(0)
+
+			options := append([]Option{
+				WithAPIURL(server.URL + tt.path),
+				WithHTTPClient(server.Client()),
+				WithRetry(2, time.Millisecond),
+			}, tt.options...)
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			err := d.TrackEvent(map[string]any{"action": "click"})
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrRedirect) {
+					t.Errorf("expected ErrRedirect, got %v", err)
+				}
+				var transportErr *TransportError
+				if !errors.As(err, &transportErr) {
+					t.Errorf("expected TransportError, got %T", err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if got := hits.Load(); got != tt.expectedHits {
+				t.Errorf("expected %d requests, got %d", tt.expectedHits, got)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithHTTPRedirectPolicyCopiesClient(t *testing.T) {
+	client := &http.Client{}
+	d := New(123, "test-key", WithHTTPClient(client), WithHTTPRedirectPolicy(NoFollow))
+	defer d.Close()
+
+	if client.CheckRedirect != nil {
+		t.Error("expected the given client to be left unchanged")
+	}
+	if configured := d.client.(*http.Client); configured.CheckRedirect == nil {
+		t.Error("expected the redirect policy to be set")
+	}
+}
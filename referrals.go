@@ -0,0 +1,81 @@
+package dashgram
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Referral is a user invited by another user
+type Referral struct {
+	InvitedUserID int64     `json:"invited_user_id"`
+	InvitedAt     time.Time `json:"invited_at"`
+}
+
+// QueryOption configures a read request
+type QueryOption func(*queryOptions)
+
+// queryOptions holds the pagination parameters of a read request
+type queryOptions struct {
+	limit  int
+	offset int
+}
+
+// WithLimit sets the maximum number of results returned by a read request
+func WithLimit(limit int) QueryOption {
+	return func(qo *queryOptions) {
+		qo.limit = limit
+	}
+}
+
+// WithOffset sets the number of results to skip, for paginating a read request
+func WithOffset(offset int) QueryOption {
+	return func(qo *queryOptions) {
+		qo.offset = offset
+	}
+}
+
+// values encodes the query options as URL query parameters
+func (qo queryOptions) values() url.Values {
+	values := url.Values{}
+	if qo.limit > 0 {
+		values.Set("limit", strconv.Itoa(qo.limit))
+	}
+	if qo.offset > 0 {
+		values.Set("offset", strconv.Itoa(qo.offset))
+	}
+	return values
+}
+
+// GetReferrals returns the users invited by userID. Use WithLimit and
+// WithOffset to page through the results. It always runs synchronously.
+func (d *Dashgram) GetReferrals(ctx context.Context, userID int64, opts ...QueryOption) ([]Referral, error) {
+	if userID <= 0 {
+		return nil, &ValidationError{Field: "userID", Message: "must be positive"}
+	}
+
+	var qo queryOptions
+	for _, opt := range opts {
+		opt(&qo)
+	}
+	if qo.limit < 0 {
+		return nil, &ValidationError{Field: "limit", Message: "must not be negative"}
+	}
+	if qo.offset < 0 {
+		return nil, &ValidationError{Field: "offset", Message: "must not be negative"}
+	}
+
+	query := qo.values()
+	query.Set("user_id", strconv.FormatInt(userID, 10))
+
+	var response struct {
+		Referrals []Referral `json:"referrals"`
+	}
+	if _, err := d.do(ctx, http.MethodGet, "referrals", query, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Referrals, nil
+}
@@ -0,0 +1,107 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDashgram_GetReferrals(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok","referrals":[{"invited_user_id":111,"invited_at":"2024-05-01T12:00:00Z"},{"invited_user_id":222,"invited_at":"2024-05-02T12:00:00Z"}]}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	referrals, err := d.GetReferrals(context.Background(), 42, WithLimit(2), WithOffset(10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Referral{
+		{InvitedUserID: 111, InvitedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+		{InvitedUserID: 222, InvitedAt: time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)},
+	}
+	if len(referrals) != len(expected) {
+		t.Fatalf("expected %d referrals, got %d", len(expected), len(referrals))
+	}
+	for i := range expected {
+		if referrals[i].InvitedUserID != expected[i].InvitedUserID || !referrals[i].InvitedAt.Equal(expected[i].InvitedAt) {
+			t.Errorf("expected referral %+v, got %+v", expected[i], referrals[i])
+		}
+	}
+
+	req := helper.LastRequest()
+	if req.Method != http.MethodGet {
+		t.Errorf("expected method GET, got %s", req.Method)
+	}
+	if req.URL.Path != "/v1/123/referrals" {
+		t.Errorf("expected path '/v1/123/referrals', got '%s'", req.URL.Path)
+	}
+	query := req.URL.Query()
+	for key, value := range map[string]string{"user_id": "42", "limit": "2", "offset": "10"} {
+		if got := query.Get(key); got != value {
+			t.Errorf("expected query %s=%s, got '%s'", key, value, got)
+		}
+	}
+	if len(req.Body) != 0 {
+		t.Errorf("expected empty body, got '%s'", req.Body)
+	}
+	if got := req.Headers.Get("Content-Type"); got != "" {
+		t.Errorf("expected no Content-Type header, got '%s'", got)
+	}
+}
+
+func TestDashgram_GetReferralsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int64
+		opts   []QueryOption
+		field  string
+	}{
+		{name: "zero user ID", userID: 0, field: "userID"},
+		{name: "negative limit", userID: 1, opts: []QueryOption{WithLimit(-1)}, field: "limit"},
+		{name: "negative offset", userID: 1, opts: []QueryOption{WithOffset(-1)}, field: "offset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			_, err := d.GetReferrals(context.Background(), tt.userID, tt.opts...)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("expected field '%s', got '%s'", tt.field, validationErr.Field)
+			}
+			if helper.RequestCount != 0 {
+				t.Errorf("expected no requests, got %d", helper.RequestCount)
+			}
+		})
+	}
+}
+
+func TestDashgram_GetReferralsAPIError(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusNotFound, `{"status":"error","details":"user not found"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	referrals, err := d.GetReferrals(context.Background(), 42)
+
+	var apiErr *DashgramAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected DashgramAPIError, got %v", err)
+	}
+	if referrals != nil {
+		t.Errorf("expected nil referrals, got %v", referrals)
+	}
+}
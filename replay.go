@@ -0,0 +1,126 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// ReplayResult summarizes a Replay
+type ReplayResult struct {
+	// Total is the number of recorded requests, Succeeded and Failed those
+	// replayed so far
+	Total     int
+	Succeeded int
+	Failed    int
+	// Errors holds a *ReplayRequestError per failed request, in order
+	Errors []error
+}
+
+// ReplayRequestError is the error of a recorded request that failed to replay
+type ReplayRequestError struct {
+	// Index is the position of the request in the recorded requests
+	Index    int
+	Endpoint string
+	Err      error
+}
+
+func (e *ReplayRequestError) Error() string {
+	return fmt.Sprintf("replay of request %d (%s): %v", e.Index, e.Endpoint, e.Err)
+}
+
+func (e *ReplayRequestError) Unwrap() error {
+	return e.Err
+}
+
+// Replay re-issues recorded requests, e.g. captured with TestHelper, against
+// the API of the client, one after the other, to verify a migration or debug
+// a payload. Each recorded body is sent as is to its endpoint, with the
+// credentials and project of this client rather than the recorded ones.
+//
+// Requests that fail don't stop the replay and are listed in the result.
+// The returned error is only set if ctx is done before every request is
+// replayed.
+func (d *Dashgram) Replay(ctx context.Context, recorded []RecordedRequest) (ReplayResult, error) {
+	result := ReplayResult{Total: len(recorded)}
+
+	for i, req := range recorded {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		endpoint, err := d.replayRequest(ctx, req)
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, &ReplayRequestError{Index: i, Endpoint: endpoint, Err: err})
+			continue
+		}
+		result.Succeeded++
+	}
+
+	return result, nil
+}
+
+// replayRequest sends a recorded request and returns its endpoint
+func (d *Dashgram) replayRequest(ctx context.Context, req RecordedRequest) (string, error) {
+	if req.URL == nil {
+		return "", &ValidationError{Field: "URL", Message: "must not be nil"}
+	}
+	endpoint := replayEndpoint(req.URL.Path, d.baseURL)
+	if endpoint == "" {
+		return "", &ValidationError{Field: "URL", Message: "must hold an endpoint, got " + req.URL.Path}
+	}
+
+	var data any
+	if len(req.Body) > 0 {
+		mediaType, _, _ := mime.ParseMediaType(req.Headers.Get("Content-Type"))
+		if mediaType != "" && mediaType != "application/json" {
+			return endpoint, &ValidationError{Field: "Body", Message: "must be JSON, got " + mediaType}
+		}
+		data = json.RawMessage(req.Body)
+	}
+
+	var query url.Values
+	if req.URL.RawQuery != "" {
+		query = req.URL.Query()
+	}
+
+	_, err := d.do(ctx, req.Method, endpoint, query, data, nil)
+	return endpoint, err
+}
+
+// replayEndpoint returns the endpoint of a recorded request path, the part
+// after the project ID. The path of the API URL is stripped first if the
+// request was recorded against it; otherwise the project ID is taken to be
+// the first numeric segment.
+func replayEndpoint(path, apiURL string) string {
+	if u, err := url.Parse(apiURL); err == nil && u.Path != "" && strings.HasPrefix(path, u.Path+"/") {
+		path = strings.TrimPrefix(path, u.Path+"/")
+		_, endpoint, _ := strings.Cut(path, "/")
+		return endpoint
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if isDigits(segment) {
+			return strings.Join(segments[i+1:], "/")
+		}
+	}
+	return ""
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
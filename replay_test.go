@@ -0,0 +1,110 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestReplayEndpoint(t *testing.T) {
+	tests := []struct {
+		path     string
+		apiURL   string
+		expected string
+	}{
+		{path: "/v1/123/track", apiURL: "https://api.dashgram.io/v1", expected: "track"},
+		{path: "/v1/123/invited_by/bulk", apiURL: "https://api.dashgram.io/v1", expected: "invited_by/bulk"},
+		{path: "/v1/123/users/42", apiURL: "https://api.dashgram.io/v1", expected: "users/42"},
+		{path: "/123/track", apiURL: "https://api.dashgram.io/v1", expected: "track"},
+		{path: "/v2/7/track", apiURL: "http://127.0.0.1:8080", expected: "track"},
+		{path: "/track", apiURL: "https://api.dashgram.io/v1", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if endpoint := replayEndpoint(tt.path, tt.apiURL); endpoint != tt.expected {
+				t.Errorf("expected endpoint %q, got %q", tt.expected, endpoint)
+			}
+		})
+	}
+}
+
+func TestDashgram_Replay(t *testing.T) {
+	// Record requests made by a first client
+	recorder := NewTestHelper()
+	for i := 0; i < 3; i++ {
+		recorder.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	}
+	source := New(111, "source-key", WithHTTPClient(recorder.MockHTTPClient()))
+	source.TrackEvent(map[string]any{"action": "open"})
+	source.InvitedBy(1, 2)
+	source.TrackEvent(map[string]any{"action": "close"})
+	source.Close()
+	recorded := recorder.RecordedRequests()
+
+	// Replay them with a second client, whose API rejects the invitation
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusBadRequest, `{"status":"error","details":"bad request"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	d := New(222, "target-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	result, err := d.Replay(context.Background(), recorded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Total != 3 || result.Succeeded != 2 || result.Failed != 1 {
+		t.Errorf("expected 3 total, 2 succeeded and 1 failed, got %+v", result)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(result.Errors))
+	}
+	var replayErr *ReplayRequestError
+	if !errors.As(result.Errors[0], &replayErr) || replayErr.Index != 1 || replayErr.Endpoint != "invited_by" {
+		t.Errorf("expected ReplayRequestError for request 1 to invited_by, got %v", result.Errors[0])
+	}
+	var apiErr *DashgramAPIError
+	if !errors.As(result.Errors[0], &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected the API error to be wrapped, got %v", result.Errors[0])
+	}
+
+	replayed := helper.RecordedRequests()
+	if len(replayed) != len(recorded) {
+		t.Fatalf("expected %d replayed requests, got %d", len(recorded), len(replayed))
+	}
+	expectedPaths := []string{"/v1/222/track", "/v1/222/invited_by", "/v1/222/track"}
+	for i, req := range replayed {
+		if req.URL.Path != expectedPaths[i] {
+			t.Errorf("expected path %s, got %s", expectedPaths[i], req.URL.Path)
+		}
+		if string(req.Body) != string(recorded[i].Body) {
+			t.Errorf("expected body %s, got %s", recorded[i].Body, req.Body)
+		}
+		if auth := req.Headers.Get("Authorization"); auth != "Bearer target-key" {
+			t.Errorf("expected the target credentials, got %q", auth)
+		}
+	}
+}
+
+func TestDashgram_ReplayCancelled(t *testing.T) {
+	helper := NewTestHelper()
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	recorded := []RecordedRequest{{Method: http.MethodPost, Body: []byte(`{}`)}}
+	result, err := d.Replay(ctx, recorded)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if result.Total != 1 || result.Succeeded != 0 || result.Failed != 0 {
+		t.Errorf("expected nothing replayed, got %+v", result)
+	}
+	if helper.RequestCount != 0 {
+		t.Errorf("expected no request, got %d", helper.RequestCount)
+	}
+}
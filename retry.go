@@ -0,0 +1,168 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultRetryableStatuses are the status codes retried unless WithRetryableStatuses is used
+var defaultRetryableStatuses = []int{429, 500, 502, 503, 504}
+
+// WithRetry retries failed requests up to maxRetries times, waiting backoff
+// before the first retry and doubling the wait after each attempt. Transport
+// errors and the statuses set by WithRetryableStatuses are retried.
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(d *Dashgram) {
+		if maxRetries >= 0 {
+			d.maxRetries = maxRetries
+		}
+		if backoff >= 0 {
+			d.retryBackoff = backoff
+		}
+	}
+}
+
+// WithRetryableStatuses sets the HTTP status codes that trigger a retry,
+// replacing the default 429, 500, 502, 503 and 504. Other codes fail immediately.
+func WithRetryableStatuses(codes ...int) Option {
+	return func(d *Dashgram) {
+		d.retryableStatuses = make(map[int]bool, len(codes))
+		for _, code := range codes {
+			d.retryableStatuses[code] = true
+		}
+	}
+}
+
+// retryBudgetBurst is the number of retries a retry budget holds at most, and
+// starts with, so a client can retry before it sent enough requests to earn them
+const retryBudgetBurst = 10
+
+// WithRetryBudget caps the retries of the client at ratio of its requests,
+// e.g. 0.1 to retry at most one request in ten, so an outage doesn't multiply
+// the load on the API by the number of retries. Every request earns ratio of
+// a retry, up to a burst of 10 retries shared by all calls, and every retry
+// spends one. Once the budget is spent, failed requests return their error
+// without being retried until enough requests earned a retry again.
+// WithRetry still caps the retries of each request.
+func WithRetryBudget(ratio float64) Option {
+	return func(d *Dashgram) {
+		if ratio < 0 {
+			ratio = 0
+		}
+		d.retryBudget = &retryBudget{ratio: ratio, tokens: retryBudgetBurst}
+	}
+}
+
+// retryBudget is a token bucket of retries shared by every call, see WithRetryBudget
+type retryBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	tokens float64
+}
+
+// deposit earns a request's share of a retry
+func (b *retryBudget) deposit() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += b.ratio
+	if b.tokens > retryBudgetBurst {
+		b.tokens = retryBudgetBurst
+	}
+}
+
+// withdraw spends a retry and reports whether the budget allowed it, a nil
+// budget allows every retry
+func (b *retryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// RetryableChecker decides whether a request is retried. resp is nil if the
+// request failed without a response; its body has already been consumed.
+type RetryableChecker func(resp *http.Response, err error) bool
+
+// WithRequestRetryableCheck sets the function deciding which failed requests
+// are retried, overriding WithRetryableStatuses. The number of retries is
+// still set by WithRetry.
+func WithRequestRetryableCheck(fn RetryableChecker) Option {
+	return func(d *Dashgram) {
+		d.retryableCheck = fn
+	}
+}
+
+// DefaultRetryableChecker retries transport errors, except refused
+// redirects, 429 and 5xx responses
+func DefaultRetryableChecker(resp *http.Response, err error) bool {
+	if err != nil {
+		var transportErr *TransportError
+		return errors.As(err, &transportErr) && !errors.Is(err, ErrRedirect)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// sendWithRetry sends the request, retrying it as configured by WithRetry or WithCallRetry
+func (d *Dashgram) sendWithRetry(ctx context.Context, method string, requestURL string, endpoint string, data any, call callOptions) (*Response, []byte, error) {
+	d.retryBudget.deposit()
+
+	maxRetries, backoff := call.retryOr(d.maxRetries, d.retryBackoff)
+	for attempt := 0; ; attempt++ {
+		resp, respBody, err := d.send(ctx, method, requestURL, data, call)
+		if attempt >= maxRetries || !d.shouldRetry(ctx, resp, err) {
+			return resp, respBody, err
+		}
+		if !d.retryBudget.withdraw() {
+			d.log(LogLevelWarn, "retry budget exhausted, not retrying", "endpoint", endpoint)
+			return resp, respBody, err
+		}
+
+		d.log(LogLevelDebug, "retrying request", "endpoint", endpoint, "attempt", attempt+1)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, respBody, err
+		}
+		backoff *= 2
+	}
+}
+
+// shouldRetry reports whether a request is worth retrying
+func (d *Dashgram) shouldRetry(ctx context.Context, resp *Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if d.retryableCheck != nil {
+		var httpResp *http.Response
+		if resp != nil {
+			httpResp = &http.Response{
+				Status:     http.StatusText(resp.StatusCode),
+				StatusCode: resp.StatusCode,
+				Header:     resp.Headers,
+			}
+		}
+		return d.retryableCheck(httpResp, err)
+	}
+	if err != nil {
+		var transportErr *TransportError
+		return errors.As(err, &transportErr) && !errors.Is(err, ErrRedirect)
+	}
+	return d.retryableStatuses[resp.StatusCode]
+}
@@ -0,0 +1,273 @@
+package dashgram
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WithRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		options          []Option
+		statuses         []int
+		transportErr     bool
+		expectedRequests int
+		expectError      bool
+	}{
+		{
+			name:             "no retries by default",
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedRequests: 1,
+			expectError:      true,
+		},
+		{
+			name:             "retries default statuses",
+			options:          []Option{WithRetry(3, time.Millisecond)},
+			statuses:         []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusOK},
+			expectedRequests: 3,
+		},
+		{
+			name:             "gives up after max retries",
+			options:          []Option{WithRetry(2, time.Millisecond)},
+			statuses:         []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			expectedRequests: 3,
+			expectError:      true,
+		},
+		{
+			name:             "does not retry client errors",
+			options:          []Option{WithRetry(3, time.Millisecond)},
+			statuses:         []int{http.StatusBadRequest, http.StatusOK},
+			expectedRequests: 1,
+			expectError:      true,
+		},
+		{
+			name:             "retries transport errors",
+			options:          []Option{WithRetry(3, time.Millisecond)},
+			statuses:         []int{0, http.StatusOK},
+			transportErr:     true,
+			expectedRequests: 2,
+		},
+		{
+			name:             "custom statuses retry 408",
+			options:          []Option{WithRetry(3, time.Millisecond), WithRetryableStatuses(408)},
+			statuses:         []int{http.StatusRequestTimeout, http.StatusOK},
+			expectedRequests: 2,
+		},
+		{
+			name:             "custom statuses exclude 500",
+			options:          []Option{WithRetry(3, time.Millisecond), WithRetryableStatuses(408)},
+			statuses:         []int{http.StatusInternalServerError, http.StatusOK},
+			expectedRequests: 1,
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			for i, status := range tt.statuses {
+				if i == 0 && tt.transportErr {
+					helper.AddResponse(0, "")
+					helper.AddError(errors.New("connection reset"))
+					continue
+				}
+				helper.AddResponse(status, `{"status":"success","details":"ok"}`)
+				helper.AddError(nil)
+			}
+
+			options := append([]Option{WithHTTPClient(helper.MockHTTPClient())}, tt.options...)
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			err := d.TrackEvent(map[string]any{"action": "click"})
+			if tt.expectError && err == nil {
+				t.Errorf("expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if count := len(helper.RecordedRequests()); count != tt.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tt.expectedRequests, count)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithRetryBackoff(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusServiceUnavailable, `{"status":"error","details":"unavailable"}`)
+	helper.AddResponse(http.StatusServiceUnavailable, `{"status":"error","details":"unavailable"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithRetry(2, 20*time.Millisecond))
+	defer d.Close()
+
+	start := time.Now()
+	if err := d.TrackEvent(map[string]any{"action": "click"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 20ms before the first retry, 40ms before the second
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("expected exponential backoff of at least 60ms, took %v", elapsed)
+	}
+}
+
+func TestDashgram_WithRequestRetryableCheck(t *testing.T) {
+	tests := []struct {
+		name             string
+		checker          RetryableChecker
+		statuses         []int
+		expectedRequests int
+	}{
+		{
+			name:             "never retry",
+			checker:          func(resp *http.Response, err error) bool { return false },
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedRequests: 1,
+		},
+		{
+			name: "retry on bad request",
+			checker: func(resp *http.Response, err error) bool {
+				return resp != nil && resp.StatusCode == http.StatusBadRequest
+			},
+			statuses:         []int{http.StatusBadRequest, http.StatusOK},
+			expectedRequests: 2,
+		},
+		{
+			name:             "default checker retries any 5xx",
+			checker:          DefaultRetryableChecker,
+			statuses:         []int{http.StatusNotImplemented, http.StatusOK},
+			expectedRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			for _, status := range tt.statuses {
+				helper.AddResponse(status, `{"status":"success","details":"ok"}`)
+			}
+
+			d := New(123, "test-key",
+				WithHTTPClient(helper.MockHTTPClient()),
+				WithRetry(3, time.Millisecond),
+				WithRequestRetryableCheck(tt.checker),
+			)
+			defer d.Close()
+
+			d.TrackEvent(map[string]any{"action": "click"})
+
+			if count := len(helper.RecordedRequests()); count != tt.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tt.expectedRequests, count)
+			}
+		})
+	}
+}
+
+func TestDefaultRetryableChecker(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		err      error
+		expected bool
+	}{
+		{name: "transport error", err: &TransportError{Err: errors.New("connection reset")}, expected: true},
+		{name: "other error", err: errors.New("failed to marshal request data"), expected: false},
+		{name: "too many requests", resp: &http.Response{StatusCode: http.StatusTooManyRequests}, expected: true},
+		{name: "server error", resp: &http.Response{StatusCode: http.StatusGatewayTimeout}, expected: true},
+		{name: "client error", resp: &http.Response{StatusCode: http.StatusBadRequest}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultRetryableChecker(tt.resp, tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithRetryBudget(t *testing.T) {
+	var requests atomic.Int64
+	client := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"error","details":"unavailable"}`)),
+			}, nil
+		},
+	}
+
+	// The budget earns almost nothing, so only its initial burst of 10 retries is spent
+	d := New(123, "test-key", WithHTTPClient(client), WithRetry(3, 0), WithRetryBudget(0.0001))
+	defer d.Close()
+
+	// Three calls retry three times each, the fourth retries once with the last retry
+	expected := []int64{4, 4, 4, 2, 1, 1, 1}
+	for i, expectedRequests := range expected {
+		before := requests.Load()
+		if err := d.TrackEvent(map[string]any{"action": "click"}); err == nil {
+			t.Fatal("expected an error")
+		}
+		if sent := requests.Load() - before; sent != expectedRequests {
+			t.Errorf("call %d: expected %d requests, got %d", i, expectedRequests, sent)
+		}
+	}
+}
+
+func TestDashgram_WithRetryBudgetRefills(t *testing.T) {
+	var requests atomic.Int64
+	var failing atomic.Bool
+	client := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			if failing.Load() {
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Body:       io.NopCloser(strings.NewReader(`{"status":"error","details":"unavailable"}`)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	d := New(123, "test-key", WithHTTPClient(client), WithRetry(1, 0), WithRetryBudget(0.5))
+	defer d.Close()
+
+	// Spend the burst: each failing call earns half a retry and spends one
+	failing.Store(true)
+	for i := 0; i < 30; i++ {
+		d.TrackEvent(map[string]any{"action": "click"})
+	}
+	before := requests.Load()
+	d.TrackEvent(map[string]any{"action": "click"})
+	d.TrackEvent(map[string]any{"action": "click"})
+	if sent := requests.Load() - before; sent != 3 {
+		t.Errorf("expected a retry every other call once the burst is spent, got %d requests for 2 calls", sent)
+	}
+
+	// Successful calls earn retries back
+	failing.Store(false)
+	for i := 0; i < 4; i++ {
+		if err := d.TrackEvent(map[string]any{"action": "click"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	failing.Store(true)
+	before = requests.Load()
+	d.TrackEvent(map[string]any{"action": "click"})
+	if sent := requests.Load() - before; sent != 2 {
+		t.Errorf("expected the earned retry to be spent, got %d requests", sent)
+	}
+}
@@ -0,0 +1,119 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"strconv"
+)
+
+// WithSampleRate tracks only a fraction of the events, between 0 and 1, e.g.
+// 0.1 to keep 10% of page views. Sampling is deterministic: events are kept
+// by hashing the ID of their user, found as with WithUserIDExtractor, so a
+// user is consistently in or out of the sample. Events without a user are
+// sampled by hashing their JSON payload. Sampled-out events are counted in
+// Stats().SampledOut and the tracking methods return nil. Invitations and
+// user properties are never sampled.
+func WithSampleRate(rate float64) Option {
+	return func(d *Dashgram) {
+		d.sampleRate = rate
+	}
+}
+
+// WithCallSampleRate overrides the sample rate for a single call, e.g. 1 to
+// keep every purchase despite WithSampleRate
+func WithCallSampleRate(rate float64) CallOption {
+	return func(co *callOptions) {
+		co.sampleRate = rate
+		co.sampleRateSet = true
+	}
+}
+
+// Sampler decides which events are tracked, e.g. for time-window sampling or
+// feature-flag gating
+type Sampler interface {
+	// ShouldTrack reports whether the event is tracked
+	ShouldTrack(event any) bool
+}
+
+// SamplerFunc adapts a function to the Sampler interface
+type SamplerFunc func(event any) bool
+
+// ShouldTrack calls f(event)
+func (f SamplerFunc) ShouldTrack(event any) bool {
+	return f(event)
+}
+
+// WithSampler tracks only the events s keeps, by default every event is
+// tracked. It applies after WithSampleRate, to synchronous and asynchronous
+// calls alike, and is called from the calling goroutine. Dropped events are
+// counted in Stats().SampledOut and the tracking methods return nil.
+func WithSampler(s Sampler) Option {
+	return func(d *Dashgram) {
+		d.sampler = s
+	}
+}
+
+// forceTrackKey is the context key set by ForceTrack
+type forceTrackKey struct{}
+
+// ForceTrack returns a copy of ctx whose events bypass WithSampleRate,
+// WithCallSampleRate and WithSampler, e.g. to debug the session of a user who
+// is sampled out. Events of opted-out users are still suppressed.
+func ForceTrack(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceTrackKey{}, true)
+}
+
+// skipEvent reports whether the event must not be tracked, because its user
+// opted out or it was sampled out
+func (d *Dashgram) skipEvent(ctx context.Context, event any, opts []CallOption) bool {
+	return d.suppressEvent(event) || d.sampledOut(ctx, event, opts)
+}
+
+// sampledOut reports whether the event falls outside the sample, counting it
+func (d *Dashgram) sampledOut(ctx context.Context, event any, opts []CallOption) bool {
+	if forced, _ := ctx.Value(forceTrackKey{}).(bool); forced {
+		return false
+	}
+
+	rate := d.sampleRate
+	if len(opts) > 0 {
+		if call := newCallOptions(opts); call.sampleRateSet {
+			rate = call.sampleRate
+		}
+	}
+	keep := rate >= 1 || (rate > 0 && sampleKey(d.userIDOf(event), event) < rate)
+	if keep && d.sampler != nil {
+		keep = d.sampler.ShouldTrack(event)
+	}
+	if keep {
+		return false
+	}
+
+	d.stats.sampledOut.Add(1)
+	return true
+}
+
+// userIDOf returns the ID of the user of an event, as a string so it can be hashed
+func (d *Dashgram) userIDOf(event any) string {
+	if userID, ok := d.extractUserID(event); ok {
+		return strconv.FormatInt(userID, 10)
+	}
+	return ""
+}
+
+// sampleKey maps the user ID, or the payload if there is none, to [0, 1)
+func sampleKey(userID string, event any) float64 {
+	h := fnv.New64a()
+	if userID != "" {
+		h.Write([]byte("user:" + userID))
+	} else if raw, ok := event.(json.RawMessage); ok {
+		h.Write(raw)
+	} else if payload, err := json.Marshal(event); err == nil {
+		h.Write(payload)
+	}
+
+	// Use the top 53 bits, which a float64 represents exactly
+	return float64(h.Sum64()>>11) / math.Exp2(53)
+}
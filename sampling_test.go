@@ -0,0 +1,281 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// countingClient returns a successful response to every request and counts them
+func countingClient(count *atomic.Int64) *mockHTTPClient {
+	return &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			count.Add(1)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+}
+
+func TestDashgram_WithSampleRate(t *testing.T) {
+	var requests atomic.Int64
+	d := New(123, "test-key", WithHTTPClient(countingClient(&requests)), WithSampleRate(0.1))
+	defer d.Close()
+
+	const users = 2000
+	for userID := int64(1); userID <= users; userID++ {
+		if err := d.TrackEventWithUserID(userID, map[string]any{"action": "page_view"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	sent := requests.Load()
+	if sent < users*0.07 || sent > users*0.13 {
+		t.Errorf("expected about 10%% of %d events to be sent, got %d", users, sent)
+	}
+
+	stats := d.Stats()
+	if sent+stats.SampledOut != users {
+		t.Errorf("expected sent and sampled out events to add up to %d, got %d + %d", users, sent, stats.SampledOut)
+	}
+}
+
+func TestDashgram_WithSampleRateIsDeterministic(t *testing.T) {
+	var requests atomic.Int64
+	d := New(123, "test-key", WithHTTPClient(countingClient(&requests)), WithSampleRate(0.5))
+	defer d.Close()
+
+	for userID := int64(1); userID <= 100; userID++ {
+		before := requests.Load()
+		d.TrackEventWithUserID(userID, map[string]any{"action": "first"})
+		first := requests.Load() - before
+
+		d.TrackEventWithUserID(userID, map[string]any{"action": "second", "page": "/pricing"})
+		second := requests.Load() - before - first
+
+		if first != second {
+			t.Fatalf("expected both events of user %d to get the same decision, got %d and %d", userID, first, second)
+		}
+	}
+
+	// Events without a user are sampled by payload
+	for i := 0; i < 100; i++ {
+		event := json.RawMessage(`{"action":"anonymous","index":` + strconv.Itoa(i) + `}`)
+		before := requests.Load()
+		d.TrackEvent(event)
+		first := requests.Load() - before
+		d.TrackEvent(event)
+		if second := requests.Load() - before - first; first != second {
+			t.Fatalf("expected identical payloads to get the same decision, got %d and %d", first, second)
+		}
+	}
+}
+
+func TestDashgram_WithCallSampleRate(t *testing.T) {
+	tests := []struct {
+		name               string
+		clientRate         float64
+		callOpts           []CallOption
+		expectedSent       int64
+		expectedSampledOut int64
+	}{
+		{name: "sample nothing", clientRate: 0, expectedSent: 0, expectedSampledOut: 10},
+		{name: "call keeps everything", clientRate: 0, callOpts: []CallOption{WithCallSampleRate(1)}, expectedSent: 10},
+		{name: "call drops everything", clientRate: 1, callOpts: []CallOption{WithCallSampleRate(0)}, expectedSampledOut: 10},
+		{name: "default keeps everything", clientRate: 1, expectedSent: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int64
+			d := New(123, "test-key", WithHTTPClient(countingClient(&requests)), WithSampleRate(tt.clientRate))
+
+			for userID := int64(1); userID <= 10; userID++ {
+				event := map[string]any{"action": "purchase", "user_id": userID}
+				if err := d.TrackEventWithContext(context.Background(), event, tt.callOpts...); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			d.Close()
+
+			if sent := requests.Load(); sent != tt.expectedSent {
+				t.Errorf("expected %d requests, got %d", tt.expectedSent, sent)
+			}
+			if sampledOut := d.Stats().SampledOut; sampledOut != tt.expectedSampledOut {
+				t.Errorf("expected %d sampled out events, got %d", tt.expectedSampledOut, sampledOut)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithSampleRateAsync(t *testing.T) {
+	var requests atomic.Int64
+	d := New(123, "test-key", WithHTTPClient(countingClient(&requests)), WithSampleRate(0))
+
+	d.TrackEventAsync(map[string]any{"action": "page_view", "user_id": 1})
+	d.InvitedByAsync(1, 2)
+	d.Close()
+
+	if sent := requests.Load(); sent != 1 {
+		t.Errorf("expected only the invitation to be sent, got %d requests", sent)
+	}
+	stats := d.Stats()
+	if stats.SampledOut != 1 || stats.Enqueued != 1 {
+		t.Errorf("expected 1 sampled out event and 1 enqueued task, got %+v", stats)
+	}
+}
+
+func TestSampleKey(t *testing.T) {
+	for _, userID := range []string{"", "1", "42", "9007199254740993"} {
+		key := sampleKey(userID, map[string]any{"action": "click"})
+		if key < 0 || key >= 1 {
+			t.Errorf("expected a key in [0, 1), got %v", key)
+		}
+	}
+
+	if sampleKey("42", map[string]any{"a": 1}) != sampleKey("42", map[string]any{"b": 2}) {
+		t.Error("expected the key of a user to ignore the payload")
+	}
+	if sampleKey("", map[string]any{"a": 1}) == sampleKey("", map[string]any{"b": 2}) {
+		t.Error("expected events without a user to be keyed by payload")
+	}
+}
+
+// actionSampler keeps the events whose action is in the set
+type actionSampler map[string]bool
+
+func (s actionSampler) ShouldTrack(event any) bool {
+	e, ok := event.(map[string]any)
+	return ok && s[e["action"].(string)]
+}
+
+func TestDashgram_WithSampler(t *testing.T) {
+	tests := []struct {
+		name  string
+		async bool
+	}{
+		{name: "sync"},
+		{name: "async", async: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int64
+			options := []Option{
+				WithHTTPClient(countingClient(&requests)),
+				WithSampler(actionSampler{"purchase": true}),
+			}
+			if tt.async {
+				options = append(options, WithUseAsync())
+			}
+			d := New(123, "test-key", options...)
+
+			for _, action := range []string{"purchase", "page_view", "purchase", "scroll"} {
+				if err := d.TrackEvent(map[string]any{"action": action}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			d.Close()
+
+			if sent := requests.Load(); sent != 2 {
+				t.Errorf("expected 2 purchases to be sent, got %d requests", sent)
+			}
+			if sampledOut := d.Stats().SampledOut; sampledOut != 2 {
+				t.Errorf("expected 2 sampled out events, got %d", sampledOut)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithSamplerAfterSampleRate(t *testing.T) {
+	var requests atomic.Int64
+	var calls atomic.Int64
+	d := New(123, "test-key",
+		WithHTTPClient(countingClient(&requests)),
+		WithSampleRate(0),
+		WithSampler(SamplerFunc(func(event any) bool {
+			calls.Add(1)
+			return true
+		})),
+	)
+	defer d.Close()
+
+	d.TrackEvent(map[string]any{"action": "click"})
+	d.TrackEvent(map[string]any{"action": "click"}, WithCallSampleRate(1))
+
+	if sent := requests.Load(); sent != 1 {
+		t.Errorf("expected 1 request, got %d", sent)
+	}
+	if count := calls.Load(); count != 1 {
+		t.Errorf("expected the sampler to only see events kept by the sample rate, got %d calls", count)
+	}
+}
+
+func TestDashgram_ForceTrack(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{
+			name:    "sample rate 0",
+			options: []Option{WithSampleRate(0)},
+		},
+		{
+			name:    "sampler dropping every event",
+			options: []Option{WithSampler(SamplerFunc(func(event any) bool { return false }))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int64
+			options := append([]Option{WithHTTPClient(countingClient(&requests))}, tt.options...)
+			d := New(123, "test-key", options...)
+
+			ctx := ForceTrack(context.Background())
+			event := map[string]any{"action": "page_view", "user_id": 1}
+
+			d.TrackEventWithContext(context.Background(), event)
+			if err := d.TrackEventWithContext(ctx, event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := d.TrackEventAsyncWithContext(ctx, event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := d.TrackGroupEventWithContext(ctx, 1, event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			d.Close()
+
+			if sent := requests.Load(); sent != 3 {
+				t.Errorf("expected the 3 force-tracked events to be sent, got %d requests", sent)
+			}
+			if sampledOut := d.Stats().SampledOut; sampledOut != 1 {
+				t.Errorf("expected 1 sampled out event, got %d", sampledOut)
+			}
+		})
+	}
+}
+
+func TestDashgram_ForceTrackKeepsOptOut(t *testing.T) {
+	var requests atomic.Int64
+	d := New(123, "test-key",
+		WithHTTPClient(countingClient(&requests)),
+		WithOptOut(func(userID int64) bool { return true }),
+	)
+	defer d.Close()
+
+	if err := d.TrackEventWithContext(ForceTrack(context.Background()), map[string]any{"user_id": 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent := requests.Load(); sent != 0 {
+		t.Errorf("expected the opted-out user's event to be suppressed, got %d requests", sent)
+	}
+}
@@ -0,0 +1,141 @@
+package dashgram
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// scrubbedValue replaces the values removed by WithScrubFields
+const scrubbedValue = "[redacted]"
+
+// WithScrubFields replaces the values at the given paths of every event with
+// "[redacted]", e.g. "message.contact.phone_number" or "message.text" for
+// Telegram updates. Paths are dot-separated keys of the event's JSON
+// encoding; arrays along the path are traversed, so "entities.url" scrubs the
+// url of every entity. Missing paths are ignored.
+//
+// Scrubbing runs after the events are marshaled and before the request is
+// sent, so it also covers raw JSON events and values added by other options.
+// Scrubbed events are re-encoded with their keys sorted.
+func WithScrubFields(paths ...string) Option {
+	return func(d *Dashgram) {
+		for _, path := range paths {
+			if path != "" {
+				d.scrubPaths = append(d.scrubPaths, strings.Split(path, "."))
+			}
+		}
+	}
+}
+
+// scrubRequest returns a copy of the request data with the events scrubbed,
+// other request data is returned as is
+func (d *Dashgram) scrubRequest(data any) (any, error) {
+	if len(d.scrubPaths) == 0 {
+		return data, nil
+	}
+
+	var err error
+	switch r := data.(type) {
+	case *TrackEventRequest:
+		scrubbed := *r
+		scrubbed.Updates, err = d.scrubUpdates(r.Updates)
+		return &scrubbed, err
+	case TrackEventRequest:
+		r.Updates, err = d.scrubUpdates(r.Updates)
+		return r, err
+	case GroupEventRequest:
+		r.Updates, err = d.scrubUpdates(r.Updates)
+		return r, err
+	case ImportEventsRequest:
+		updates := make([]any, len(r.Events))
+		for i, event := range r.Events {
+			updates[i] = event.Update
+		}
+		if updates, err = d.scrubUpdates(updates); err != nil {
+			return nil, err
+		}
+		events := make([]HistoricalUpdate, len(r.Events))
+		for i, event := range r.Events {
+			events[i] = HistoricalUpdate{Update: updates[i], OccurredAt: event.OccurredAt}
+		}
+		r.Events = events
+		return r, nil
+	case *multipartRequest:
+		payload, err := d.scrubRequest(r.payload)
+		if err != nil {
+			return nil, err
+		}
+		scrubbed := *r
+		scrubbed.payload = payload
+		return &scrubbed, nil
+	default:
+		return data, nil
+	}
+}
+
+// scrubUpdates returns a copy of the updates with the configured paths scrubbed
+func (d *Dashgram) scrubUpdates(updates []any) ([]any, error) {
+	scrubbed := make([]any, len(updates))
+	for i, update := range updates {
+		raw, ok := update.(json.RawMessage)
+		if !ok {
+			var err error
+			if raw, err = json.Marshal(update); err != nil {
+				return nil, err
+			}
+		}
+
+		// Decode numbers as is, so large IDs keep their precision
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		changed := false
+		for _, path := range d.scrubPaths {
+			if scrubPath(value, path) {
+				changed = true
+			}
+		}
+		if !changed {
+			scrubbed[i] = raw
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		scrubbed[i] = json.RawMessage(encoded)
+	}
+	return scrubbed, nil
+}
+
+// scrubPath replaces the value at path, traversing arrays, and reports whether it found any
+func scrubPath(value any, path []string) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		child, ok := v[path[0]]
+		if !ok {
+			return false
+		}
+		if len(path) == 1 {
+			v[path[0]] = scrubbedValue
+			return true
+		}
+		return scrubPath(child, path[1:])
+	case []any:
+		found := false
+		for _, item := range v {
+			if scrubPath(item, path) {
+				found = true
+			}
+		}
+		return found
+	default:
+		return false
+	}
+}
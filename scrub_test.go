@@ -0,0 +1,194 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// telegramUpdate is a full Telegram update sharing a contact, with a caption
+// carrying a phone number and entities
+const telegramUpdate = `{
+	"update_id": 912345678,
+	"message": {
+		"message_id": 4521,
+		"from": {
+			"id": 7012345678,
+			"is_bot": false,
+			"first_name": "Alice",
+			"last_name": "Smith",
+			"username": "alice_smith",
+			"language_code": "en"
+		},
+		"chat": {
+			"id": 7012345678,
+			"first_name": "Alice",
+			"last_name": "Smith",
+			"username": "alice_smith",
+			"type": "private"
+		},
+		"date": 1714564800,
+		"text": "Call me at +1 555 0100 or see https://example.com",
+		"entities": [
+			{"offset": 11, "length": 11, "type": "phone_number", "url": "tel:+15550100"},
+			{"offset": 30, "length": 19, "type": "url", "url": "https://example.com"}
+		],
+		"contact": {
+			"phone_number": "+15550100",
+			"first_name": "Alice",
+			"user_id": 7012345678
+		}
+	}
+}`
+
+func TestDashgram_WithScrubFields(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithScrubFields("message.contact.phone_number", "message.text", "message.entities.url", "message.reply_to_message.text"),
+	)
+	defer d.Close()
+
+	if err := d.TrackEventJSON([]byte(telegramUpdate)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := string(helper.LastRequest().Body)
+	for _, secret := range []string{"+15550100", "+1 555 0100", "tel:", "https://example.com"} {
+		if strings.Contains(body, secret) {
+			t.Errorf("expected %q to be scrubbed, got %s", secret, body)
+		}
+	}
+
+	var request struct {
+		Updates []struct {
+			UpdateID int64 `json:"update_id"`
+			Message  struct {
+				From struct {
+					ID        int64  `json:"id"`
+					FirstName string `json:"first_name"`
+				} `json:"from"`
+				Text     string `json:"text"`
+				Entities []struct {
+					Type string `json:"type"`
+					URL  string `json:"url"`
+				} `json:"entities"`
+				Contact struct {
+					PhoneNumber string `json:"phone_number"`
+					UserID      int64  `json:"user_id"`
+				} `json:"contact"`
+			} `json:"message"`
+		} `json:"updates"`
+	}
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("failed to decode request: %v", err)
+	}
+
+	message := request.Updates[0].Message
+	if message.Text != "[redacted]" || message.Contact.PhoneNumber != "[redacted]" {
+		t.Errorf("expected text and phone number to be redacted, got %q and %q", message.Text, message.Contact.PhoneNumber)
+	}
+	for _, entity := range message.Entities {
+		if entity.URL != "[redacted]" {
+			t.Errorf("expected the url of every entity to be redacted, got %q", entity.URL)
+		}
+	}
+
+	// Other fields are kept, with their precision
+	if request.Updates[0].UpdateID != 912345678 || message.From.ID != 7012345678 || message.Contact.UserID != 7012345678 {
+		t.Errorf("expected IDs to be kept, got %+v", request.Updates[0])
+	}
+	if message.From.FirstName != "Alice" || message.Entities[0].Type != "phone_number" {
+		t.Errorf("expected unlisted fields to be kept, got %+v", message)
+	}
+}
+
+func TestDashgram_WithScrubFieldsRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		call         func(d *Dashgram) error
+		expectedBody string
+	}{
+		{
+			name: "map event",
+			call: func(d *Dashgram) error {
+				return d.TrackEvent(map[string]any{"action": "contact", "phone": "+15550100"})
+			},
+			expectedBody: `{"updates":[{"action":"contact","phone":"[redacted]"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:         "untouched event keeps its encoding",
+			call:         func(d *Dashgram) error { return d.TrackEventJSON([]byte(`{"z":1,"a":2}`)) },
+			expectedBody: `{"updates":[{"z":1,"a":2}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:         "group event",
+			call:         func(d *Dashgram) error { return d.TrackGroupEvent(7, map[string]any{"phone": "+15550100"}) },
+			expectedBody: `{"group_id":7,"updates":[{"phone":"[redacted]"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name: "batch",
+			call: func(d *Dashgram) error {
+				return d.TrackEventBatch([]any{map[string]any{"phone": "1"}, map[string]any{"list": []any{map[string]any{"phone": "2"}}}})
+			},
+			expectedBody: `{"updates":[{"phone":"[redacted]"},{"list":[{"phone":"[redacted]"}]}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name: "imported event",
+			call: func(d *Dashgram) error {
+				events := []HistoricalEvent{{Event: map[string]any{"action": "contact", "phone": "+15550100"}, OccurredAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}}
+				report, err := d.ImportEvents(context.Background(), historicalEvents(events...), ImportOptions{})
+				if err == nil && report.Failed > 0 {
+					err = report.Errors[0]
+				}
+				return err
+			},
+			expectedBody: `{"events":[{"update":{"action":"contact","phone":"[redacted]"},"occurred_at":"2024-05-01T00:00:00Z"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:         "other requests",
+			call:         func(d *Dashgram) error { return d.SetUserProperties(1, map[string]any{"phone": "+15550100"}) },
+			expectedBody: `{"user_id":1,"properties":{"phone":"+15550100"},"origin":"Go + Dashgram SDK"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithScrubFields("phone", "list.phone"))
+			defer d.Close()
+
+			if err := tt.call(d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if body := string(helper.LastRequest().Body); body != tt.expectedBody {
+				t.Errorf("expected body %s, got %s", tt.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithScrubFieldsAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithUseAsync(), WithScrubFields("message.text"))
+
+	if err := d.TrackEvent(map[string]any{"message": map[string]any{"text": "secret"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Close()
+
+	expected := `{"updates":[{"message":{"text":"[redacted]"}}],"origin":"Go + Dashgram SDK"}`
+	if body := string(helper.LastRequest().Body); body != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+}
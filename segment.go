@@ -0,0 +1,152 @@
+package dashgram
+
+import (
+	"context"
+	"strconv"
+)
+
+// maxSegmentLength is the longest segment name accepted, in bytes
+const maxSegmentLength = 64
+
+// defaultSegmentChunkSize is the number of users sent per bulk segment request
+const defaultSegmentChunkSize = 500
+
+// AddUserToSegment tags a user with a segment, e.g. "beta_testers" or
+// "churn_risk", so dashboards can be sliced by it. Segment names must not be
+// empty nor longer than 64 bytes. With WithUseAsync the call is enqueued.
+func (d *Dashgram) AddUserToSegment(ctx context.Context, userID int64, segment string) error {
+	return d.segmentRequest(ctx, "segments/add", userID, segment, d.useAsync)
+}
+
+// RemoveUserFromSegment removes the segment tag of a user
+func (d *Dashgram) RemoveUserFromSegment(ctx context.Context, userID int64, segment string) error {
+	return d.segmentRequest(ctx, "segments/remove", userID, segment, d.useAsync)
+}
+
+// AddUserToSegmentAsyncWithContext validates the arguments and enqueues the segment tag to be sent asynchronously
+func (d *Dashgram) AddUserToSegmentAsyncWithContext(ctx context.Context, userID int64, segment string) error {
+	return d.segmentRequest(ctx, "segments/add", userID, segment, true)
+}
+
+func (d *Dashgram) AddUserToSegmentAsync(userID int64, segment string) error {
+	return d.AddUserToSegmentAsyncWithContext(context.Background(), userID, segment)
+}
+
+// RemoveUserFromSegmentAsyncWithContext validates the arguments and enqueues the segment removal to be sent asynchronously
+func (d *Dashgram) RemoveUserFromSegmentAsyncWithContext(ctx context.Context, userID int64, segment string) error {
+	return d.segmentRequest(ctx, "segments/remove", userID, segment, true)
+}
+
+func (d *Dashgram) RemoveUserFromSegmentAsync(userID int64, segment string) error {
+	return d.RemoveUserFromSegmentAsyncWithContext(context.Background(), userID, segment)
+}
+
+// AddUsersToSegment tags many users with a segment, e.g. from a backend job.
+// The users are split into chunks of 500 sent one after the other; chunks that
+// fail don't stop the others and are described by the returned ImportError,
+// so they can be sent again; if ctx is done, the remaining chunks aren't sent
+// and the error of ctx is returned. It always runs synchronously.
+func (d *Dashgram) AddUsersToSegment(ctx context.Context, segment string, userIDs []int64) error {
+	if err := validateSegment(segment); err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return &ValidationError{Field: "userIDs", Message: "must not be empty"}
+	}
+	for i, userID := range userIDs {
+		if userID <= 0 {
+			return &ValidationError{Field: "userIDs[" + strconv.Itoa(i) + "]", Message: "must be positive"}
+		}
+	}
+
+	chunks := (len(userIDs) + defaultSegmentChunkSize - 1) / defaultSegmentChunkSize
+
+	var failed []*ImportChunkError
+	for chunk := 0; chunk < chunks; chunk++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		start := chunk * defaultSegmentChunkSize
+		end := start + defaultSegmentChunkSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		ids := d.filterSuppressedUsers(userIDs[start:end])
+		if len(ids) == 0 {
+			continue
+		}
+		err := d.request(ctx, "segments/add/bulk", SegmentBulkRequest{
+			Segment: segment,
+			UserIDs: ids,
+			Origin:  d.Origin,
+		})
+		if err != nil {
+			failed = append(failed, &ImportChunkError{Chunk: chunk, Start: start, End: end, Err: err})
+		}
+	}
+
+	if len(failed) > 0 {
+		return &ImportError{Chunks: chunks, Failed: failed}
+	}
+	return nil
+}
+
+// segmentRequest validates and sends, or enqueues, a single segment request
+func (d *Dashgram) segmentRequest(ctx context.Context, endpoint string, userID int64, segment string, async bool) error {
+	if userID <= 0 {
+		return &ValidationError{Field: "userID", Message: "must be positive"}
+	}
+	if err := validateSegment(segment); err != nil {
+		return err
+	}
+	if d.suppressUsers(userID) {
+		return nil
+	}
+
+	requestData, ok := d.runBeforeSend(endpoint, SegmentRequest{
+		UserID:  userID,
+		Segment: segment,
+		Origin:  d.Origin,
+	})
+	if !ok {
+		return nil
+	}
+
+	if async {
+		d.enqueueTask(asyncTask{
+			ctx:      ctx,
+			endpoint: endpoint,
+			data:     requestData,
+		})
+		return nil
+	}
+
+	return d.request(ctx, endpoint, requestData)
+}
+
+// validateSegment checks a segment name
+func validateSegment(segment string) error {
+	if segment == "" {
+		return &ValidationError{Field: "segment", Message: "must not be empty"}
+	}
+	if len(segment) > maxSegmentLength {
+		return &ValidationError{Field: "segment", Message: "must be at most " + strconv.Itoa(maxSegmentLength) + " bytes"}
+	}
+	return nil
+}
+
+// filterSuppressedUsers returns the users who didn't opt out, see WithOptOut
+func (d *Dashgram) filterSuppressedUsers(userIDs []int64) []int64 {
+	if d.optOut == nil {
+		return userIDs
+	}
+
+	kept := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if !d.suppressUsers(userID) {
+			kept = append(kept, userID)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,263 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDashgram_AddUserToSegment(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        int64
+		segment       string
+		remove        bool
+		expectedPath  string
+		expected      string
+		expectedField string
+	}{
+		{
+			name:         "add",
+			userID:       12345,
+			segment:      "beta_testers",
+			expectedPath: "/v1/123/segments/add",
+			expected:     `{"user_id":12345,"segment":"beta_testers","origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:         "remove",
+			userID:       12345,
+			segment:      "churn_risk",
+			remove:       true,
+			expectedPath: "/v1/123/segments/remove",
+			expected:     `{"user_id":12345,"segment":"churn_risk","origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "empty segment",
+			userID:        12345,
+			expectedField: "segment",
+		},
+		{
+			name:          "segment too long",
+			userID:        12345,
+			segment:       strings.Repeat("s", 65),
+			expectedField: "segment",
+		},
+		{
+			name:          "non-positive user ID",
+			userID:        0,
+			segment:       "beta_testers",
+			remove:        true,
+			expectedField: "userID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			var err error
+			if tt.remove {
+				err = d.RemoveUserFromSegment(context.Background(), tt.userID, tt.segment)
+			} else {
+				err = d.AddUserToSegment(context.Background(), tt.userID, tt.segment)
+			}
+
+			if tt.expectedField != "" {
+				assertValidationError(t, err, tt.expectedField, helper)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			last := helper.LastRequest()
+			if last.URL.Path != tt.expectedPath {
+				t.Errorf("expected path %s, got %s", tt.expectedPath, last.URL.Path)
+			}
+			if body := string(last.Body); body != tt.expected {
+				t.Errorf("expected body %s, got %s", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestDashgram_AddUserToSegmentAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithNumWorkers(1), WithUseAsync())
+
+	if err := d.AddUserToSegmentAsync(12345, ""); err == nil {
+		t.Error("expected an empty segment to be rejected")
+	}
+	if err := d.AddUserToSegmentAsync(12345, "beta_testers"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := d.RemoveUserFromSegmentAsync(12345, "beta_testers"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// With WithUseAsync, the synchronous methods enqueue too
+	if err := d.AddUserToSegment(context.Background(), 12345, "churn_risk"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	d.Close()
+
+	if enqueued := d.Stats().Enqueued; enqueued != 3 {
+		t.Errorf("expected 3 enqueued tasks, got %d", enqueued)
+	}
+	if sent := len(helper.RecordedRequests()); sent != 3 {
+		t.Errorf("expected 3 requests, got %d", sent)
+	}
+}
+
+func TestDashgram_AddUsersToSegment(t *testing.T) {
+	userIDs := make([]int64, 1200)
+	for i := range userIDs {
+		userIDs[i] = int64(i + 1)
+	}
+
+	tests := []struct {
+		name           string
+		userIDs        []int64
+		segment        string
+		options        []Option
+		statuses       []int
+		expectedSizes  []int
+		expectedFailed []int
+		expectedField  string
+	}{
+		{
+			name:          "chunked",
+			userIDs:       userIDs,
+			segment:       "beta_testers",
+			statuses:      []int{200, 200, 200},
+			expectedSizes: []int{500, 500, 200},
+		},
+		{
+			name:           "failed chunk",
+			userIDs:        userIDs,
+			segment:        "beta_testers",
+			statuses:       []int{200, 500, 200},
+			expectedSizes:  []int{500, 500, 200},
+			expectedFailed: []int{1},
+		},
+		{
+			name:          "opted-out users skipped",
+			userIDs:       []int64{1, 2, 3},
+			segment:       "beta_testers",
+			options:       []Option{WithOptOut(func(userID int64) bool { return userID == 2 })},
+			statuses:      []int{200},
+			expectedSizes: []int{2},
+		},
+		{
+			name:          "no users",
+			segment:       "beta_testers",
+			expectedField: "userIDs",
+		},
+		{
+			name:          "invalid user",
+			userIDs:       []int64{1, -2},
+			segment:       "beta_testers",
+			expectedField: "userIDs[1]",
+		},
+		{
+			name:          "empty segment",
+			userIDs:       []int64{1},
+			expectedField: "segment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			for _, status := range tt.statuses {
+				helper.AddResponse(status, `{"status":"success","details":"ok"}`)
+			}
+
+			options := append([]Option{WithHTTPClient(helper.MockHTTPClient())}, tt.options...)
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			err := d.AddUsersToSegment(context.Background(), tt.segment, tt.userIDs)
+
+			if tt.expectedField != "" {
+				assertValidationError(t, err, tt.expectedField, helper)
+				return
+			}
+
+			if len(tt.expectedFailed) == 0 && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tt.expectedFailed) > 0 {
+				var importErr *ImportError
+				if !errors.As(err, &importErr) {
+					t.Fatalf("expected ImportError, got %v", err)
+				}
+				if len(importErr.Failed) != len(tt.expectedFailed) {
+					t.Fatalf("expected %d failed chunks, got %d", len(tt.expectedFailed), len(importErr.Failed))
+				}
+				for i, chunk := range tt.expectedFailed {
+					if importErr.Failed[i].Chunk != chunk {
+						t.Errorf("expected chunk %d to fail, got %d", chunk, importErr.Failed[i].Chunk)
+					}
+				}
+			}
+
+			calls := helper.RecordedCalls()
+			if len(calls) != len(tt.expectedSizes) {
+				t.Fatalf("expected %d requests, got %d", len(tt.expectedSizes), len(calls))
+			}
+			for i, call := range calls {
+				if !strings.HasSuffix(call.Path, "/segments/add/bulk") {
+					t.Errorf("expected the bulk endpoint, got %s", call.Path)
+				}
+				body := call.Body.(map[string]any)
+				if body["segment"] != tt.segment {
+					t.Errorf("expected segment %s, got %v", tt.segment, body["segment"])
+				}
+				if size := len(body["user_ids"].([]any)); size != tt.expectedSizes[i] {
+					t.Errorf("expected chunk %d to hold %d users, got %d", i, tt.expectedSizes[i], size)
+				}
+			}
+		})
+	}
+}
+
+func TestDashgram_AddUsersToSegmentCancelled(t *testing.T) {
+	userIDs := make([]int64, 1200)
+	for i := range userIDs {
+		userIDs[i] = int64(i + 1)
+	}
+
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			// Cancel the import once the first chunk is sent
+			cancel()
+			return helper.MockHTTPClient().Do(req)
+		},
+	}
+
+	d := New(123, "test-key", WithHTTPClient(client))
+	defer d.Close()
+
+	err := d.AddUsersToSegment(ctx, "beta_testers", userIDs)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if helper.RequestCount != 1 {
+		t.Errorf("expected the remaining chunks not to be sent, got %d requests", helper.RequestCount)
+	}
+}
@@ -0,0 +1,57 @@
+package dashgram
+
+import "fmt"
+
+// ProjectIDHasher maps a user ID to an index of the project IDs passed to
+// WithProjectIDHasher, taken modulo their number
+type ProjectIDHasher func(userID int) int
+
+// WithProjectIDHasher distributes events across several projects, e.g. one
+// per region, by user ID. The events tracked one at a time by the TrackEvent
+// methods are sent to projectIDs[fn(userID) % len(projectIDs)], where userID
+// is found as with WithUserIDExtractor; events without a user and other
+// requests go to the client's project ID. projectIDs must not be empty.
+func WithProjectIDHasher(fn ProjectIDHasher, projectIDs []int) Option {
+	return func(d *Dashgram) {
+		d.projectIDHasher = fn
+		d.shardProjectIDs = append([]int(nil), projectIDs...)
+	}
+}
+
+// withProjectID sends a single call to another project
+func withProjectID(projectID int) CallOption {
+	return func(co *callOptions) {
+		co.projectID = projectID
+	}
+}
+
+// validateSharding checks the project IDs of WithProjectIDHasher
+func (d *Dashgram) validateSharding() error {
+	if d.projectIDHasher != nil && len(d.shardProjectIDs) == 0 {
+		return &ConfigurationError{Field: "ProjectIDs", Err: fmt.Errorf("must not be empty")}
+	}
+	return nil
+}
+
+// shardOptions returns the call options of an event, sending it to the
+// project of its user if WithProjectIDHasher is set
+func (d *Dashgram) shardOptions(event any, opts []CallOption) []CallOption {
+	if d.projectIDHasher == nil {
+		return opts
+	}
+
+	userID, ok := d.extractUserID(event)
+	if !ok {
+		return opts
+	}
+
+	index := d.projectIDHasher(int(userID)) % len(d.shardProjectIDs)
+	if index < 0 {
+		index += len(d.shardProjectIDs)
+	}
+
+	// Copy the options so the caller's slice is never appended to
+	sharded := make([]CallOption, len(opts), len(opts)+1)
+	copy(sharded, opts)
+	return append(sharded, withProjectID(d.shardProjectIDs[index]))
+}
@@ -0,0 +1,133 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestDashgram_WithProjectIDHasher(t *testing.T) {
+	modulo := func(userID int) int { return userID }
+
+	tests := []struct {
+		name         string
+		hasher       ProjectIDHasher
+		event        any
+		expectedPath string
+	}{
+		{
+			name:         "first project",
+			hasher:       modulo,
+			event:        map[string]any{"action": "open", "user_id": 3},
+			expectedPath: "/v1/10/track",
+		},
+		{
+			name:         "second project",
+			hasher:       modulo,
+			event:        map[string]any{"action": "open", "user_id": 4},
+			expectedPath: "/v1/20/track",
+		},
+		{
+			name:         "raw JSON event",
+			hasher:       modulo,
+			event:        json.RawMessage(`{"action":"open","user_id":5}`),
+			expectedPath: "/v1/30/track",
+		},
+		{
+			name:         "negative hash",
+			hasher:       func(userID int) int { return -userID },
+			event:        map[string]any{"action": "open", "user_id": 1},
+			expectedPath: "/v1/30/track",
+		},
+		{
+			name:         "no user",
+			hasher:       modulo,
+			event:        map[string]any{"action": "open"},
+			expectedPath: "/v1/123/track",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key",
+				WithHTTPClient(helper.MockHTTPClient()),
+				WithProjectIDHasher(tt.hasher, []int{10, 20, 30}),
+			)
+			defer d.Close()
+
+			if err := d.TrackEvent(tt.event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if path := helper.LastRequest().URL.Path; path != tt.expectedPath {
+				t.Errorf("expected path %s, got %s", tt.expectedPath, path)
+			}
+		})
+	}
+}
+
+func TestDashgram_WithProjectIDHasherAsync(t *testing.T) {
+	helper := NewTestHelper()
+	for i := 0; i < 3; i++ {
+		helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	}
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithNumWorkers(1),
+		WithProjectIDHasher(func(userID int) int { return userID }, []int{10, 20}),
+	)
+
+	d.TrackEventAsync(map[string]any{"action": "open", "user_id": 1})
+	d.TrackEventAsync(map[string]any{"action": "open", "user_id": 2})
+	d.InvitedBy(1, 2)
+	d.Close()
+
+	expected := []string{"/v1/20/track", "/v1/10/track", "/v1/123/invited_by"}
+	calls := helper.RecordedCalls()
+	if len(calls) != len(expected) {
+		t.Fatalf("expected %d requests, got %d", len(expected), len(calls))
+	}
+	paths := map[string]bool{}
+	for _, call := range calls {
+		paths[call.Path] = true
+	}
+	for _, path := range expected {
+		if !paths[path] {
+			t.Errorf("expected a request to %s, got %+v", path, calls)
+		}
+	}
+}
+
+func TestDashgram_WithProjectIDHasherMultipart(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithProjectIDHasher(func(userID int) int { return userID }, []int{10, 20}),
+	)
+	defer d.Close()
+
+	attachments := []Attachment{{Name: "photo", FileName: "photo.jpg", Data: []byte("jpeg")}}
+	if err := d.TrackEventMultipart(context.Background(), map[string]any{"action": "open", "user_id": 1}, attachments); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path := helper.LastRequest().URL.Path; path != "/v1/20/track" {
+		t.Errorf("expected path /v1/20/track, got %s", path)
+	}
+}
+
+func TestDashgram_WithProjectIDHasherNoProjects(t *testing.T) {
+	d := New(123, "test-key", WithProjectIDHasher(func(userID int) int { return userID }, nil))
+	defer d.Close()
+
+	var configErr *ConfigurationError
+	if !errors.As(d.InitErr(), &configErr) || configErr.Field != "ProjectIDs" {
+		t.Errorf("expected ConfigurationError on ProjectIDs, got %v", d.InitErr())
+	}
+}
@@ -0,0 +1,55 @@
+package dashgram
+
+// WithShutdownErrorHandler sets a function called by Close when the async
+// tasks didn't drain cleanly, with the *ShutdownError also returned by
+// ShutdownResult. Such errors are logged either way.
+func WithShutdownErrorHandler(fn func(err error)) Option {
+	return func(d *Dashgram) {
+		d.shutdownHandler = fn
+	}
+}
+
+// ShutdownResult reports whether the async tasks drained cleanly on Close. It
+// returns a *ShutdownError if tasks failed while Close waited for them or were
+// left in the queue, and nil otherwise or before Close returns.
+func (d *Dashgram) ShutdownResult() error {
+	if err := d.shutdownErr.Load(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// dropPending drops the tasks left in the queues once the workers stopped, so
+// their result channels and callbacks still get a result
+func (d *Dashgram) dropPending() {
+	drop := func(task asyncTask) {
+		d.dropTask(task, "client closed")
+	}
+	d.pool.drain(drop)
+	for _, pool := range d.endpointPools {
+		pool.drain(drop)
+	}
+}
+
+// recordShutdown records the outcome of Close, given the number of failed
+// tasks when Close was called
+func (d *Dashgram) recordShutdown(failedBefore int64) {
+	err := &ShutdownError{
+		Failed:  d.stats.failed.Load() - failedBefore,
+		Pending: d.QueueLength(),
+	}
+	if err.Failed == 0 && err.Pending == 0 {
+		return
+	}
+
+	d.shutdownErr.Store(err)
+	d.log(LogLevelError, "async tasks did not drain cleanly", "failed", err.Failed, "pending", err.Pending)
+
+	if d.shutdownHandler != nil {
+		defer func() {
+			// A misbehaving handler must not break Close
+			recover()
+		}()
+		d.shutdownHandler(err)
+	}
+}
@@ -0,0 +1,155 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashgram_ShutdownResult(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		body           string
+		failBeforeStop bool
+		expectedFailed int64
+	}{
+		{
+			name:   "drained cleanly",
+			status: http.StatusOK,
+			body:   `{"status":"success","details":"ok"}`,
+		},
+		{
+			name:           "failed while draining",
+			status:         http.StatusInternalServerError,
+			body:           `{"status":"error","details":"internal error"}`,
+			expectedFailed: 3,
+		},
+		{
+			name:           "failed before close",
+			status:         http.StatusInternalServerError,
+			body:           `{"status":"error","details":"internal error"}`,
+			failBeforeStop: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					<-release
+					return &http.Response{
+						StatusCode: tt.status,
+						Body:       io.NopCloser(strings.NewReader(tt.body)),
+					}, nil
+				},
+			}
+
+			var handled []error
+			d := New(123, "test-key",
+				WithHTTPClient(mockClient),
+				WithNumWorkers(1),
+				WithShutdownErrorHandler(func(err error) { handled = append(handled, err) }),
+			)
+
+			if tt.failBeforeStop {
+				close(release)
+				d.TrackEventAsync(map[string]any{"action": "failed"})
+				waitFor(t, func() bool { return d.Stats().Failed == 1 })
+			} else {
+				// Hold the requests until Close starts draining
+				go func() {
+					<-d.workerCtx.Done()
+					close(release)
+				}()
+				for i := 0; i < 3; i++ {
+					d.TrackEventAsync(map[string]any{"action": "pending"})
+				}
+			}
+
+			if err := d.ShutdownResult(); err != nil {
+				t.Errorf("expected no shutdown result before Close, got %v", err)
+			}
+
+			d.Close()
+
+			err := d.ShutdownResult()
+			if tt.expectedFailed == 0 {
+				if err != nil {
+					t.Errorf("unexpected shutdown result: %v", err)
+				}
+				if len(handled) != 0 {
+					t.Errorf("expected the handler not to be called, got %v", handled)
+				}
+				return
+			}
+
+			var shutdownErr *ShutdownError
+			if !errors.As(err, &shutdownErr) {
+				t.Fatalf("expected ShutdownError, got %v", err)
+			}
+			if shutdownErr.Failed != tt.expectedFailed {
+				t.Errorf("expected %d failed tasks, got %d", tt.expectedFailed, shutdownErr.Failed)
+			}
+			if shutdownErr.Pending != 0 {
+				t.Errorf("expected no pending tasks, got %d", shutdownErr.Pending)
+			}
+			if len(handled) != 1 || handled[0] != err {
+				t.Errorf("expected the handler to be called once with %v, got %v", err, handled)
+			}
+		})
+	}
+}
+
+func TestDashgram_CloseDropsQueuedTasks(t *testing.T) {
+	d := New(123, "test-key", WithHTTPClient(NewTestHelper().MockHTTPClient()), WithAsyncEventOrdering())
+
+	// Stop the workers first, as if the tasks raced past the closed check
+	d.workerCancel()
+	d.workerWg.Wait()
+
+	result := make(chan error, 1)
+	var callbackErr error
+	d.pool.laneFor("").queue(PriorityNormal) <- asyncTask{ctx: context.Background(), endpoint: "invited_by", result: result}
+	d.pool.laneFor("").queue(PriorityLow) <- asyncTask{ctx: context.Background(), endpoint: "track", callback: func(err error) {
+		callbackErr = err
+	}}
+	d.Close()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, ErrTaskDropped) {
+			t.Errorf("expected ErrTaskDropped, got %v", err)
+		}
+	default:
+		t.Fatal("expected the queued task to get a result")
+	}
+	if !errors.Is(callbackErr, ErrTaskDropped) {
+		t.Errorf("expected the callback to get ErrTaskDropped, got %v", callbackErr)
+	}
+
+	var shutdownErr *ShutdownError
+	if !errors.As(d.ShutdownResult(), &shutdownErr) || shutdownErr.Pending != 2 {
+		t.Errorf("expected 2 pending tasks, got %v", d.ShutdownResult())
+	}
+	if length := d.QueueLength(); length != 0 {
+		t.Errorf("expected the queues to be drained, got %d tasks", length)
+	}
+}
+
+// waitFor polls cond until it holds or a second elapses
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
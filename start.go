@@ -0,0 +1,193 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// StartSource is the acquisition source parsed from a /start payload
+type StartSource struct {
+	// Source is the campaign or ref code, e.g. "campaign_xyz"
+	Source string
+	// ReferrerID is the user who shared the link, 0 if the payload has none
+	ReferrerID int64
+	// Params holds the parameters of encoded payloads, e.g. utm_medium
+	Params map[string]string
+}
+
+// StartPayloadParser parses a /start payload, returning false if it doesn't
+// follow the parser's convention
+type StartPayloadParser func(payload string) (StartSource, bool)
+
+// WithStartPayloadParsers sets how TrackStart parses payloads: the parsers are
+// tried in order and the first one returning true wins. Payloads no parser
+// accepts are tracked as plain ref codes. The default is ParseRefPayload then
+// ParseBase64Payload; include them to keep them alongside custom parsers.
+func WithStartPayloadParsers(parsers ...StartPayloadParser) Option {
+	return func(d *Dashgram) {
+		d.startParsers = parsers
+		d.startParsersSet = true
+	}
+}
+
+// WithStartInvitedBy sets whether TrackStart also tracks an invitation when
+// the payload holds a referrer, true by default
+func WithStartInvitedBy(enabled bool) Option {
+	return func(d *Dashgram) {
+		d.startNoInvite = !enabled
+	}
+}
+
+// ParseRefPayload parses "ref_<user ID>" payloads, e.g. "ref_42", into the
+// source "ref" with referrer 42
+func ParseRefPayload(payload string) (StartSource, bool) {
+	id, ok := strings.CutPrefix(payload, "ref_")
+	if !ok {
+		return StartSource{}, false
+	}
+	referrerID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || referrerID <= 0 {
+		return StartSource{}, false
+	}
+	return StartSource{Source: "ref", ReferrerID: referrerID}, true
+}
+
+// ParseBase64Payload parses URL-safe base64 encoded query strings, e.g. the
+// encoding of "source=ads&utm_medium=cpc&ref=42". The source is read from the
+// "source", "utm_source" or "campaign" parameter and the referrer from "ref".
+// Telegram doesn't allow padding in payloads, so it is optional.
+func ParseBase64Payload(payload string) (StartSource, bool) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(payload, "="))
+	if err != nil || !utf8.Valid(decoded) || !strings.Contains(string(decoded), "=") {
+		return StartSource{}, false
+	}
+	values, err := url.ParseQuery(string(decoded))
+	if err != nil {
+		return StartSource{}, false
+	}
+
+	source := StartSource{Params: make(map[string]string, len(values))}
+	for key := range values {
+		source.Params[key] = values.Get(key)
+	}
+	for _, key := range []string{"source", "utm_source", "campaign"} {
+		if value := values.Get(key); value != "" {
+			source.Source = value
+			break
+		}
+	}
+	if ref := values.Get("ref"); ref != "" {
+		if referrerID, err := strconv.ParseInt(ref, 10, 64); err == nil && referrerID > 0 {
+			source.ReferrerID = referrerID
+		}
+	}
+	return source, true
+}
+
+// parseStartPayload parses the payload with the configured parsers, falling
+// back to a plain ref code
+func (d *Dashgram) parseStartPayload(payload string) StartSource {
+	parsers := d.startParsers
+	if !d.startParsersSet {
+		parsers = []StartPayloadParser{ParseRefPayload, ParseBase64Payload}
+	}
+	for _, parse := range parsers {
+		if source, ok := parse(payload); ok {
+			return source
+		}
+	}
+	return StartSource{Source: payload}
+}
+
+// startEvent validates the arguments of TrackStart and returns the event to
+// track and the parsed source
+func (d *Dashgram) startEvent(userID int64, payload string) (map[string]any, StartSource, error) {
+	if userID <= 0 {
+		return nil, StartSource{}, &ValidationError{Field: "userID", Message: "must be positive"}
+	}
+
+	properties := map[string]any{}
+	var source StartSource
+	if payload = strings.TrimSpace(payload); payload != "" {
+		source = d.parseStartPayload(payload)
+		properties["payload"] = payload
+	}
+	if source.Source != "" {
+		properties["source"] = source.Source
+	}
+	if source.ReferrerID != 0 {
+		properties["referrer_id"] = source.ReferrerID
+	}
+	if len(source.Params) > 0 {
+		properties["params"] = source.Params
+	}
+
+	return map[string]any{
+		"action":     "start",
+		"user_id":    userID,
+		"properties": properties,
+	}, source, nil
+}
+
+// invitesFrom reports whether TrackStart tracks the invitation of userID by
+// the referrer of source
+func (d *Dashgram) invitesFrom(userID int64, source StartSource) bool {
+	return !d.startNoInvite && source.ReferrerID > 0 && source.ReferrerID != userID
+}
+
+// TrackStart tracks a user starting the bot through a deep link such as
+// t.me/mybot?start=campaign_xyz, with the acquisition source parsed from the
+// start payload:
+//
+//	{"action": "start", "user_id": 42, "properties": {"payload": "ref_7", "source": "ref", "referrer_id": 7}}
+//
+// Plain payloads are tracked as ref codes, "ref_<user ID>" payloads and
+// base64 encoded query strings are parsed, see WithStartPayloadParsers. When
+// the payload holds a referrer other than the user, the invitation is also
+// tracked with InvitedBy, unless disabled with WithStartInvitedBy. An empty
+// payload tracks an organic start.
+func (d *Dashgram) TrackStart(ctx context.Context, userID int64, startPayload string) error {
+	event, source, err := d.startEvent(userID, startPayload)
+	if err != nil {
+		return err
+	}
+
+	if err := d.TrackEventWithContext(ctx, event); err != nil {
+		return err
+	}
+	if !d.invitesFrom(userID, source) {
+		return nil
+	}
+	return d.InvitedByWithOptions(ctx, userID, source.ReferrerID, InviteOptions{
+		Campaign: source.Source,
+		Payload:  strings.TrimSpace(startPayload),
+	})
+}
+
+// TrackStartAsyncWithContext validates the arguments and enqueues the start,
+// and the invitation if any, to be tracked asynchronously
+func (d *Dashgram) TrackStartAsyncWithContext(ctx context.Context, userID int64, startPayload string) error {
+	event, source, err := d.startEvent(userID, startPayload)
+	if err != nil {
+		return err
+	}
+
+	if err := d.TrackEventAsyncWithContext(ctx, event); err != nil {
+		return err
+	}
+	if d.invitesFrom(userID, source) {
+		d.InvitedByAsyncWithOptions(ctx, userID, source.ReferrerID, InviteOptions{
+			Campaign: source.Source,
+			Payload:  strings.TrimSpace(startPayload),
+		})
+	}
+	return nil
+}
+
+func (d *Dashgram) TrackStartAsync(userID int64, startPayload string) error {
+	return d.TrackStartAsyncWithContext(context.Background(), userID, startPayload)
+}
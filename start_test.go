@@ -0,0 +1,176 @@
+package dashgram
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStartPayload(t *testing.T) {
+	tests := []struct {
+		payload    string
+		expected   StartSource
+		expectedOK bool
+	}{
+		{payload: "ref_42", expected: StartSource{Source: "ref", ReferrerID: 42}, expectedOK: true},
+		{payload: "ref_abc"},
+		{payload: "ref_-1"},
+		{
+			payload:    "c291cmNlPWFkcyZyZWY9Nw",
+			expected:   StartSource{Source: "ads", ReferrerID: 7, Params: map[string]string{"source": "ads", "ref": "7"}},
+			expectedOK: true,
+		},
+		{
+			payload:    "dXRtX3NvdXJjZT10ZyZ1dG1fbWVkaXVtPWNwYw",
+			expected:   StartSource{Source: "tg", Params: map[string]string{"utm_source": "tg", "utm_medium": "cpc"}},
+			expectedOK: true,
+		},
+		{payload: "campaign_xyz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.payload, func(t *testing.T) {
+			source, ok := ParseRefPayload(tt.payload)
+			if !ok {
+				source, ok = ParseBase64Payload(tt.payload)
+			}
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok %v, got %v", tt.expectedOK, ok)
+			}
+			if ok && !reflect.DeepEqual(source, tt.expected) {
+				t.Errorf("expected source %+v, got %+v", tt.expected, source)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackStart(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        int64
+		payload       string
+		options       []Option
+		expected      []string
+		expectedField string
+	}{
+		{
+			name:     "plain ref code",
+			userID:   12345,
+			payload:  "campaign_xyz",
+			expected: []string{`{"updates":[{"action":"start","properties":{"payload":"campaign_xyz","source":"campaign_xyz"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`},
+		},
+		{
+			name:     "organic",
+			userID:   12345,
+			expected: []string{`{"updates":[{"action":"start","properties":{},"user_id":12345}],"origin":"Go + Dashgram SDK"}`},
+		},
+		{
+			name:    "referral",
+			userID:  12345,
+			payload: "ref_42",
+			expected: []string{
+				`{"updates":[{"action":"start","properties":{"payload":"ref_42","referrer_id":42,"source":"ref"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+				`{"user_id":12345,"invited_by":42,"campaign":"ref","payload":"ref_42","origin":"Go + Dashgram SDK"}`,
+			},
+		},
+		{
+			name:    "encoded params",
+			userID:  12345,
+			payload: "c291cmNlPWFkcyZyZWY9Nw",
+			expected: []string{
+				`{"updates":[{"action":"start","properties":{"params":{"ref":"7","source":"ads"},"payload":"c291cmNlPWFkcyZyZWY9Nw","referrer_id":7,"source":"ads"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+				`{"user_id":12345,"invited_by":7,"campaign":"ads","payload":"c291cmNlPWFkcyZyZWY9Nw","origin":"Go + Dashgram SDK"}`,
+			},
+		},
+		{
+			name:     "self referral",
+			userID:   42,
+			payload:  "ref_42",
+			expected: []string{`{"updates":[{"action":"start","properties":{"payload":"ref_42","referrer_id":42,"source":"ref"},"user_id":42}],"origin":"Go + Dashgram SDK"}`},
+		},
+		{
+			name:     "invitation disabled",
+			userID:   12345,
+			payload:  "ref_42",
+			options:  []Option{WithStartInvitedBy(false)},
+			expected: []string{`{"updates":[{"action":"start","properties":{"payload":"ref_42","referrer_id":42,"source":"ref"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`},
+		},
+		{
+			name:    "custom parser",
+			userID:  12345,
+			payload: "fb-summer",
+			options: []Option{WithStartPayloadParsers(func(payload string) (StartSource, bool) {
+				network, campaign, ok := strings.Cut(payload, "-")
+				return StartSource{Source: campaign, Params: map[string]string{"network": network}}, ok
+			})},
+			expected: []string{`{"updates":[{"action":"start","properties":{"params":{"network":"fb"},"payload":"fb-summer","source":"summer"},"user_id":12345}],"origin":"Go + Dashgram SDK"}`},
+		},
+		{
+			name:          "invalid user",
+			userID:        0,
+			payload:       "ref_42",
+			expectedField: "userID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			for range tt.expected {
+				helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+			}
+
+			d := New(123, "test-key", append([]Option{WithHTTPClient(helper.MockHTTPClient())}, tt.options...)...)
+			defer d.Close()
+
+			err := d.TrackStart(context.Background(), tt.userID, tt.payload)
+
+			if tt.expectedField != "" {
+				assertValidationError(t, err, tt.expectedField, helper)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			requests := helper.RecordedRequests()
+			if len(requests) != len(tt.expected) {
+				t.Fatalf("expected %d requests, got %d", len(tt.expected), len(requests))
+			}
+			for i, expected := range tt.expected {
+				if body := string(requests[i].Body); body != expected {
+					t.Errorf("expected body %s, got %s", expected, body)
+				}
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackStartAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+
+	if err := d.TrackStartAsync(12345, "ref_42"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.TrackStartAsync(0, "ref_42"); err == nil {
+		t.Error("expected an invalid user to be rejected")
+	}
+	d.Close()
+
+	paths := map[string]bool{}
+	for _, call := range helper.RecordedCalls() {
+		paths[call.Path] = true
+	}
+	if !paths["/v1/123/track"] || !paths["/v1/123/invited_by"] {
+		t.Errorf("expected the start and the invitation to be tracked, got %v", paths)
+	}
+	if enqueued := d.Stats().Enqueued; enqueued != 2 {
+		t.Errorf("expected 2 enqueued tasks, got %d", enqueued)
+	}
+}
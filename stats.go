@@ -0,0 +1,127 @@
+package dashgram
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the client's async counters
+type Stats struct {
+	// Enqueued is the number of tasks accepted by the async queue
+	Enqueued int64
+	// Dropped is the number of tasks dropped because the client was closed or the queue was full
+	Dropped int64
+	// Delivered is the number of async tasks sent successfully
+	Delivered int64
+	// Failed is the number of async tasks whose request failed
+	Failed int64
+	// Fallback is the number of async tasks sent synchronously because the queue was full
+	Fallback int64
+	// Suppressed is the number of events dropped because their user opted out, see WithOptOut
+	Suppressed int64
+	// SampledOut is the number of events dropped by sampling, see WithSampleRate
+	SampledOut int64
+	// Filtered is the number of payloads dropped by the WithBeforeSend hook
+	Filtered int64
+	// Debounced is the number of events replaced by a later event with the same key, see WithDebounce
+	Debounced int64
+	// QueueLength is the number of tasks waiting for a worker
+	QueueLength int
+	// InFlight is the number of tasks whose request is being sent by a worker
+	InFlight int
+	// AvgQueueWait is the average time tasks waited in the queue before a worker picked them up
+	AvgQueueWait time.Duration
+}
+
+// statsCounters holds the counters reported by Stats
+type statsCounters struct {
+	enqueued   atomic.Int64
+	dropped    atomic.Int64
+	delivered  atomic.Int64
+	failed     atomic.Int64
+	fallback   atomic.Int64
+	suppressed atomic.Int64
+	sampledOut atomic.Int64
+	filtered   atomic.Int64
+	debounced  atomic.Int64
+	inFlight   atomic.Int64
+	// queueWait is the total time, in nanoseconds, dequeued tasks spent in the queue
+	queueWait atomic.Int64
+	dequeued  atomic.Int64
+}
+
+// Stats returns a snapshot of the client's async counters
+func (d *Dashgram) Stats() Stats {
+	return Stats{
+		Enqueued:     d.stats.enqueued.Load(),
+		Dropped:      d.stats.dropped.Load(),
+		Delivered:    d.stats.delivered.Load(),
+		Failed:       d.stats.failed.Load(),
+		Fallback:     d.stats.fallback.Load(),
+		Suppressed:   d.stats.suppressed.Load(),
+		SampledOut:   d.stats.sampledOut.Load(),
+		Filtered:     d.stats.filtered.Load(),
+		Debounced:    d.stats.debounced.Load(),
+		QueueLength:  d.QueueLength(),
+		InFlight:     d.InFlight(),
+		AvgQueueWait: d.AvgQueueWait(),
+	}
+}
+
+// QueueLength returns the number of tasks waiting for a worker across all worker pools
+func (d *Dashgram) QueueLength() int {
+	length := d.pool.len()
+	for _, pool := range d.endpointPools {
+		length += pool.len()
+	}
+	return length
+}
+
+// InFlight returns the number of tasks whose request is being sent by a
+// worker. Together with QueueLength it tells whether async work is pending.
+func (d *Dashgram) InFlight() int {
+	return int(d.stats.inFlight.Load())
+}
+
+// AvgQueueWait returns the average time async tasks waited in the queue
+// before a worker picked them up, or zero if no task was picked up yet
+func (d *Dashgram) AvgQueueWait() time.Duration {
+	dequeued := d.stats.dequeued.Load()
+	if dequeued == 0 {
+		return 0
+	}
+	return time.Duration(d.stats.queueWait.Load() / dequeued)
+}
+
+// WithStatsReporter calls fn with a snapshot of the stats every interval, and
+// once more when the client is closed
+func WithStatsReporter(interval time.Duration, fn func(Stats)) Option {
+	return func(d *Dashgram) {
+		d.statsInterval = interval
+		d.statsReporter = fn
+	}
+}
+
+// startStatsReporter starts the goroutine periodically reporting the stats
+func (d *Dashgram) startStatsReporter() {
+	if d.statsReporter == nil || d.statsInterval <= 0 {
+		return
+	}
+
+	d.workerWg.Add(1)
+	go func() {
+		defer d.workerWg.Done()
+
+		ticker := time.NewTicker(d.statsInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				d.statsReporter(d.Stats())
+			case <-d.workerCtx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,254 @@
+package dashgram
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDashgram_Stats(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(400, `{"status":"error","details":"bad request"}`)
+	helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+
+	// A frozen clock makes the queue wait zero
+	clock := newFakeClock()
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), withClock(clock.Now))
+
+	for i := 0; i < 3; i++ {
+		d.TrackEventAsync(map[string]any{"action": "test", "index": i})
+	}
+
+	if !helper.WaitForRequests(3, time.Second) {
+		t.Fatalf("expected 3 requests")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && d.Stats().Delivered+d.Stats().Failed < 3 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	d.Close()
+
+	// Tasks enqueued after Close are dropped
+	d.TrackEventAsync(map[string]any{"action": "dropped"})
+
+	expected := Stats{
+		Enqueued:  3,
+		Dropped:   1,
+		Delivered: 2,
+		Failed:    1,
+	}
+	if stats := d.Stats(); stats != expected {
+		t.Errorf("expected stats %+v, got %+v", expected, stats)
+	}
+}
+
+func TestDashgram_QueueLength(t *testing.T) {
+	release := make(chan struct{})
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			<-release
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	d := New(123, "test-key", WithHTTPClient(mockClient), WithEndpointWorkers("invited_by", 1))
+	defer d.Close()
+	defer close(release)
+
+	// One task of each pool is picked up by the blocked workers, the rest waits
+	for i := 0; i < 3; i++ {
+		d.TrackEventAsync(map[string]any{"action": "test"})
+		d.InvitedByAsync(12345, 67890)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && d.QueueLength() != 4 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if d.QueueLength() != 4 {
+		t.Errorf("expected queue length 4, got %d", d.QueueLength())
+	}
+}
+
+func TestDashgram_InFlight(t *testing.T) {
+	release := make(chan struct{})
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			<-release
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	d := New(123, "test-key", WithHTTPClient(mockClient), WithNumWorkers(3))
+	defer d.Close()
+
+	for i := 0; i < 5; i++ {
+		d.TrackEventAsync(map[string]any{"action": "test"})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && d.InFlight() != 3 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if d.InFlight() != 3 {
+		t.Errorf("expected 3 in-flight tasks, got %d", d.InFlight())
+	}
+	if d.QueueLength() != 2 {
+		t.Errorf("expected queue length 2, got %d", d.QueueLength())
+	}
+
+	close(release)
+
+	deadline = time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && (d.InFlight() != 0 || d.QueueLength() != 0) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if stats := d.Stats(); stats.InFlight != 0 || stats.QueueLength != 0 {
+		t.Errorf("expected no pending tasks, got %d in flight and %d queued", stats.InFlight, stats.QueueLength)
+	}
+}
+
+func TestDashgram_WithStatsReporter(t *testing.T) {
+	helper := NewTestHelper()
+	for i := 0; i < 20; i++ {
+		helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+	}
+
+	var mu sync.Mutex
+	var snapshots []Stats
+
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithStatsReporter(5*time.Millisecond, func(stats Stats) {
+			mu.Lock()
+			snapshots = append(snapshots, stats)
+			mu.Unlock()
+		}),
+	)
+
+	for i := 0; i < 20; i++ {
+		d.TrackEventAsync(map[string]any{"action": "test", "index": i})
+		time.Sleep(time.Millisecond)
+	}
+
+	if !helper.WaitForRequests(20, time.Second) {
+		t.Fatalf("expected 20 requests")
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	d.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(snapshots) < 2 {
+		t.Fatalf("expected periodic snapshots, got %d", len(snapshots))
+	}
+
+	for i := 1; i < len(snapshots); i++ {
+		prev, cur := snapshots[i-1], snapshots[i]
+		if cur.Enqueued < prev.Enqueued || cur.Delivered < prev.Delivered || cur.Failed < prev.Failed || cur.Dropped < prev.Dropped {
+			t.Errorf("expected monotonic counters, got %+v after %+v", cur, prev)
+		}
+		if cur.Delivered > cur.Enqueued {
+			t.Errorf("expected delivered <= enqueued, got %+v", cur)
+		}
+	}
+
+	// The final snapshot is reported on Close
+	if last := snapshots[len(snapshots)-1]; last.Enqueued != 20 || last.Delivered != 20 {
+		t.Errorf("expected final snapshot with 20 enqueued and delivered tasks, got %+v", last)
+	}
+
+	// The reporter goroutine is stopped by Close
+	count := len(snapshots)
+	mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	if len(snapshots) != count {
+		t.Errorf("expected no snapshots after Close")
+	}
+}
+
+// fakeClock is a clock that only moves when advanced
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// withClock replaces the clock of the client
+func withClock(now func() time.Time) Option {
+	return func(d *Dashgram) {
+		d.now = now
+	}
+}
+
+func TestDashgram_AvgQueueWait(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			started <- struct{}{}
+			<-release
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	clock := newFakeClock()
+	d := New(123, "test-key", WithHTTPClient(mockClient), withClock(clock.Now))
+	defer d.Close()
+
+	if wait := d.AvgQueueWait(); wait != 0 {
+		t.Errorf("expected no queue wait before any task, got %v", wait)
+	}
+
+	// The first task is picked up at once and keeps the worker busy
+	d.TrackEventAsync(map[string]any{"action": "first"})
+	<-started
+
+	// The second task waits in the queue while the clock moves
+	d.TrackEventAsync(map[string]any{"action": "second"})
+	clock.Advance(250 * time.Millisecond)
+	close(release)
+	<-started
+
+	expected := 125 * time.Millisecond
+	if wait := d.AvgQueueWait(); wait != expected {
+		t.Errorf("expected average queue wait %v, got %v", expected, wait)
+	}
+	if wait := d.Stats().AvgQueueWait; wait != expected {
+		t.Errorf("expected Stats().AvgQueueWait %v, got %v", expected, wait)
+	}
+}
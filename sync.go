@@ -1,40 +1,446 @@
 package dashgram
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
 
-func (d *Dashgram) TrackEventWithContext(ctx context.Context, event any) error {
+func (d *Dashgram) TrackEventWithContext(ctx context.Context, event any, opts ...CallOption) error {
 	if d.useAsync {
-		d.TrackEventAsyncWithContext(ctx, event)
+		return d.TrackEventAsyncWithContext(ctx, event, opts...)
+	}
+
+	if err := validateEvent(event); err != nil {
+		return err
+	}
+	if d.skipEvent(ctx, event, opts) {
+		return nil
+	}
+	opts = d.shardOptions(event, opts)
+	if d.debounce(ctx, event, opts) {
+		return nil
+	}
+	return d.sendEvent(ctx, event, opts)
+}
+
+// sendEvent sends a validated event right away, once sampled and debounced
+func (d *Dashgram) sendEvent(ctx context.Context, event any, opts []CallOption) error {
+	event, ok := d.runBeforeSend("track", event)
+	if !ok {
+		return nil
+	}
+
+	// The request is encoded before request returns, so it can be reused
+	requestData := getTrackEventRequest(newCallOptions(opts).originOr(d.Origin), d.prepareUpdate(event))
+	defer putTrackEventRequest(requestData)
+
+	return d.request(ctx, "track", requestData, opts...)
+}
+
+// TrackEventBatchWithContext tracks several events with a single request. No
+// event is sent if any of them is invalid.
+func (d *Dashgram) TrackEventBatchWithContext(ctx context.Context, events []any, opts ...CallOption) error {
+	if len(events) == 0 {
+		return &ValidationError{Field: "events", Message: "must not be empty"}
+	}
+	for i, event := range events {
+		if err := validateEvent(event); err != nil {
+			return fmt.Errorf("event %d: %w", i, err)
+		}
+	}
+
+	if d.useAsync {
+		for _, event := range events {
+			if err := d.TrackEventAsyncWithContext(ctx, event, opts...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	updates := make([]any, 0, len(events))
+	for _, event := range events {
+		if d.skipEvent(ctx, event, opts) {
+			continue
+		}
+		if event, ok := d.runBeforeSend("track", event); ok {
+			updates = append(updates, d.prepareUpdate(event))
+		}
+	}
+	if len(updates) == 0 {
 		return nil
 	}
 
 	requestData := TrackEventRequest{
-		Origin:  d.Origin,
-		Updates: []any{event},
+		Origin:  newCallOptions(opts).originOr(d.Origin),
+		Updates: updates,
 	}
 
-	return d.request(ctx, "track", requestData)
+	return d.request(ctx, "track", requestData, opts...)
 }
 
-func (d *Dashgram) InvitedByWithContext(ctx context.Context, userID int, invitedBy int) error {
+// TrackEventWithResponse tracks an event and returns the API response, e.g. to
+// inspect rate limit or request ID headers. The response is also returned
+// with API errors such as a 429. It always runs synchronously. Both are nil
+// if the event is suppressed by WithOptOut.
+func (d *Dashgram) TrackEventWithResponse(ctx context.Context, event any, opts ...CallOption) (*Response, error) {
+	if err := validateEvent(event); err != nil {
+		return nil, err
+	}
+	if d.skipEvent(ctx, event, opts) {
+		return nil, nil
+	}
+	opts = d.shardOptions(event, opts)
+	event, ok := d.runBeforeSend("track", event)
+	if !ok {
+		return nil, nil
+	}
+
+	requestData := getTrackEventRequest(newCallOptions(opts).originOr(d.Origin), d.prepareUpdate(event))
+	defer putTrackEventRequest(requestData)
+
+	return d.do(ctx, http.MethodPost, "track", nil, requestData, nil, opts...)
+}
+
+// TrackEventJSONWithContext tracks a pre-serialized JSON event as is, without
+// decoding and re-encoding it
+func (d *Dashgram) TrackEventJSONWithContext(ctx context.Context, jsonBytes []byte, opts ...CallOption) error {
 	if d.useAsync {
-		d.InvitedByAsyncWithContext(ctx, userID, invitedBy)
+		return d.TrackEventJSONAsyncWithContext(ctx, jsonBytes, opts...)
+	}
+
+	if err := validateEventJSON(jsonBytes); err != nil {
+		return err
+	}
+
+	return d.TrackEventWithContext(ctx, json.RawMessage(jsonBytes), opts...)
+}
+
+// TrackEventWithUserIDAndContext tracks a copy of the event with its "user_id"
+// set to userID, overriding any existing value
+func (d *Dashgram) TrackEventWithUserIDAndContext(ctx context.Context, userID int64, event map[string]any, opts ...CallOption) error {
+	if event == nil {
+		return errNilEvent()
+	}
+
+	return d.TrackEventWithContext(ctx, withUserID(userID, event), opts...)
+}
+
+// TrackGroupEventWithContext tracks an event attributed to a group
+func (d *Dashgram) TrackGroupEventWithContext(ctx context.Context, groupID int, event any, opts ...CallOption) error {
+	if d.useAsync {
+		return d.TrackGroupEventAsyncWithContext(ctx, groupID, event, opts...)
+	}
+
+	requestData, ok, err := d.newGroupEventRequest(ctx, groupID, event, opts)
+	if err != nil || !ok {
+		return err
+	}
+
+	return d.request(ctx, "group_track", requestData, opts...)
+}
+
+func (d *Dashgram) InvitedByWithContext(ctx context.Context, userID int64, invitedBy int64, opts ...CallOption) error {
+	return d.InvitedByWithOptions(ctx, userID, invitedBy, InviteOptions{}, opts...)
+}
+
+func (d *Dashgram) InvitedByWithOptions(ctx context.Context, userID int64, invitedBy int64, inviteOpts InviteOptions, opts ...CallOption) error {
+	if d.useAsync {
+		d.InvitedByAsyncWithOptions(ctx, userID, invitedBy, inviteOpts, opts...)
+		return nil
+	}
+	if d.suppressUsers(userID, invitedBy) {
+		return nil
+	}
+
+	requestData, ok := d.runBeforeSend("invited_by", d.newInvitedByRequest(userID, invitedBy, inviteOpts, newCallOptions(opts).originOr(d.Origin)))
+	if !ok {
+		return nil
+	}
+
+	return d.request(ctx, "invited_by", requestData, opts...)
+}
+
+func (d *Dashgram) SetUserPropertiesWithContext(ctx context.Context, userID int64, props map[string]any) error {
+	if d.useAsync {
+		return d.SetUserPropertiesAsyncWithContext(ctx, userID, props)
+	}
+
+	if err := validateUserProperties(userID, props); err != nil {
+		return err
+	}
+	if d.suppressUsers(userID) {
+		return nil
+	}
+
+	requestData, ok := d.runBeforeSend("user_properties", UserPropertiesRequest{
+		UserID:     userID,
+		Properties: props,
+		Origin:     d.Origin,
+	})
+	if !ok {
+		return nil
+	}
+
+	return d.request(ctx, "user_properties", requestData)
+}
+
+// AliasUser merges an anonymous identity, e.g. a web app visitor, into a
+// Telegram user once their ID is known, so the events tracked under
+// anonymousID are attributed to userID. The merge is idempotent on the
+// server, so the call is safe to retry.
+func (d *Dashgram) AliasUser(ctx context.Context, anonymousID string, userID int64) error {
+	if d.useAsync {
+		return d.AliasUserAsyncWithContext(ctx, anonymousID, userID)
+	}
+
+	if err := validateAlias(anonymousID, userID); err != nil {
+		return err
+	}
+	if d.suppressUsers(userID) {
 		return nil
 	}
 
+	requestData, ok := d.runBeforeSend("alias", AliasRequest{
+		AnonymousID: anonymousID,
+		UserID:      userID,
+		Origin:      d.Origin,
+	})
+	if !ok {
+		return nil
+	}
+
+	return d.request(ctx, "alias", requestData)
+}
+
+// DeleteUserData purges the analytics data of a user, e.g. to honour a GDPR
+// erasure request. It always runs synchronously, even with WithUseAsync, so
+// the deletion is confirmed when it returns nil. A UserNotFoundError is
+// returned if the API doesn't know the user.
+func (d *Dashgram) DeleteUserData(ctx context.Context, userID int64) error {
+	if userID <= 0 {
+		return &ValidationError{Field: "userID", Message: "must be positive"}
+	}
+
+	_, err := d.do(ctx, http.MethodDelete, fmt.Sprintf("users/%d", userID), nil, nil, nil)
+
+	var apiErr *DashgramAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return &UserNotFoundError{UserID: userID}
+	}
+	return err
+}
+
+// Call makes a request to an endpoint the SDK doesn't wrap yet, e.g.
+// Call(ctx, http.MethodPost, "funnels", payload, &result). The request goes
+// through the same machinery as the other methods: authentication, retries,
+// timeouts and the mapping of API errors such as InvalidCredentialsError. The
+// payload is encoded as JSON, a map[string]any payload without an "origin"
+// getting the client's origin, and a nil payload sends no body. If out is not
+// nil, the response body is decoded into it. It always runs synchronously.
+func (d *Dashgram) Call(ctx context.Context, method, endpoint string, payload any, out any, opts ...CallOption) error {
+	if method == "" {
+		return &ValidationError{Field: "method", Message: "must not be empty"}
+	}
+	endpoint = strings.TrimPrefix(endpoint, "/")
+	if endpoint == "" {
+		return &ValidationError{Field: "endpoint", Message: "must not be empty"}
+	}
+
+	if m, ok := payload.(map[string]any); ok {
+		if _, ok := m["origin"]; !ok {
+			clone := make(map[string]any, len(m)+1)
+			for key, value := range m {
+				clone[key] = value
+			}
+			clone["origin"] = newCallOptions(opts).originOr(d.Origin)
+			payload = clone
+		}
+	}
+
+	_, err := d.do(ctx, method, endpoint, nil, payload, out, opts...)
+	return err
+}
+
+// ValidateCredentials verifies the access key with a minimal authenticated
+// request that tracks no events. It returns nil on success, an
+// InvalidCredentialsError or ForbiddenError if the key is rejected, and a
+// TransportError if the API can't be reached. It always runs synchronously.
+func (d *Dashgram) ValidateCredentials(ctx context.Context) error {
+	requestData := TrackEventRequest{
+		Origin:  d.Origin,
+		Updates: []any{},
+	}
+
+	return d.request(ctx, "track", requestData)
+}
+
+// Ping is an alias for ValidateCredentials, e.g. for readiness probes. Its
+// outcome can be cached with WithPingCache.
+func (d *Dashgram) Ping(ctx context.Context) error {
+	if d.pingCache.ttl > 0 {
+		return d.pingWithCache(ctx)
+	}
+	return d.ValidateCredentials(ctx)
+}
+
+func (d *Dashgram) TrackEvent(event any, opts ...CallOption) error {
+	return d.TrackEventWithContext(context.Background(), event, opts...)
+}
+
+func (d *Dashgram) TrackEventBatch(events []any, opts ...CallOption) error {
+	return d.TrackEventBatchWithContext(context.Background(), events, opts...)
+}
+
+func (d *Dashgram) TrackEventJSON(jsonBytes []byte, opts ...CallOption) error {
+	return d.TrackEventJSONWithContext(context.Background(), jsonBytes, opts...)
+}
+
+func (d *Dashgram) TrackEventWithUserID(userID int64, event map[string]any, opts ...CallOption) error {
+	return d.TrackEventWithUserIDAndContext(context.Background(), userID, event, opts...)
+}
+
+func (d *Dashgram) TrackGroupEvent(groupID int, event any, opts ...CallOption) error {
+	return d.TrackGroupEventWithContext(context.Background(), groupID, event, opts...)
+}
+
+func (d *Dashgram) InvitedBy(userID int64, invitedBy int64, opts ...CallOption) error {
+	return d.InvitedByWithContext(context.Background(), userID, invitedBy, opts...)
+}
+
+func (d *Dashgram) SetUserProperties(userID int64, props map[string]any) error {
+	return d.SetUserPropertiesWithContext(context.Background(), userID, props)
+}
+
+// validateEvent rejects nil and empty events before any request is made
+func validateEvent(event any) error {
+	if isNilEvent(event) {
+		return errNilEvent()
+	}
+	if v := reflect.ValueOf(event); v.Kind() == reflect.Map && v.Len() == 0 {
+		return &ValidationError{Field: "event", Message: "must not be empty"}
+	}
+	return nil
+}
+
+// errNilEvent returns the error of a nil event, it wraps ErrNilEvent
+func errNilEvent() error {
+	return &ValidationError{Field: "event", Message: "must not be nil", Err: ErrNilEvent}
+}
+
+// marshalUpdate encodes an update up front, so async calls report
+// unserializable events instead of failing later in a worker. Raw JSON, such
+// as the already validated events of TrackEventJSON, is copied rather than
+// re-encoded, so callers may reuse their buffer once the event is enqueued.
+func marshalUpdate(update any) (json.RawMessage, error) {
+	if raw, ok := update.(json.RawMessage); ok {
+		return append(json.RawMessage(nil), raw...), nil
+	}
+	raw, err := json.Marshal(update)
+	if err != nil {
+		return nil, &ValidationError{Field: "event", Message: "must be serializable to JSON", Err: err}
+	}
+	return raw, nil
+}
+
+// isNilEvent reports whether the event is nil or a nil pointer, map, slice or interface
+func isNilEvent(event any) bool {
+	if event == nil {
+		return true
+	}
+
+	switch v := reflect.ValueOf(event); v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// validateEventJSON checks the argument of the TrackEventJSON methods
+func validateEventJSON(jsonBytes []byte) error {
+	if !json.Valid(jsonBytes) {
+		return &ValidationError{Field: "jsonBytes", Message: "must be valid JSON"}
+	}
+	return nil
+}
+
+// withUserID returns a copy of the event with its "user_id" set
+func withUserID(userID int64, event map[string]any) map[string]any {
+	clone := make(map[string]any, len(event)+1)
+	for key, value := range event {
+		clone[key] = value
+	}
+	clone["user_id"] = userID
+
+	return clone
+}
+
+// newGroupEventRequest validates the arguments and builds the group_track
+// request payload. It returns false if the event must not be sent.
+func (d *Dashgram) newGroupEventRequest(ctx context.Context, groupID int, event any, opts []CallOption) (GroupEventRequest, bool, error) {
+	if groupID <= 0 {
+		return GroupEventRequest{}, false, &ValidationError{Field: "groupID", Message: "must be positive"}
+	}
+	if err := validateEvent(event); err != nil {
+		return GroupEventRequest{}, false, err
+	}
+	if d.skipEvent(ctx, event, opts) {
+		return GroupEventRequest{}, false, nil
+	}
+	event, ok := d.runBeforeSend("group_track", event)
+	if !ok {
+		return GroupEventRequest{}, false, nil
+	}
+
+	return GroupEventRequest{
+		GroupID: groupID,
+		Origin:  newCallOptions(opts).originOr(d.Origin),
+		Updates: []any{d.prepareUpdate(event)},
+	}, true, nil
+}
+
+// newInvitedByRequest builds the invited_by request payload
+func (d *Dashgram) newInvitedByRequest(userID int64, invitedBy int64, opts InviteOptions, origin string) InvitedByRequest {
 	requestData := InvitedByRequest{
 		UserID:    userID,
 		InvitedBy: invitedBy,
-		Origin:    d.Origin,
+		Campaign:  opts.Campaign,
+		Payload:   opts.Payload,
+		Extra:     opts.Extra,
+		Origin:    origin,
+	}
+	if !opts.Timestamp.IsZero() {
+		timestamp := opts.Timestamp
+		requestData.Timestamp = &timestamp
 	}
 
-	return d.request(ctx, "invited_by", requestData)
+	return requestData
 }
 
-func (d *Dashgram) TrackEvent(event any) error {
-	return d.TrackEventWithContext(context.Background(), event)
+// validateAlias checks the arguments of the AliasUser methods
+func validateAlias(anonymousID string, userID int64) error {
+	if anonymousID == "" {
+		return &ValidationError{Field: "anonymousID", Message: "must not be empty"}
+	}
+	if userID <= 0 {
+		return &ValidationError{Field: "userID", Message: "must be positive"}
+	}
+	return nil
 }
 
-func (d *Dashgram) InvitedBy(userID int, invitedBy int) error {
-	return d.InvitedByWithContext(context.Background(), userID, invitedBy)
+// validateUserProperties checks the arguments of the SetUserProperties methods
+func validateUserProperties(userID int64, props map[string]any) error {
+	if userID <= 0 {
+		return &ValidationError{Field: "userID", Message: "must be positive"}
+	}
+	if len(props) == 0 {
+		return &ValidationError{Field: "props", Message: "must not be empty"}
+	}
+	return nil
 }
@@ -2,11 +2,14 @@ package dashgram
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestDashgram_TrackEvent(t *testing.T) {
@@ -173,8 +176,8 @@ func TestDashgram_TrackEventWithContext(t *testing.T) {
 func TestDashgram_InvitedBy(t *testing.T) {
 	tests := []struct {
 		name          string
-		userID        int
-		invitedBy     int
+		userID        int64
+		invitedBy     int64
 		useAsync      bool
 		mockResponse  *http.Response
 		mockError     error
@@ -264,8 +267,8 @@ func TestDashgram_InvitedByWithContext(t *testing.T) {
 	tests := []struct {
 		name          string
 		ctx           context.Context
-		userID        int
-		invitedBy     int
+		userID        int64
+		invitedBy     int64
 		useAsync      bool
 		mockResponse  *http.Response
 		mockError     error
@@ -330,3 +333,929 @@ func TestDashgram_InvitedByWithContext(t *testing.T) {
 		})
 	}
 }
+
+func TestDashgram_SetUserProperties(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        int64
+		props         map[string]any
+		useAsync      bool
+		mockResponse  *http.Response
+		expectedError string
+		expectRequest bool
+	}{
+		{
+			name:   "successful set user properties",
+			userID: 12345,
+			props:  map[string]any{"plan": "premium", "language": "en"},
+			mockResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			},
+			expectRequest: true,
+		},
+		{
+			name:          "non-positive user ID",
+			userID:        0,
+			props:         map[string]any{"plan": "premium"},
+			expectedError: "invalid userID: must be positive",
+		},
+		{
+			name:          "empty properties",
+			userID:        12345,
+			props:         map[string]any{},
+			expectedError: "invalid props: must not be empty",
+		},
+		{
+			name:          "validation with async enabled",
+			userID:        -1,
+			props:         map[string]any{"plan": "premium"},
+			useAsync:      true,
+			expectedError: "invalid userID: must be positive",
+		},
+		{
+			name:   "API error response",
+			userID: 12345,
+			props:  map[string]any{"plan": "premium"},
+			mockResponse: &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"error","details":"invalid properties"}`)),
+			},
+			expectedError: "dashgram API error (status: 400): invalid properties",
+			expectRequest: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested bool
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					requested = true
+					if !strings.HasSuffix(req.URL.Path, "/user_properties") {
+						t.Errorf("expected endpoint '/user_properties', got %s", req.URL.Path)
+					}
+
+					var requestData UserPropertiesRequest
+					if err := json.NewDecoder(req.Body).Decode(&requestData); err != nil {
+						t.Errorf("failed to decode request body: %v", err)
+					}
+					if requestData.UserID != tt.userID {
+						t.Errorf("expected UserID %d, got %d", tt.userID, requestData.UserID)
+					}
+					if requestData.Origin != "Go + Dashgram SDK" {
+						t.Errorf("expected default origin, got '%s'", requestData.Origin)
+					}
+					return tt.mockResponse, nil
+				},
+			}
+
+			options := []Option{WithHTTPClient(mockClient)}
+			if tt.useAsync {
+				options = append(options, WithUseAsync())
+			}
+
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			err := d.SetUserProperties(tt.userID, tt.props)
+
+			if tt.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error '%s', got nil", tt.expectedError)
+				} else if err.Error() != tt.expectedError {
+					t.Errorf("expected error '%s', got '%s'", tt.expectedError, err.Error())
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if requested != tt.expectRequest {
+				t.Errorf("expected request made %v, got %v", tt.expectRequest, requested)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackGroupEvent(t *testing.T) {
+	tests := []struct {
+		name          string
+		groupID       int
+		event         any
+		useAsync      bool
+		mockResponse  *http.Response
+		expectedError string
+		expectRequest bool
+	}{
+		{
+			name:    "successful track group event",
+			groupID: 42,
+			event:   map[string]any{"action": "message"},
+			mockResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			},
+			expectRequest: true,
+		},
+		{
+			name:          "non-positive group ID",
+			groupID:       0,
+			event:         map[string]any{"action": "message"},
+			expectedError: "invalid groupID: must be positive",
+		},
+		{
+			name:          "nil event",
+			groupID:       42,
+			event:         nil,
+			expectedError: "invalid event: must not be nil",
+		},
+		{
+			name:          "validation with async enabled",
+			groupID:       -1,
+			event:         map[string]any{"action": "message"},
+			useAsync:      true,
+			expectedError: "invalid groupID: must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested bool
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					requested = true
+					if !strings.HasSuffix(req.URL.Path, "/group_track") {
+						t.Errorf("expected endpoint '/group_track', got %s", req.URL.Path)
+					}
+
+					body, _ := io.ReadAll(req.Body)
+					expected := `{"group_id":42,"updates":[{"action":"message"}],"origin":"Go + Dashgram SDK"}`
+					if string(body) != expected {
+						t.Errorf("expected body '%s', got '%s'", expected, string(body))
+					}
+					return tt.mockResponse, nil
+				},
+			}
+
+			options := []Option{WithHTTPClient(mockClient)}
+			if tt.useAsync {
+				options = append(options, WithUseAsync())
+			}
+
+			d := New(123, "test-key", options...)
+			defer d.Close()
+
+			err := d.TrackGroupEvent(tt.groupID, tt.event)
+
+			if tt.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error '%s', got nil", tt.expectedError)
+				} else if err.Error() != tt.expectedError {
+					t.Errorf("expected error '%s', got '%s'", tt.expectedError, err.Error())
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if requested != tt.expectRequest {
+				t.Errorf("expected request made %v, got %v", tt.expectRequest, requested)
+			}
+		})
+	}
+}
+
+func TestDashgram_InvitedByWithOptions(t *testing.T) {
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		opts     InviteOptions
+		expected string
+	}{
+		{
+			name:     "zero options",
+			expected: `{"user_id":12345,"invited_by":67890,"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name: "all options",
+			opts: InviteOptions{
+				Timestamp: timestamp,
+				Campaign:  "spring_sale",
+				Payload:   "ref_67890",
+				Extra:     map[string]any{"reward_granted": true},
+			},
+			expected: `{"user_id":12345,"invited_by":67890,"timestamp":"2024-05-01T12:00:00Z","campaign":"spring_sale","payload":"ref_67890","extra":{"reward_granted":true},"origin":"Go + Dashgram SDK"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					body, _ = io.ReadAll(req.Body)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+					}, nil
+				},
+			}
+
+			d := New(123, "test-key", WithHTTPClient(mockClient))
+			defer d.Close()
+
+			if err := d.InvitedByWithOptions(context.Background(), 12345, 67890, tt.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(body) != tt.expected {
+				t.Errorf("expected body '%s', got '%s'", tt.expected, string(body))
+			}
+		})
+	}
+}
+
+func TestDashgram_InvitedByLargeIDs(t *testing.T) {
+	var requestData InvitedByRequest
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&requestData); err != nil {
+				t.Errorf("failed to decode request body: %v", err)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	d := New(123, "test-key", WithHTTPClient(mockClient))
+	defer d.Close()
+
+	// Telegram user IDs may exceed the 32-bit int range
+	var userID, invitedBy int64 = 7123456789, 1<<31 + 1
+	if err := d.InvitedBy(userID, invitedBy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requestData.UserID != userID {
+		t.Errorf("expected UserID %d, got %d", userID, requestData.UserID)
+	}
+	if requestData.InvitedBy != invitedBy {
+		t.Errorf("expected InvitedBy %d, got %d", invitedBy, requestData.InvitedBy)
+	}
+}
+
+func TestDashgram_TrackEventWithResponse(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		body          string
+		expectedError string
+	}{
+		{
+			name:       "successful track event",
+			statusCode: http.StatusOK,
+			body:       `{"status":"success","details":"ok"}`,
+		},
+		{
+			name:          "rate limited",
+			statusCode:    http.StatusTooManyRequests,
+			body:          `{"status":"error","details":"rate limited"}`,
+			expectedError: "dashgram API error (status: 429): rate limited",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					header := make(http.Header)
+					header.Set("X-RateLimit-Remaining", "42")
+					header.Set("X-Request-ID", "req-123")
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Header:     header,
+						Body:       io.NopCloser(strings.NewReader(tt.body)),
+					}, nil
+				},
+			}
+
+			d := New(123, "test-key", WithHTTPClient(mockClient), WithUseAsync())
+			defer d.Close()
+
+			resp, err := d.TrackEventWithResponse(context.Background(), map[string]any{"action": "click"})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if resp == nil {
+				t.Fatal("expected a response, got nil")
+			}
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("expected status code %d, got %d", tt.statusCode, resp.StatusCode)
+			}
+			if got := resp.Headers.Get("X-RateLimit-Remaining"); got != "42" {
+				t.Errorf("expected X-RateLimit-Remaining '42', got '%s'", got)
+			}
+			if got := resp.Headers.Get("X-Request-ID"); got != "req-123" {
+				t.Errorf("expected X-Request-ID 'req-123', got '%s'", got)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackEventJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		jsonBytes     []byte
+		expected      string
+		expectedError string
+	}{
+		{
+			name:      "embeds raw event",
+			jsonBytes: []byte(`{"update_id":1,"message":{"text":"hi"}}`),
+			expected:  `{"updates":[{"update_id":1,"message":{"text":"hi"}}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "invalid JSON",
+			jsonBytes:     []byte(`{"update_id":`),
+			expectedError: "invalid jsonBytes: must be valid JSON",
+		},
+		{
+			name:          "empty input",
+			jsonBytes:     nil,
+			expectedError: "invalid jsonBytes: must be valid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					body, _ = io.ReadAll(req.Body)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+					}, nil
+				},
+			}
+
+			d := New(123, "test-key", WithHTTPClient(mockClient))
+			defer d.Close()
+
+			err := d.TrackEventJSON(tt.jsonBytes)
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedError, err)
+				}
+				if body != nil {
+					t.Errorf("expected no request to be made")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(body) != tt.expected {
+				t.Errorf("expected body '%s', got '%s'", tt.expected, string(body))
+			}
+		})
+	}
+}
+
+func TestMarshalUpdate(t *testing.T) {
+	raw := json.RawMessage(`{"update_id": 1}`)
+	encoded, err := marshalUpdate(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(encoded) != string(raw) {
+		t.Errorf("expected raw JSON to be kept as is, got %s", encoded)
+	}
+	if &encoded[0] == &raw[0] {
+		t.Error("expected raw JSON to be copied")
+	}
+
+	encoded, err = marshalUpdate(map[string]any{"action": "click"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(encoded) != `{"action":"click"}` {
+		t.Errorf("expected {\"action\":\"click\"}, got %s", encoded)
+	}
+
+	var validationErr *ValidationError
+	if _, err := marshalUpdate(map[string]any{"ch": make(chan int)}); !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestDashgram_TrackEventWithUserID(t *testing.T) {
+	tests := []struct {
+		name          string
+		event         map[string]any
+		expected      string
+		expectedError error
+	}{
+		{
+			name:     "injects user ID",
+			event:    map[string]any{"action": "click"},
+			expected: `{"updates":[{"action":"click","user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "argument takes precedence",
+			event:    map[string]any{"action": "click", "user_id": 999},
+			expected: `{"updates":[{"action":"click","user_id":12345}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "nil event",
+			expectedError: ErrNilEvent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					body, _ = io.ReadAll(req.Body)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+					}, nil
+				},
+			}
+
+			d := New(123, "test-key", WithHTTPClient(mockClient))
+			defer d.Close()
+
+			var original map[string]any
+			if tt.event != nil {
+				original = make(map[string]any)
+				for key, value := range tt.event {
+					original[key] = value
+				}
+			}
+
+			err := d.TrackEventWithUserID(12345, tt.event)
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error '%v', got '%v'", tt.expectedError, err)
+				}
+				if body != nil {
+					t.Errorf("expected no request to be made")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(body) != tt.expected {
+				t.Errorf("expected body '%s', got '%s'", tt.expected, string(body))
+			}
+			if len(tt.event) != len(original) || tt.event["user_id"] != original["user_id"] {
+				t.Errorf("expected the caller's event to be left untouched, got %v", tt.event)
+			}
+		})
+	}
+}
+
+func TestDashgram_ValidateCredentials(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockResponse *http.Response
+		mockError    error
+		checkErr     func(error) bool
+	}{
+		{
+			name: "valid credentials",
+			mockResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			},
+			checkErr: func(err error) bool { return err == nil },
+		},
+		{
+			name: "invalid credentials",
+			mockResponse: &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"error","details":"unauthorized"}`)),
+			},
+			checkErr: func(err error) bool {
+				var target *InvalidCredentialsError
+				return errors.As(err, &target)
+			},
+		},
+		{
+			name: "forbidden",
+			mockResponse: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"error","details":"forbidden"}`)),
+			},
+			checkErr: func(err error) bool {
+				var target *ForbiddenError
+				return errors.As(err, &target)
+			},
+		},
+		{
+			name:      "network error",
+			mockError: fmt.Errorf("connection refused"),
+			checkErr: func(err error) bool {
+				var target *TransportError
+				return errors.As(err, &target)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					body, _ = io.ReadAll(req.Body)
+					return tt.mockResponse, tt.mockError
+				},
+			}
+
+			// Credentials are validated synchronously even in async mode
+			d := New(123, "test-key", WithHTTPClient(mockClient), WithUseAsync())
+			defer d.Close()
+
+			err := d.ValidateCredentials(context.Background())
+			if !tt.checkErr(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			expected := `{"updates":[],"origin":"Go + Dashgram SDK"}`
+			if string(body) != expected {
+				t.Errorf("expected body '%s', got '%s'", expected, string(body))
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackEventNil(t *testing.T) {
+	var nilMap map[string]any
+	var nilPointer *struct{ Action string }
+
+	tests := []struct {
+		name     string
+		event    any
+		useAsync bool
+	}{
+		{name: "nil event", event: nil},
+		{name: "nil map", event: nilMap},
+		{name: "nil pointer", event: nilPointer},
+		{name: "nil event with async enabled", event: nil, useAsync: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+
+			options := []Option{WithHTTPClient(helper.MockHTTPClient())}
+			if tt.useAsync {
+				options = append(options, WithUseAsync())
+			}
+
+			d := New(123, "test-key", options...)
+
+			err := d.TrackEvent(tt.event)
+			d.Close()
+
+			if !errors.Is(err, ErrNilEvent) {
+				t.Errorf("expected ErrNilEvent, got %v", err)
+			}
+			if helper.RequestCount != 0 {
+				t.Errorf("expected no HTTP requests, got %d", helper.RequestCount)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackEventValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		event         any
+		useAsync      bool
+		expectedError string
+	}{
+		{name: "empty map", event: map[string]any{}, expectedError: "invalid event: must not be empty"},
+		{name: "empty string map", event: map[string]string{}, expectedError: "invalid event: must not be empty"},
+		{name: "empty map with async enabled", event: map[string]any{}, useAsync: true, expectedError: "invalid event: must not be empty"},
+		{name: "channel with async enabled", event: map[string]any{"ch": make(chan int)}, useAsync: true, expectedError: "invalid event: must be serializable to JSON"},
+		{name: "function with async enabled", event: map[string]any{"fn": func() {}}, useAsync: true, expectedError: "invalid event: must be serializable to JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+
+			options := []Option{WithHTTPClient(helper.MockHTTPClient())}
+			if tt.useAsync {
+				options = append(options, WithUseAsync())
+			}
+
+			d := New(123, "test-key", options...)
+
+			err := d.TrackEvent(tt.event)
+			d.Close()
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if err.Error() != tt.expectedError {
+				t.Errorf("expected error '%s', got '%s'", tt.expectedError, err.Error())
+			}
+			if helper.RequestCount != 0 {
+				t.Errorf("expected no HTTP requests, got %d", helper.RequestCount)
+			}
+			if stats := d.Stats(); stats.Enqueued != 0 {
+				t.Errorf("expected no enqueued tasks, got %d", stats.Enqueued)
+			}
+		})
+	}
+}
+
+func TestDashgram_DeleteUserData(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        int64
+		statusCode    int
+		body          string
+		expectRequest bool
+		checkError    func(error) bool
+	}{
+		{
+			name:          "successful deletion",
+			userID:        12345,
+			statusCode:    http.StatusOK,
+			body:          `{"status":"success","details":"ok"}`,
+			expectRequest: true,
+			checkError:    func(err error) bool { return err == nil },
+		},
+		{
+			name:          "unknown user",
+			userID:        12345,
+			statusCode:    http.StatusNotFound,
+			body:          `{"status":"error","details":"user not found"}`,
+			expectRequest: true,
+			checkError: func(err error) bool {
+				var notFound *UserNotFoundError
+				return errors.As(err, &notFound) && notFound.UserID == 12345
+			},
+		},
+		{
+			name:          "server error",
+			userID:        12345,
+			statusCode:    http.StatusInternalServerError,
+			body:          `{"status":"error","details":"internal error"}`,
+			expectRequest: true,
+			checkError: func(err error) bool {
+				var apiErr *DashgramAPIError
+				return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusInternalServerError
+			},
+		},
+		{
+			name:   "non-positive user ID",
+			userID: 0,
+			checkError: func(err error) bool {
+				var validationErr *ValidationError
+				return errors.As(err, &validationErr)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(tt.statusCode, tt.body)
+
+			// Deletion is synchronous even when async mode is enabled
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithUseAsync())
+			defer d.Close()
+
+			err := d.DeleteUserData(context.Background(), tt.userID)
+			if !tt.checkError(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			requests := helper.RecordedRequests()
+			if !tt.expectRequest {
+				if len(requests) != 0 {
+					t.Errorf("expected no requests, got %d", len(requests))
+				}
+				return
+			}
+			if len(requests) != 1 {
+				t.Fatalf("expected 1 request, got %d", len(requests))
+			}
+			if requests[0].Method != http.MethodDelete {
+				t.Errorf("expected method DELETE, got %s", requests[0].Method)
+			}
+			if requests[0].URL.Path != "/v1/123/users/12345" {
+				t.Errorf("expected path '/v1/123/users/12345', got '%s'", requests[0].URL.Path)
+			}
+		})
+	}
+}
+
+func TestDashgram_AliasUser(t *testing.T) {
+	tests := []struct {
+		name          string
+		anonymousID   string
+		userID        int64
+		expected      string
+		expectedField string
+	}{
+		{
+			name:        "merge",
+			anonymousID: "visitor-5f0c",
+			userID:      12345,
+			expected:    `{"anonymous_id":"visitor-5f0c","user_id":12345,"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:          "empty anonymous ID",
+			userID:        12345,
+			expectedField: "anonymousID",
+		},
+		{
+			name:          "non-positive user ID",
+			anonymousID:   "visitor-5f0c",
+			userID:        0,
+			expectedField: "userID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			err := d.AliasUser(context.Background(), tt.anonymousID, tt.userID)
+
+			if tt.expectedField != "" {
+				assertValidationError(t, err, tt.expectedField, helper)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			last := helper.LastRequest()
+			if !strings.HasSuffix(last.URL.Path, "/alias") {
+				t.Errorf("expected the alias endpoint, got %s", last.URL.Path)
+			}
+			if body := string(last.Body); body != tt.expected {
+				t.Errorf("expected body %s, got %s", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestDashgram_Call(t *testing.T) {
+	type funnel struct {
+		Name  string  `json:"name"`
+		Steps []int64 `json:"steps"`
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		endpoint      string
+		payload       any
+		opts          []CallOption
+		response      *http.Response
+		expectedPath  string
+		expectedBody  string
+		checkError    func(error) bool
+		expectRequest bool
+	}{
+		{
+			name:          "map payload gets the origin",
+			method:        http.MethodPost,
+			endpoint:      "funnels",
+			payload:       map[string]any{"name": "signup"},
+			expectedPath:  "/v1/123/funnels",
+			expectedBody:  `{"name":"signup","origin":"Go + Dashgram SDK"}`,
+			expectRequest: true,
+		},
+		{
+			name:          "call origin",
+			method:        http.MethodPost,
+			endpoint:      "/funnels",
+			payload:       map[string]any{"name": "signup"},
+			opts:          []CallOption{WithCallOrigin("backend")},
+			expectedPath:  "/v1/123/funnels",
+			expectedBody:  `{"name":"signup","origin":"backend"}`,
+			expectRequest: true,
+		},
+		{
+			name:          "struct payload sent as is",
+			method:        http.MethodPut,
+			endpoint:      "funnels/7",
+			payload:       funnel{Name: "signup", Steps: []int64{1, 2}},
+			expectedPath:  "/v1/123/funnels/7",
+			expectedBody:  `{"name":"signup","steps":[1,2]}`,
+			expectRequest: true,
+		},
+		{
+			name:          "no payload",
+			method:        http.MethodGet,
+			endpoint:      "funnels/7",
+			expectedPath:  "/v1/123/funnels/7",
+			expectRequest: true,
+		},
+		{
+			name:     "API errors are mapped",
+			method:   http.MethodGet,
+			endpoint: "funnels/7",
+			response: &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"error","details":"unauthorized"}`)),
+			},
+			checkError: func(err error) bool {
+				var credentialsErr *InvalidCredentialsError
+				return errors.As(err, &credentialsErr)
+			},
+			expectRequest: true,
+		},
+		{
+			name:     "empty method",
+			endpoint: "funnels",
+			checkError: func(err error) bool {
+				var validationErr *ValidationError
+				return errors.As(err, &validationErr) && validationErr.Field == "method"
+			},
+		},
+		{
+			name:   "empty endpoint",
+			method: http.MethodGet,
+			checkError: func(err error) bool {
+				var validationErr *ValidationError
+				return errors.As(err, &validationErr) && validationErr.Field == "endpoint"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			if tt.response != nil {
+				helper.Responses = append(helper.Responses, tt.response)
+			} else {
+				helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok","funnel":{"name":"signup","steps":[1,2]}}`)
+			}
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			var out struct {
+				Funnel funnel `json:"funnel"`
+			}
+			err := d.Call(context.Background(), tt.method, tt.endpoint, tt.payload, &out, tt.opts...)
+
+			if (helper.RequestCount > 0) != tt.expectRequest {
+				t.Errorf("expected request %v, got %d requests", tt.expectRequest, helper.RequestCount)
+			}
+			if tt.checkError != nil {
+				if !tt.checkError(err) {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			last := helper.LastRequest()
+			if last.Method != tt.method {
+				t.Errorf("expected method %s, got %s", tt.method, last.Method)
+			}
+			if last.URL.Path != tt.expectedPath {
+				t.Errorf("expected path %s, got %s", tt.expectedPath, last.URL.Path)
+			}
+			if body := string(last.Body); body != tt.expectedBody {
+				t.Errorf("expected body %s, got %s", tt.expectedBody, body)
+			}
+			if out.Funnel.Name != "signup" || len(out.Funnel.Steps) != 2 {
+				t.Errorf("expected the response to be decoded, got %+v", out)
+			}
+		})
+	}
+}
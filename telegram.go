@@ -0,0 +1,43 @@
+package dashgram
+
+// SuccessfulPayment mirrors the Telegram Bot API SuccessfulPayment object, so
+// it can be decoded straight from an update or converted from a bot library type
+type SuccessfulPayment struct {
+	Currency                string `json:"currency"`
+	TotalAmount             int64  `json:"total_amount"`
+	InvoicePayload          string `json:"invoice_payload"`
+	ShippingOptionID        string `json:"shipping_option_id,omitempty"`
+	TelegramPaymentChargeID string `json:"telegram_payment_charge_id"`
+	ProviderPaymentChargeID string `json:"provider_payment_charge_id"`
+	IsRecurring             bool   `json:"is_recurring,omitempty"`
+	IsFirstRecurring        bool   `json:"is_first_recurring,omitempty"`
+	SubscriptionExpiration  int64  `json:"subscription_expiration_date,omitempty"`
+}
+
+// User mirrors the Telegram Bot API User object
+type User struct {
+	ID           int64  `json:"id"`
+	IsBot        bool   `json:"is_bot"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name,omitempty"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
+}
+
+// CallbackMessage mirrors the fields common to the Telegram Bot API Message
+// and InaccessibleMessage objects a callback query can come from
+type CallbackMessage struct {
+	MessageID int64 `json:"message_id"`
+	Date      int64 `json:"date"`
+}
+
+// CallbackQuery mirrors the Telegram Bot API CallbackQuery object, so it can
+// be decoded straight from an update or converted from a bot library type
+type CallbackQuery struct {
+	ID              string           `json:"id"`
+	From            User             `json:"from"`
+	Message         *CallbackMessage `json:"message,omitempty"`
+	InlineMessageID string           `json:"inline_message_id,omitempty"`
+	ChatInstance    string           `json:"chat_instance"`
+	Data            string           `json:"data,omitempty"`
+}
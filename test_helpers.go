@@ -1,9 +1,12 @@
 package dashgram
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -18,12 +21,22 @@ func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	return m.doFunc(req)
 }
 
+// RecordedRequest is a copy of a request made through the mock HTTP client
+type RecordedRequest struct {
+	Method    string
+	URL       *url.URL
+	Headers   http.Header
+	Body      []byte
+	Timestamp time.Time
+}
+
 // TestHelper provides common test utilities
 type TestHelper struct {
 	RequestCount int
 	mu           sync.Mutex
 	Responses    []*http.Response
 	Errors       []error
+	recorded     []RecordedRequest
 }
 
 // NewTestHelper creates a new test helper instance
@@ -38,8 +51,11 @@ func NewTestHelper() *TestHelper {
 func (th *TestHelper) MockHTTPClient() *mockHTTPClient {
 	return &mockHTTPClient{
 		doFunc: func(req *http.Request) (*http.Response, error) {
+			recorded := recordRequest(req)
+
 			th.mu.Lock()
 			th.RequestCount++
+			th.recorded = append(th.recorded, recorded)
 			responseIndex := th.RequestCount - 1
 			th.mu.Unlock()
 
@@ -58,6 +74,81 @@ func (th *TestHelper) MockHTTPClient() *mockHTTPClient {
 	}
 }
 
+// recordRequest deep-copies a request, restoring its body for the caller
+func recordRequest(req *http.Request) RecordedRequest {
+	recorded := RecordedRequest{
+		Method:    req.Method,
+		Headers:   req.Header.Clone(),
+		Timestamp: time.Now(),
+	}
+
+	if req.URL != nil {
+		u := *req.URL
+		recorded.URL = &u
+	}
+
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		recorded.Body = body
+	}
+
+	return recorded
+}
+
+// RecordedRequests returns copies of all requests made through the mock client, in order
+func (th *TestHelper) RecordedRequests() []RecordedRequest {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	recorded := make([]RecordedRequest, len(th.recorded))
+	copy(recorded, th.recorded)
+	return recorded
+}
+
+// RecordedCall is a request made through the mock HTTP client, with its JSON
+// body decoded so tests can compare it to expected values
+type RecordedCall struct {
+	Method string
+	Path   string
+	// Body is the decoded JSON body, nil if the request had none or it
+	// wasn't JSON
+	Body any
+}
+
+// RecordedCalls returns the requests made through the mock client, in order
+func (th *TestHelper) RecordedCalls() []RecordedCall {
+	recorded := th.RecordedRequests()
+
+	calls := make([]RecordedCall, len(recorded))
+	for i, req := range recorded {
+		calls[i].Method = req.Method
+		if req.URL != nil {
+			calls[i].Path = req.URL.Path
+		}
+		if len(req.Body) > 0 {
+			var body any
+			if err := json.Unmarshal(req.Body, &body); err == nil {
+				calls[i].Body = body
+			}
+		}
+	}
+	return calls
+}
+
+// LastRequest returns the last request made through the mock client, or nil if there was none
+func (th *TestHelper) LastRequest() *RecordedRequest {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	if len(th.recorded) == 0 {
+		return nil
+	}
+	last := th.recorded[len(th.recorded)-1]
+	return &last
+}
+
 // AddResponse adds a response to the mock client
 func (th *TestHelper) AddResponse(statusCode int, body string) {
 	th.Responses = append(th.Responses, &http.Response{
@@ -78,6 +169,7 @@ func (th *TestHelper) Reset() {
 	th.RequestCount = 0
 	th.Responses = make([]*http.Response, 0)
 	th.Errors = make([]error, 0)
+	th.recorded = nil
 }
 
 // WaitForRequests waits for a specified number of requests to be made
@@ -120,8 +212,8 @@ var TestEventData = map[string]any{
 
 // TestUserData provides common test user data
 var TestUserData = struct {
-	UserID    int
-	InvitedBy int
+	UserID    int64
+	InvitedBy int64
 }{
 	UserID:    12345,
 	InvitedBy: 67890,
@@ -0,0 +1,69 @@
+package dashgram
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// assertValidationError checks that err is a ValidationError on field and
+// that no request was made
+func assertValidationError(t *testing.T, err error, field string, helper *TestHelper) {
+	t.Helper()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != field {
+		t.Fatalf("expected ValidationError on %s, got %v", field, err)
+	}
+	if helper.RequestCount != 0 {
+		t.Errorf("expected no request, got %d", helper.RequestCount)
+	}
+}
+
+func TestTestHelper_RecordedCalls(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+	defer d.Close()
+
+	if calls := helper.RecordedCalls(); len(calls) != 0 {
+		t.Fatalf("expected no calls, got %d", len(calls))
+	}
+
+	if err := d.TrackEvent(map[string]any{"action": "open"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.InvitedBy(1, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []RecordedCall{
+		{
+			Method: http.MethodPost,
+			Path:   "/v1/123/track",
+			Body: map[string]any{
+				"updates": []any{map[string]any{"action": "open"}},
+				"origin":  "Go + Dashgram SDK",
+			},
+		},
+		{
+			Method: http.MethodPost,
+			Path:   "/v1/123/invited_by",
+			Body: map[string]any{
+				"user_id":    float64(1),
+				"invited_by": float64(2),
+				"origin":     "Go + Dashgram SDK",
+			},
+		},
+	}
+	if calls := helper.RecordedCalls(); !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %+v, got %+v", expected, calls)
+	}
+
+	helper.Reset()
+	if calls := helper.RecordedCalls(); len(calls) != 0 {
+		t.Errorf("expected no calls after Reset, got %d", len(calls))
+	}
+}
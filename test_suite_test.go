@@ -2,6 +2,7 @@ package dashgram
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -42,8 +43,26 @@ func testIntegration(t *testing.T) {
 	}
 
 	// Verify requests were made
-	if helper.RequestCount != 2 {
-		t.Errorf("expected 2 requests, got %d", helper.RequestCount)
+	recorded := helper.RecordedRequests()
+	if len(recorded) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(recorded))
+	}
+
+	for i, endpoint := range []string{"/123/track", "/123/invited_by"} {
+		if recorded[i].Method != http.MethodPost {
+			t.Errorf("expected POST method, got %s", recorded[i].Method)
+		}
+		if !strings.HasSuffix(recorded[i].URL.Path, endpoint) {
+			t.Errorf("expected request %d to '%s', got '%s'", i, endpoint, recorded[i].URL.Path)
+		}
+		if recorded[i].Headers.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("expected Authorization header 'Bearer test-key', got '%s'", recorded[i].Headers.Get("Authorization"))
+		}
+	}
+
+	expected := `{"user_id":12345,"invited_by":67890,"origin":"Go + Dashgram SDK"}`
+	if last := helper.LastRequest(); string(last.Body) != expected {
+		t.Errorf("expected last request body '%s', got '%s'", expected, string(last.Body))
 	}
 }
 
@@ -77,19 +96,31 @@ func testConcurrency(t *testing.T) {
 }
 
 func testErrorHandling(t *testing.T) {
-	// Test forbidden error
+	// Test unauthorized error
 	helper := NewTestHelper()
-	helper.AddResponse(403, `{"status":"error","details":"forbidden"}`)
+	helper.AddResponse(401, `{"status":"error","details":"unauthorized"}`)
 
 	client := CreateTestClient(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
 	defer client.Close()
 
 	err := client.TrackEvent(TestEventData)
+	if err == nil {
+		t.Errorf("expected error for unauthorized response")
+	}
+	if _, ok := err.(*InvalidCredentialsError); !ok {
+		t.Errorf("expected InvalidCredentialsError, got %T", err)
+	}
+
+	// Test forbidden error
+	helper.Reset()
+	helper.AddResponse(403, `{"status":"error","details":"forbidden"}`)
+
+	err = client.TrackEvent(TestEventData)
 	if err == nil {
 		t.Errorf("expected error for forbidden response")
 	}
-	if !strings.Contains(err.Error(), "invalid credentials") {
-		t.Errorf("expected error containing 'invalid credentials', got '%s'", err.Error())
+	if _, ok := err.(*ForbiddenError); !ok {
+		t.Errorf("expected ForbiddenError, got %T", err)
 	}
 
 	// Test API error
@@ -119,6 +150,7 @@ func testErrorHandling(t *testing.T) {
 
 // Benchmark tests
 func BenchmarkTrackEvent(b *testing.B) {
+	b.ReportAllocs()
 	helper := NewTestHelper()
 	// Add responses for all benchmark iterations
 	for i := 0; i < b.N; i++ {
@@ -0,0 +1,143 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// maxErrorStackFrames is the number of stack frames recorded by TrackError
+	maxErrorStackFrames = 16
+	// maxErrorChainTypes is the number of wrapped errors whose type TrackError records
+	maxErrorChainTypes = 16
+)
+
+// TrackError tracks an application error as an event, e.g. to chart error
+// rates next to usage. The event holds the error message, the types of the
+// errors in its chain, outermost first, and the stack of the caller, trimmed
+// to 16 frames with file paths reduced to their base name:
+//
+//	{"action": "error", "user_id": 42, "properties": {"error": "open config.json: no such file", "error_types": ["*fmt.wrapError", "*fs.PathError"], "stack": ["main.load (config.go:12)", ...], "tags": {"handler": "start"}}}
+//
+// userID is 0 for errors that aren't about a user, and the user ID is then
+// omitted. Errors are sampled like other events, see WithSampleRate, so a
+// crash loop can't flood the project. A nil err tracks nothing and returns nil.
+func (d *Dashgram) TrackError(ctx context.Context, userID int64, err error, tags map[string]string) error {
+	if err == nil {
+		return nil
+	}
+	// Skip runtime.Callers, stackTrace and TrackError
+	event, validationErr := errorEvent(userID, err, tags, stackTrace(3))
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return d.TrackEventWithContext(ctx, event)
+}
+
+// TrackErrorAsyncWithContext validates the arguments and enqueues the error to be tracked asynchronously
+func (d *Dashgram) TrackErrorAsyncWithContext(ctx context.Context, userID int64, err error, tags map[string]string) error {
+	if err == nil {
+		return nil
+	}
+	return d.trackErrorAsync(ctx, userID, err, tags, stackTrace(3))
+}
+
+func (d *Dashgram) TrackErrorAsync(userID int64, err error, tags map[string]string) error {
+	if err == nil {
+		return nil
+	}
+	// The stack is taken here, so it starts at the caller rather than at this wrapper
+	return d.trackErrorAsync(context.Background(), userID, err, tags, stackTrace(3))
+}
+
+// trackErrorAsync enqueues the error with the stack of the caller of TrackErrorAsync
+func (d *Dashgram) trackErrorAsync(ctx context.Context, userID int64, err error, tags map[string]string, stack []string) error {
+	event, validationErr := errorEvent(userID, err, tags, stack)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return d.TrackEventAsyncWithContext(ctx, event)
+}
+
+// errorEvent validates the arguments of TrackError and returns the event to track
+func errorEvent(userID int64, err error, tags map[string]string, stack []string) (map[string]any, error) {
+	if userID < 0 {
+		return nil, &ValidationError{Field: "userID", Message: "must not be negative"}
+	}
+
+	properties := map[string]any{
+		"error":       err.Error(),
+		"error_types": errorChainTypes(err),
+	}
+	if len(stack) > 0 {
+		properties["stack"] = stack
+	}
+	if len(tags) > 0 {
+		properties["tags"] = tags
+	}
+
+	event := map[string]any{
+		"action":     "error",
+		"properties": properties,
+	}
+	if userID != 0 {
+		event["user_id"] = userID
+	}
+	return event, nil
+}
+
+// errorChainTypes returns the types of the errors wrapped by err, depth first,
+// following both Unwrap() error and Unwrap() []error
+func errorChainTypes(err error) []string {
+	var types []string
+	pending := []error{err}
+	for len(pending) > 0 && len(types) < maxErrorChainTypes {
+		err := pending[0]
+		pending = pending[1:]
+		if err == nil {
+			continue
+		}
+		types = append(types, fmt.Sprintf("%T", err))
+
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			pending = append(append([]error{}, e.Unwrap()...), pending...)
+		default:
+			if wrapped := errors.Unwrap(err); wrapped != nil {
+				pending = append([]error{wrapped}, pending...)
+			}
+		}
+	}
+	return types
+}
+
+// stackTrace returns the stack of the calling goroutine as "function
+// (file:line)" frames, skipping the given number of frames as runtime.Callers
+// does and the frames of the runtime, such as runtime.gopanic
+func stackTrace(skip int) []string {
+	// Leave room for the runtime frames that are skipped
+	pcs := make([]uintptr, maxErrorStackFrames+8)
+	n := runtime.Callers(skip, pcs)
+	if n == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(pcs[:n])
+
+	stack := make([]string, 0, maxErrorStackFrames)
+	for len(stack) < maxErrorStackFrames {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			stack = append(stack, fmt.Sprintf("%s (%s:%d)", frame.Function, filepath.Base(frame.File), frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
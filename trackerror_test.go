@@ -0,0 +1,168 @@
+package dashgram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestErrorChainTypes(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "config.json", Err: fs.ErrNotExist}
+	tests := []struct {
+		name     string
+		err      error
+		expected []string
+	}{
+		{name: "plain", err: errors.New("boom"), expected: []string{"*errors.errorString"}},
+		{name: "wrapped", err: fmt.Errorf("load: %w", pathErr), expected: []string{"*fmt.wrapError", "*fs.PathError", "*errors.errorString"}},
+		{name: "joined", err: errors.Join(pathErr, &ValidationError{Field: "userID"}), expected: []string{"*errors.joinError", "*fs.PathError", "*errors.errorString", "*dashgram.ValidationError"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if types := errorChainTypes(tt.err); !reflect.DeepEqual(types, tt.expected) {
+				t.Errorf("expected types %v, got %v", tt.expected, types)
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackError(t *testing.T) {
+	tests := []struct {
+		name            string
+		userID          int64
+		err             error
+		tags            map[string]string
+		options         []Option
+		expectedRequest bool
+		expectedField   string
+	}{
+		{
+			name:            "user error",
+			userID:          12345,
+			err:             fmt.Errorf("load: %w", fs.ErrNotExist),
+			tags:            map[string]string{"handler": "start"},
+			expectedRequest: true,
+		},
+		{
+			name:            "background error",
+			err:             errors.New("boom"),
+			expectedRequest: true,
+		},
+		{
+			name: "nil error",
+		},
+		{
+			name:    "sampled out",
+			userID:  12345,
+			err:     errors.New("boom"),
+			options: []Option{WithSampleRate(0)},
+		},
+		{
+			name:          "negative user",
+			userID:        -1,
+			err:           errors.New("boom"),
+			expectedField: "userID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", append([]Option{WithHTTPClient(helper.MockHTTPClient())}, tt.options...)...)
+			defer d.Close()
+
+			err := d.TrackError(context.Background(), tt.userID, tt.err, tt.tags)
+
+			if tt.expectedField != "" {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != tt.expectedField {
+					t.Fatalf("expected ValidationError on %s, got %v", tt.expectedField, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.expectedRequest {
+				if helper.RequestCount != 0 {
+					t.Errorf("expected no request, got %d", helper.RequestCount)
+				}
+				return
+			}
+
+			var body struct {
+				Updates []struct {
+					Action     string `json:"action"`
+					UserID     int64  `json:"user_id"`
+					Properties struct {
+						Error      string            `json:"error"`
+						ErrorTypes []string          `json:"error_types"`
+						Stack      []string          `json:"stack"`
+						Tags       map[string]string `json:"tags"`
+					} `json:"properties"`
+				} `json:"updates"`
+			}
+			if err := json.Unmarshal(helper.LastRequest().Body, &body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			update := body.Updates[0]
+			if update.Action != "error" || update.UserID != tt.userID {
+				t.Errorf("expected error event of user %d, got %s of user %d", tt.userID, update.Action, update.UserID)
+			}
+			if update.Properties.Error != tt.err.Error() {
+				t.Errorf("expected error %q, got %q", tt.err.Error(), update.Properties.Error)
+			}
+			if expected := errorChainTypes(tt.err); !reflect.DeepEqual(update.Properties.ErrorTypes, expected) {
+				t.Errorf("expected error types %v, got %v", expected, update.Properties.ErrorTypes)
+			}
+			if !reflect.DeepEqual(update.Properties.Tags, tt.tags) {
+				t.Errorf("expected tags %v, got %v", tt.tags, update.Properties.Tags)
+			}
+			if len(update.Properties.Stack) == 0 || !strings.Contains(update.Properties.Stack[0], "TestDashgram_TrackError") {
+				t.Errorf("expected the stack to start at the caller, got %v", update.Properties.Stack)
+			}
+			if len(update.Properties.Stack) > maxErrorStackFrames {
+				t.Errorf("expected at most %d frames, got %d", maxErrorStackFrames, len(update.Properties.Stack))
+			}
+		})
+	}
+}
+
+func TestDashgram_TrackErrorAsync(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+
+	if err := d.TrackErrorAsync(12345, errors.New("boom"), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.TrackErrorAsync(12345, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Close()
+
+	if helper.RequestCount != 1 {
+		t.Errorf("expected 1 request, got %d", helper.RequestCount)
+	}
+	var body struct {
+		Updates []struct {
+			Properties struct {
+				Stack []string `json:"stack"`
+			} `json:"properties"`
+		} `json:"updates"`
+	}
+	if err := json.Unmarshal(helper.LastRequest().Body, &body); err != nil {
+		t.Fatalf("failed to decode request: %v", err)
+	}
+	if stack := body.Updates[0].Properties.Stack; len(stack) == 0 || !strings.Contains(stack[0], "TestDashgram_TrackErrorAsync") {
+		t.Errorf("expected the stack to start at the caller, got %v", stack)
+	}
+}
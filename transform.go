@@ -0,0 +1,210 @@
+package dashgram
+
+import (
+	"reflect"
+	"time"
+)
+
+// EventFormat is the shape of the updates sent to the track endpoint
+type EventFormat int
+
+const (
+	// EventFormatNative sends events as they are
+	EventFormatNative EventFormat = iota
+	// EventFormatProperties nests each event under a "properties" key next to
+	// "type" and "event" fields, as expected by Segment/Amplitude-style tooling:
+	//
+	//	{"type": "track", "event": "<action>", "properties": {...}}
+	//
+	// The event name is taken from the "event" or "action" field of map events
+	// and defaults to "update".
+	EventFormatProperties
+)
+
+// WithEventFormat sets the shape of the updates sent to the track endpoint
+func WithEventFormat(format EventFormat) Option {
+	return func(d *Dashgram) {
+		d.eventFormat = format
+	}
+}
+
+// TimeEncoding is how time.Time values in map events are encoded
+type TimeEncoding int
+
+const (
+	// TimeEncodingRFC3339 encodes times as RFC 3339 strings, like encoding/json
+	TimeEncodingRFC3339 TimeEncoding = iota
+	// TimeEncodingUnixSeconds encodes times as unix seconds
+	TimeEncodingUnixSeconds
+	// TimeEncodingUnixMillis encodes times as unix milliseconds
+	TimeEncodingUnixMillis
+)
+
+// WithTimeEncoding sets how time.Time values in map events, including nested
+// maps and slices, are encoded. Other events, such as structs, are sent as is.
+func WithTimeEncoding(encoding TimeEncoding) Option {
+	return func(d *Dashgram) {
+		d.timeEncoding = encoding
+	}
+}
+
+// WithJSONOmitEmpty strips the fields of map events, including nested maps,
+// whose values are nil, empty strings, numeric zeros, empty slices or empty maps
+func WithJSONOmitEmpty() Option {
+	return func(d *Dashgram) {
+		d.omitEmpty = true
+	}
+}
+
+// WithEventTimestamps adds an "event_time" field, in unix milliseconds, to every
+// update, captured when the tracking method is called rather than when an
+// async worker sends it. Map events get the field unless they already have
+// one; other events, such as structs or raw JSON, are left untouched and
+// wrapped in an envelope instead:
+//
+//	{"event": <event>, "event_time": 1714564800000}
+func WithEventTimestamps() Option {
+	return func(d *Dashgram) {
+		d.eventTimestamps = true
+	}
+}
+
+// prepareUpdate turns an event passed to a tracking method into the update sent to the API
+func (d *Dashgram) prepareUpdate(event any) any {
+	if d.timeEncoding != TimeEncodingRFC3339 {
+		event = encodeTimes(event, d.timeEncoding)
+	}
+	if d.omitEmpty {
+		event = stripEmpty(event)
+	}
+
+	var update any
+	switch d.eventFormat {
+	case EventFormatProperties:
+		update = map[string]any{
+			"type":       "track",
+			"event":      eventName(event),
+			"properties": event,
+		}
+	default:
+		update = event
+	}
+
+	if d.eventTimestamps {
+		update = withEventTime(update, d.now())
+	}
+	return update
+}
+
+// encodeTimes returns a copy of a map or slice event with its time.Time values
+// encoded as unix timestamps, other values are returned as is
+func encodeTimes(value any, encoding TimeEncoding) any {
+	switch v := value.(type) {
+	case time.Time:
+		if encoding == TimeEncodingUnixMillis {
+			return v.UnixMilli()
+		}
+		return v.Unix()
+	case *time.Time:
+		if v == nil {
+			return v
+		}
+		return encodeTimes(*v, encoding)
+	case map[string]any:
+		encoded := make(map[string]any, len(v))
+		for key, item := range v {
+			encoded[key] = encodeTimes(item, encoding)
+		}
+		return encoded
+	case []any:
+		encoded := make([]any, len(v))
+		for i, item := range v {
+			encoded[i] = encodeTimes(item, encoding)
+		}
+		return encoded
+	default:
+		return value
+	}
+}
+
+// stripEmpty returns a copy of a map or slice event without its empty map
+// fields, nested maps are stripped first
+func stripEmpty(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		stripped := make(map[string]any, len(v))
+		for key, item := range v {
+			item = stripEmpty(item)
+			if !isEmptyValue(item) {
+				stripped[key] = item
+			}
+		}
+		return stripped
+	case []any:
+		stripped := make([]any, len(v))
+		for i, item := range v {
+			stripped[i] = stripEmpty(item)
+		}
+		return stripped
+	default:
+		return value
+	}
+}
+
+// isEmptyValue reports whether a field is nil, an empty string, a numeric
+// zero, or an empty slice or map
+func isEmptyValue(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return v.IsZero()
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// withEventTime sets the "event_time" of a map update, or wraps other updates in an envelope
+func withEventTime(update any, now time.Time) any {
+	eventTime := now.UnixMilli()
+
+	m, ok := update.(map[string]any)
+	if !ok {
+		return map[string]any{
+			"event":      update,
+			"event_time": eventTime,
+		}
+	}
+	if _, ok := m["event_time"]; ok {
+		return m
+	}
+
+	// Copy the map so the caller's event isn't mutated
+	clone := make(map[string]any, len(m)+1)
+	for key, value := range m {
+		clone[key] = value
+	}
+	clone["event_time"] = eventTime
+	return clone
+}
+
+// eventName returns the name of a map event, or "update" for other events
+func eventName(event any) string {
+	if m, ok := event.(map[string]any); ok {
+		for _, key := range []string{"event", "action"} {
+			if name, ok := m[key].(string); ok && name != "" {
+				return name
+			}
+		}
+	}
+	return "update"
+}
@@ -0,0 +1,270 @@
+package dashgram
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WithEventFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   EventFormat
+		event    any
+		expected string
+	}{
+		{
+			name:     "native format",
+			format:   EventFormatNative,
+			event:    map[string]any{"action": "click", "page": "home"},
+			expected: `{"updates":[{"action":"click","page":"home"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "properties format with action",
+			format:   EventFormatProperties,
+			event:    map[string]any{"action": "click", "page": "home"},
+			expected: `{"updates":[{"event":"click","properties":{"action":"click","page":"home"},"type":"track"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "properties format with event name",
+			format:   EventFormatProperties,
+			event:    map[string]any{"event": "signup", "action": "click"},
+			expected: `{"updates":[{"event":"signup","properties":{"action":"click","event":"signup"},"type":"track"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "properties format with raw update",
+			format:   EventFormatProperties,
+			event:    json.RawMessage(`{"update_id":1}`),
+			expected: `{"updates":[{"event":"update","properties":{"update_id":1},"type":"track"}],"origin":"Go + Dashgram SDK"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					body, _ = io.ReadAll(req.Body)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+					}, nil
+				},
+			}
+
+			d := New(123, "test-key", WithHTTPClient(mockClient), WithEventFormat(tt.format))
+			defer d.Close()
+
+			if err := d.TrackEvent(tt.event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(body) != tt.expected {
+				t.Errorf("expected body '%s', got '%s'", tt.expected, string(body))
+			}
+		})
+	}
+}
+
+func TestWithEventTime(t *testing.T) {
+	now := time.UnixMilli(1714564800000)
+
+	tests := []struct {
+		name     string
+		update   any
+		expected string
+	}{
+		{
+			name:     "map event",
+			update:   map[string]any{"action": "click"},
+			expected: `{"action":"click","event_time":1714564800000}`,
+		},
+		{
+			name:     "map event with event time",
+			update:   map[string]any{"action": "click", "event_time": 42},
+			expected: `{"action":"click","event_time":42}`,
+		},
+		{
+			name:     "struct event",
+			update:   struct{ Action string }{Action: "click"},
+			expected: `{"event":{"Action":"click"},"event_time":1714564800000}`,
+		},
+		{
+			name:     "raw event",
+			update:   json.RawMessage(`{"update_id":1}`),
+			expected: `{"event":{"update_id":1},"event_time":1714564800000}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(withEventTime(tt.update, now))
+			if err != nil {
+				t.Fatalf("failed to marshal update: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("expected update '%s', got '%s'", tt.expected, string(data))
+			}
+		})
+	}
+}
+
+func TestDashgram_WithEventTimestamps(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	// Hold the worker so the event sits in the queue before it's sent
+	release := make(chan struct{})
+	client := &mockHTTPClient{doFunc: func(req *http.Request) (*http.Response, error) {
+		<-release
+		return helper.MockHTTPClient().Do(req)
+	}}
+
+	d := New(123, "test-key", WithHTTPClient(client), WithEventTimestamps())
+
+	event := map[string]any{"action": "click"}
+	before := time.Now().UnixMilli()
+	if err := d.TrackEventAsync(event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after := time.Now().UnixMilli()
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	if !helper.WaitForRequests(1, time.Second) {
+		t.Fatal("expected request to be made, but none was")
+	}
+	d.Close()
+
+	if _, ok := event["event_time"]; ok {
+		t.Errorf("expected the caller's event not to be mutated")
+	}
+
+	var body struct {
+		Updates []map[string]any `json:"updates"`
+	}
+	if err := json.Unmarshal(helper.LastRequest().Body, &body); err != nil {
+		t.Fatalf("failed to unmarshal request body: %v", err)
+	}
+	eventTime := int64(body.Updates[0]["event_time"].(float64))
+	if eventTime < before || eventTime > after {
+		t.Errorf("expected event_time between %d and %d, got %d", before, after, eventTime)
+	}
+}
+
+func TestDashgram_WithTimeEncoding(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		encoding TimeEncoding
+		expected string
+	}{
+		{
+			name:     "RFC 3339",
+			encoding: TimeEncodingRFC3339,
+			expected: `{"updates":[{"at":"2024-05-01T12:00:00Z","items":[{"at":"2024-05-01T12:00:00Z"}],"meta":{"at":"2024-05-01T12:00:00Z"},"ptr":"2024-05-01T12:00:00Z"}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "unix seconds",
+			encoding: TimeEncodingUnixSeconds,
+			expected: `{"updates":[{"at":1714564800,"items":[{"at":1714564800}],"meta":{"at":1714564800},"ptr":1714564800}],"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:     "unix milliseconds",
+			encoding: TimeEncodingUnixMillis,
+			expected: `{"updates":[{"at":1714564800000,"items":[{"at":1714564800000}],"meta":{"at":1714564800000},"ptr":1714564800000}],"origin":"Go + Dashgram SDK"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithTimeEncoding(tt.encoding))
+			defer d.Close()
+
+			event := map[string]any{
+				"at":    at,
+				"ptr":   &at,
+				"meta":  map[string]any{"at": at},
+				"items": []any{map[string]any{"at": at}},
+			}
+			if err := d.TrackEvent(event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if body := string(helper.LastRequest().Body); body != tt.expected {
+				t.Errorf("expected body '%s', got '%s'", tt.expected, body)
+			}
+			if _, ok := event["at"].(time.Time); !ok {
+				t.Errorf("expected the caller's event to be left untouched")
+			}
+		})
+	}
+}
+
+func TestDashgram_WithJSONOmitEmpty(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithJSONOmitEmpty())
+	defer d.Close()
+
+	var nilPointer *int
+	event := map[string]any{
+		// Fields kept
+		"action":   "purchase",
+		"amount":   499,
+		"price":    4.99,
+		"currency": "USD",
+		"paid":     false,
+		"items":    []string{"premium"},
+		"meta":     map[string]any{"source": "bot", "empty": ""},
+		"count":    int64(1),
+		"rate":     float32(0.5),
+		"tags":     []any{"new"},
+		// Fields stripped
+		"coupon":   "",
+		"discount": 0,
+		"tax":      0.0,
+		"refunds":  []string{},
+		"extra":    map[string]any{},
+		"note":     nil,
+		"ref":      nilPointer,
+		"retries":  uint(0),
+		"nested":   map[string]any{"empty": "", "zero": 0},
+		"history":  []any{},
+	}
+
+	if err := d.TrackEvent(event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var body struct {
+		Updates []map[string]any `json:"updates"`
+	}
+	if err := json.Unmarshal(helper.LastRequest().Body, &body); err != nil {
+		t.Fatalf("failed to unmarshal request body: %v", err)
+	}
+
+	update := body.Updates[0]
+	if len(update) != 10 {
+		t.Errorf("expected 10 fields, got %d: %v", len(update), update)
+	}
+	for _, key := range []string{"coupon", "discount", "tax", "refunds", "extra", "note", "ref", "retries", "nested", "history"} {
+		if _, ok := update[key]; ok {
+			t.Errorf("expected field '%s' to be stripped", key)
+		}
+	}
+	if meta := update["meta"].(map[string]any); len(meta) != 1 {
+		t.Errorf("expected nested empty fields to be stripped, got %v", meta)
+	}
+	if len(event) != 20 {
+		t.Errorf("expected the caller's event to be left untouched")
+	}
+}
@@ -1,12 +1,102 @@
 package dashgram
 
+import (
+	"net/http"
+	"time"
+)
+
 type TrackEventRequest struct {
 	Updates []any  `json:"updates"`
 	Origin  string `json:"origin,omitempty"`
 }
 
 type InvitedByRequest struct {
-	UserID    int    `json:"user_id"`
-	InvitedBy int    `json:"invited_by"`
+	UserID    int64          `json:"user_id"`
+	InvitedBy int64          `json:"invited_by"`
+	Timestamp *time.Time     `json:"timestamp,omitempty"`
+	Campaign  string         `json:"campaign,omitempty"`
+	Payload   string         `json:"payload,omitempty"`
+	Extra     map[string]any `json:"extra,omitempty"`
+	Origin    string         `json:"origin,omitempty"`
+}
+
+// InvitedByRequestT is an InvitedByRequest whose user IDs are of type T, sent
+// as JSON numbers or strings, see InvitedByT
+type InvitedByRequestT[T UserID] struct {
+	UserID    T      `json:"user_id"`
+	InvitedBy T      `json:"invited_by"`
 	Origin    string `json:"origin,omitempty"`
 }
+
+// InviteOptions carries optional referral attributes for InvitedByWithOptions
+type InviteOptions struct {
+	// Timestamp is when the referral happened, the server time is used if zero
+	Timestamp time.Time
+	// Campaign is the campaign that brought the user
+	Campaign string
+	// Payload is the start payload that carried the referral
+	Payload string
+	// Extra holds arbitrary additional attributes, e.g. whether a reward was granted
+	Extra map[string]any
+}
+
+// InvitedByBulkRequest imports many invitations at once
+type InvitedByBulkRequest struct {
+	Invites []InvitePair `json:"invites"`
+	Origin  string       `json:"origin,omitempty"`
+}
+
+// HistoricalUpdate is an event imported by ImportEvents with the time it occurred
+type HistoricalUpdate struct {
+	Update     any       `json:"update"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ImportEventsRequest imports historical events, keeping their timestamps
+type ImportEventsRequest struct {
+	Events []HistoricalUpdate `json:"events"`
+	Origin string             `json:"origin,omitempty"`
+}
+
+// GroupEventRequest tracks events attributed to a group rather than a user
+type GroupEventRequest struct {
+	GroupID int    `json:"group_id"`
+	Updates []any  `json:"updates"`
+	Origin  string `json:"origin,omitempty"`
+}
+
+type UserPropertiesRequest struct {
+	UserID     int64          `json:"user_id"`
+	Properties map[string]any `json:"properties"`
+	Origin     string         `json:"origin,omitempty"`
+}
+
+// AliasRequest merges an anonymous identity into a Telegram user
+type AliasRequest struct {
+	AnonymousID string `json:"anonymous_id"`
+	UserID      int64  `json:"user_id"`
+	Origin      string `json:"origin,omitempty"`
+}
+
+// SegmentRequest adds a user to a segment, or removes them from it
+type SegmentRequest struct {
+	UserID  int64  `json:"user_id"`
+	Segment string `json:"segment"`
+	Origin  string `json:"origin,omitempty"`
+}
+
+// SegmentBulkRequest adds many users to a segment at once
+type SegmentBulkRequest struct {
+	Segment string  `json:"segment"`
+	UserIDs []int64 `json:"user_ids"`
+	Origin  string  `json:"origin,omitempty"`
+}
+
+// Response describes the API response to a request
+type Response struct {
+	StatusCode int
+	Status     string
+	Details    string
+	// Headers holds the response headers, e.g. X-RateLimit-Remaining or X-Request-ID
+	Headers http.Header
+}
@@ -3,6 +3,7 @@ package dashgram
 import (
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestTrackEventRequest(t *testing.T) {
@@ -100,6 +101,14 @@ func TestInvitedByRequest(t *testing.T) {
 			},
 			expected: `{"user_id":999999999,"invited_by":888888888,"origin":"Large Scale App"}`,
 		},
+		{
+			name: "invited by request with IDs above 2^31",
+			request: InvitedByRequest{
+				UserID:    7123456789,
+				InvitedBy: 1 << 40,
+			},
+			expected: `{"user_id":7123456789,"invited_by":1099511627776}`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -133,6 +142,65 @@ func TestInvitedByRequest(t *testing.T) {
 	}
 }
 
+func TestInvitedByRequestWithAttributes(t *testing.T) {
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	request := InvitedByRequest{
+		UserID:    12345,
+		InvitedBy: 67890,
+		Timestamp: &timestamp,
+		Campaign:  "spring_sale",
+		Payload:   "ref_67890",
+		Extra:     map[string]any{"reward_granted": true},
+		Origin:    "Test App",
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to marshal InvitedByRequest: %v", err)
+	}
+
+	expected := `{"user_id":12345,"invited_by":67890,"timestamp":"2024-05-01T12:00:00Z","campaign":"spring_sale","payload":"ref_67890","extra":{"reward_granted":true},"origin":"Test App"}`
+	if string(data) != expected {
+		t.Errorf("expected JSON '%s', got '%s'", expected, string(data))
+	}
+}
+
+func TestUserPropertiesRequest(t *testing.T) {
+	request := UserPropertiesRequest{
+		UserID:     12345,
+		Properties: map[string]any{"language": "en", "plan": "premium"},
+		Origin:     "Test App",
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to marshal UserPropertiesRequest: %v", err)
+	}
+
+	expected := `{"user_id":12345,"properties":{"language":"en","plan":"premium"},"origin":"Test App"}`
+	if string(data) != expected {
+		t.Errorf("expected JSON '%s', got '%s'", expected, string(data))
+	}
+}
+
+func TestGroupEventRequest(t *testing.T) {
+	request := GroupEventRequest{
+		GroupID: 42,
+		Updates: []any{map[string]string{"action": "message"}},
+		Origin:  "Test App",
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to marshal GroupEventRequest: %v", err)
+	}
+
+	expected := `{"group_id":42,"updates":[{"action":"message"}],"origin":"Test App"}`
+	if string(data) != expected {
+		t.Errorf("expected JSON '%s', got '%s'", expected, string(data))
+	}
+}
+
 func TestRequestStructTags(t *testing.T) {
 	// Test that the JSON tags are working correctly
 	trackRequest := TrackEventRequest{
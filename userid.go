@@ -0,0 +1,95 @@
+package dashgram
+
+import (
+	"context"
+	"math"
+	"reflect"
+)
+
+// UserID is the set of types user IDs can have with InvitedByT
+type UserID interface {
+	int | int64 | string | uint64
+}
+
+// WithUserIDType declares the type of the user IDs of the application, e.g.
+// WithUserIDType[string]() for UUIDs. InvitedByT then rejects IDs of any
+// other type with a ValidationError, catching mixed ID types early.
+func WithUserIDType[T UserID]() Option {
+	return func(d *Dashgram) {
+		d.userIDType = reflect.TypeOf((*T)(nil)).Elem()
+	}
+}
+
+// InvitedByT tracks a user invitation like InvitedBy, for applications whose
+// user IDs aren't int64. Numeric IDs are sent as JSON numbers and string IDs,
+// such as UUIDs, as JSON strings. It is a function rather than a method
+// because Go methods can't have type parameters.
+func InvitedByT[T UserID](d *Dashgram, userID T, invitedBy T, opts ...CallOption) error {
+	return InvitedByTWithContext(context.Background(), d, userID, invitedBy, opts...)
+}
+
+// InvitedByTWithContext is InvitedByT with a context. With WithOptOut, string
+// IDs are only checked if they are numeric.
+func InvitedByTWithContext[T UserID](ctx context.Context, d *Dashgram, userID T, invitedBy T, opts ...CallOption) error {
+	if err := d.validateUserIDs(userID, invitedBy); err != nil {
+		return err
+	}
+	if d.suppressUserIDs(userID, invitedBy) {
+		return nil
+	}
+
+	requestData, ok := d.runBeforeSend("invited_by", InvitedByRequestT[T]{
+		UserID:    userID,
+		InvitedBy: invitedBy,
+		Origin:    newCallOptions(opts).originOr(d.Origin),
+	})
+	if !ok {
+		return nil
+	}
+
+	if d.useAsync {
+		d.enqueueTask(asyncTask{
+			ctx:      ctx,
+			endpoint: "invited_by",
+			data:     requestData,
+			opts:     opts,
+		})
+		return nil
+	}
+
+	return d.request(ctx, "invited_by", requestData, opts...)
+}
+
+// validateUserIDs checks the IDs passed to InvitedByT against WithUserIDType
+func (d *Dashgram) validateUserIDs(userID, invitedBy any) error {
+	if d.userIDType != nil {
+		if t := reflect.TypeOf(userID); t != d.userIDType {
+			return &ValidationError{Field: "userID", Message: "must be of type " + d.userIDType.String() + ", got " + t.String()}
+		}
+	}
+
+	if userID == "" {
+		return &ValidationError{Field: "userID", Message: "must not be empty"}
+	}
+	if invitedBy == "" {
+		return &ValidationError{Field: "invitedBy", Message: "must not be empty"}
+	}
+	return nil
+}
+
+// suppressUserIDs is suppressUsers for IDs of any UserID type
+func (d *Dashgram) suppressUserIDs(userIDs ...any) bool {
+	if d.optOut == nil {
+		return false
+	}
+
+	ids := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if id, ok := userID.(uint64); ok && id <= math.MaxInt64 {
+			ids = append(ids, int64(id))
+		} else if id, ok := userIDValue(userID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return d.suppressUsers(ids...)
+}
@@ -0,0 +1,147 @@
+package dashgram
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestInvitedByT(t *testing.T) {
+	tests := []struct {
+		name         string
+		call         func(d *Dashgram) error
+		expectedBody string
+	}{
+		{
+			name:         "int",
+			call:         func(d *Dashgram) error { return InvitedByT(d, 1, 2) },
+			expectedBody: `{"user_id":1,"invited_by":2,"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:         "int64",
+			call:         func(d *Dashgram) error { return InvitedByT(d, int64(9007199254740993), int64(2)) },
+			expectedBody: `{"user_id":9007199254740993,"invited_by":2,"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name:         "uint64",
+			call:         func(d *Dashgram) error { return InvitedByT(d, uint64(18446744073709551615), uint64(2)) },
+			expectedBody: `{"user_id":18446744073709551615,"invited_by":2,"origin":"Go + Dashgram SDK"}`,
+		},
+		{
+			name: "string",
+			call: func(d *Dashgram) error {
+				return InvitedByT(d, "6f1c1e8a-1b2c-4d5e-8f90-123456789abc", "0b8e3a52-9c41-4f6e-a1d2-abcdefabcdef", WithCallOrigin("Admin Bot"))
+			},
+			expectedBody: `{"user_id":"6f1c1e8a-1b2c-4d5e-8f90-123456789abc","invited_by":"0b8e3a52-9c41-4f6e-a1d2-abcdefabcdef","origin":"Admin Bot"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()))
+			defer d.Close()
+
+			if err := tt.call(d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			req := helper.LastRequest()
+			if req.URL.Path != "/v1/123/invited_by" {
+				t.Errorf("expected path '/v1/123/invited_by', got '%s'", req.URL.Path)
+			}
+			if string(req.Body) != tt.expectedBody {
+				t.Errorf("expected body %s, got %s", tt.expectedBody, req.Body)
+			}
+		})
+	}
+}
+
+func TestInvitedByT_Validation(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       []Option
+		call          func(d *Dashgram) error
+		expectedField string
+	}{
+		{
+			name:          "type mismatch",
+			options:       []Option{WithUserIDType[string]()},
+			call:          func(d *Dashgram) error { return InvitedByT(d, int64(1), int64(2)) },
+			expectedField: "userID",
+		},
+		{
+			name:          "empty user ID",
+			call:          func(d *Dashgram) error { return InvitedByT(d, "", "b") },
+			expectedField: "userID",
+		},
+		{
+			name:          "empty inviter ID",
+			options:       []Option{WithUserIDType[string]()},
+			call:          func(d *Dashgram) error { return InvitedByT(d, "a", "") },
+			expectedField: "invitedBy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			d := New(123, "test-key", append(tt.options, WithHTTPClient(helper.MockHTTPClient()))...)
+			defer d.Close()
+
+			err := tt.call(d)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if validationErr.Field != tt.expectedField {
+				t.Errorf("expected field %q, got %q", tt.expectedField, validationErr.Field)
+			}
+			if helper.RequestCount != 0 {
+				t.Errorf("expected no request, got %d", helper.RequestCount)
+			}
+		})
+	}
+}
+
+func TestInvitedByT_Async(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithUseAsync(), WithUserIDType[string]())
+
+	if err := InvitedByT(d, "a", "b"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Close()
+
+	req := helper.LastRequest()
+	if req == nil {
+		t.Fatal("expected the invitation to be sent by a worker")
+	}
+	if expected := `{"user_id":"a","invited_by":"b","origin":"Go + Dashgram SDK"}`; string(req.Body) != expected {
+		t.Errorf("expected body %s, got %s", expected, req.Body)
+	}
+}
+
+func TestInvitedByT_OptOut(t *testing.T) {
+	helper := NewTestHelper()
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithOptOut(func(userID int64) bool { return userID == 42 }),
+	)
+	defer d.Close()
+
+	InvitedByT(d, uint64(1), uint64(42))
+	InvitedByT(d, "42", "7")
+
+	if helper.RequestCount != 0 {
+		t.Errorf("expected no request, got %d", helper.RequestCount)
+	}
+	if suppressed := d.Stats().Suppressed; suppressed != 2 {
+		t.Errorf("expected 2 suppressed invitations, got %d", suppressed)
+	}
+}
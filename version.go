@@ -0,0 +1,88 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// apiVersionHeader is the response header holding the API version of the server
+const apiVersionHeader = "X-Dashgram-API-Version"
+
+// The API versions supported by the SDK, from minAPIVersion included to
+// maxAPIVersion excluded
+var (
+	minAPIVersion = apiVersion{1, 0, 0}
+	maxAPIVersion = apiVersion{2, 0, 0}
+)
+
+// ErrUnknownAPIVersion is returned by VersionCheck when the server doesn't
+// report its API version
+var ErrUnknownAPIVersion = errors.New("server did not report its API version")
+
+// VersionCheck asks the server for its API version, read from the
+// X-Dashgram-API-Version header of a credentials check, and reports whether
+// the SDK supports it, i.e. whether it is at least 1.0 and below 2.0. A
+// warning is logged when it isn't, so applications can upgrade the SDK before
+// the server drops the API it uses. It returns ErrUnknownAPIVersion if the
+// server doesn't send the header.
+func (d *Dashgram) VersionCheck(ctx context.Context) (serverVersion string, compatible bool, err error) {
+	resp, err := d.do(ctx, http.MethodPost, "track", nil, TrackEventRequest{Origin: d.Origin, Updates: []any{}}, nil)
+	if err != nil {
+		return "", false, err
+	}
+
+	serverVersion = strings.TrimSpace(resp.Headers.Get(apiVersionHeader))
+	if serverVersion == "" {
+		return "", false, ErrUnknownAPIVersion
+	}
+	version, err := parseAPIVersion(serverVersion)
+	if err != nil {
+		return serverVersion, false, err
+	}
+
+	compatible = !version.less(minAPIVersion) && version.less(maxAPIVersion)
+	if !compatible {
+		d.log(LogLevelWarn, "server API version not supported by the SDK, upgrade the SDK",
+			"server_version", serverVersion, "supported", fmt.Sprintf(">= %s, < %s", minAPIVersion, maxAPIVersion))
+	}
+	return serverVersion, compatible, nil
+}
+
+// apiVersion is a major.minor.patch API version
+type apiVersion [3]int
+
+func (v apiVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// less reports whether v is older than other
+func (v apiVersion) less(other apiVersion) bool {
+	for i := range v {
+		if v[i] != other[i] {
+			return v[i] < other[i]
+		}
+	}
+	return false
+}
+
+// parseAPIVersion parses versions such as "1", "1.4" or "v1.4.2", missing
+// parts are 0
+func parseAPIVersion(s string) (apiVersion, error) {
+	var version apiVersion
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(parts) > len(version) {
+		return version, fmt.Errorf("invalid server API version %q", s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version, fmt.Errorf("invalid server API version %q", s)
+		}
+		version[i] = n
+	}
+	return version, nil
+}
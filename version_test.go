@@ -0,0 +1,91 @@
+package dashgram
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDashgram_VersionCheck(t *testing.T) {
+	tests := []struct {
+		name               string
+		header             string
+		expectedVersion    string
+		expectedCompatible bool
+		expectedErr        error
+		expectedWarning    bool
+	}{
+		{name: "compatible", header: "1.4.2", expectedVersion: "1.4.2", expectedCompatible: true},
+		{name: "compatible major only", header: "v1", expectedVersion: "v1", expectedCompatible: true},
+		{name: "newer major", header: "2.0", expectedVersion: "2.0", expectedWarning: true},
+		{name: "older major", header: "0.9.1", expectedVersion: "0.9.1", expectedWarning: true},
+		{name: "missing", expectedErr: ErrUnknownAPIVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					header := http.Header{}
+					if tt.header != "" {
+						header.Set("X-Dashgram-API-Version", tt.header)
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     header,
+						Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+					}, nil
+				},
+			}
+			logger := &recordingLogger{}
+
+			d := New(123, "test-key", WithHTTPClient(client), WithLogger(logger))
+			defer d.Close()
+
+			version, compatible, err := d.VersionCheck(context.Background())
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if version != tt.expectedVersion {
+				t.Errorf("expected version %q, got %q", tt.expectedVersion, version)
+			}
+			if compatible != tt.expectedCompatible {
+				t.Errorf("expected compatible %v, got %v", tt.expectedCompatible, compatible)
+			}
+			if warnings := logger.levels()[LogLevelWarn]; (warnings > 0) != tt.expectedWarning {
+				t.Errorf("expected warning %v, got %d warnings", tt.expectedWarning, warnings)
+			}
+		})
+	}
+}
+
+func TestParseAPIVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected apiVersion
+		wantErr  bool
+	}{
+		{version: "1", expected: apiVersion{1, 0, 0}},
+		{version: "v1.4", expected: apiVersion{1, 4, 0}},
+		{version: "1.4.2", expected: apiVersion{1, 4, 2}},
+		{version: "1.4.2.1", wantErr: true},
+		{version: "1.x", wantErr: true},
+		{version: "1.-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			version, err := parseAPIVersion(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && version != tt.expected {
+				t.Errorf("expected version %v, got %v", tt.expected, version)
+			}
+		})
+	}
+}
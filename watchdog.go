@@ -0,0 +1,74 @@
+package dashgram
+
+import "time"
+
+// minWatchdogTick is the shortest period the watchdog checks the workers at
+const minWatchdogTick = time.Millisecond
+
+// WithWorkerWatchdog watches the async workers for stalls, e.g. a worker
+// deadlocked in a misbehaving HTTP client. The workers are considered stalled
+// when no task completes within interval while tasks are queued or in flight;
+// fn, if not nil, is then called once with the number of pending tasks, and
+// WorkerStalled reports true until a task completes again.
+func WithWorkerWatchdog(interval time.Duration, fn func(pending int)) Option {
+	return func(d *Dashgram) {
+		d.watchdogInterval = interval
+		d.watchdogCallback = fn
+	}
+}
+
+// WorkerStalled reports whether the watchdog set by WithWorkerWatchdog detected
+// that the async workers stopped making progress
+func (d *Dashgram) WorkerStalled() bool {
+	return d.workerStalled.Load()
+}
+
+// completedTasks returns the number of async tasks whose request finished
+func (d *Dashgram) completedTasks() int64 {
+	return d.stats.delivered.Load() + d.stats.failed.Load()
+}
+
+// startWatchdog starts the goroutine checking that the workers make progress
+func (d *Dashgram) startWatchdog() {
+	if d.watchdogInterval <= 0 {
+		return
+	}
+
+	d.workerWg.Add(1)
+	go func() {
+		defer d.workerWg.Done()
+
+		// Check twice per interval, so a stall is detected within 1.5 intervals
+		tick := d.watchdogInterval / 2
+		if tick < minWatchdogTick {
+			tick = minWatchdogTick
+		}
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+
+		completed := d.completedTasks()
+		lastProgress := time.Now()
+		for {
+			select {
+			case now := <-ticker.C:
+				pending := d.QueueLength() + d.InFlight()
+				if current := d.completedTasks(); current != completed || pending == 0 {
+					// The workers made progress or are idle
+					completed = current
+					lastProgress = now
+					d.workerStalled.Store(false)
+					continue
+				}
+
+				if now.Sub(lastProgress) >= d.watchdogInterval && !d.workerStalled.Swap(true) {
+					d.log(LogLevelError, "async workers stalled", "pending", pending, "since", lastProgress)
+					if d.watchdogCallback != nil {
+						d.watchdogCallback(pending)
+					}
+				}
+			case <-d.workerCtx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,92 @@
+package dashgram
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WithWorkerWatchdog(t *testing.T) {
+	release := make(chan struct{})
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			<-release
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"success","details":"ok"}`)),
+			}, nil
+		},
+	}
+
+	stalled := make(chan int, 10)
+	d := New(123, "test-key",
+		WithHTTPClient(mockClient),
+		WithWorkerWatchdog(20*time.Millisecond, func(pending int) { stalled <- pending }),
+	)
+	defer d.Close()
+
+	if d.WorkerStalled() {
+		t.Fatal("expected an idle client not to be stalled")
+	}
+
+	// The first request hangs, the second one waits in the queue
+	d.TrackEventAsync(map[string]any{"action": "stuck"})
+	d.TrackEventAsync(map[string]any{"action": "queued"})
+
+	select {
+	case pending := <-stalled:
+		if pending != 2 {
+			t.Errorf("expected 2 pending tasks, got %d", pending)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the watchdog to flag the stall")
+	}
+	if !d.WorkerStalled() {
+		t.Error("expected WorkerStalled to report true")
+	}
+
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && d.WorkerStalled() {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if d.WorkerStalled() {
+		t.Error("expected WorkerStalled to report false once tasks complete")
+	}
+	if len(stalled) != 0 {
+		t.Errorf("expected the callback to be called once, got %d more calls", len(stalled))
+	}
+}
+
+func TestDashgram_WithWorkerWatchdogIdle(t *testing.T) {
+	helper := NewTestHelper()
+	helper.AddResponse(http.StatusOK, `{"status":"success","details":"ok"}`)
+
+	called := make(chan int, 1)
+	d := New(123, "test-key",
+		WithHTTPClient(helper.MockHTTPClient()),
+		WithWorkerWatchdog(10*time.Millisecond, func(pending int) { called <- pending }),
+	)
+	defer d.Close()
+
+	d.TrackEventAsync(map[string]any{"action": "click"})
+	time.Sleep(50 * time.Millisecond)
+
+	if d.WorkerStalled() {
+		t.Error("expected a client without pending tasks not to be stalled")
+	}
+	select {
+	case pending := <-called:
+		t.Errorf("expected no stall, got one with %d pending tasks", pending)
+	default:
+	}
+}
+
+func TestDashgram_WithWorkerWatchdogShortInterval(t *testing.T) {
+	// A tick of half an interval under 2ns would be 0 and make time.NewTicker panic
+	d := New(123, "test-key", WithWorkerWatchdog(time.Nanosecond, nil))
+	d.Close()
+}
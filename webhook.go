@@ -0,0 +1,62 @@
+package dashgram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// defaultWebhookMaxBodySize is the largest webhook body tracked by WebhookMiddleware
+const defaultWebhookMaxBodySize = 1 << 20 // 1 MiB
+
+// WithWebhookMaxBodySize sets the largest request body WebhookMiddleware will
+// track. Larger updates are passed through to the handler untracked.
+func WithWebhookMaxBodySize(size int64) Option {
+	return func(d *Dashgram) {
+		d.webhookMaxBodySize = size
+	}
+}
+
+// WebhookMiddleware tracks every Telegram update received by a webhook handler.
+// The raw update JSON is enqueued for asynchronous tracking and the request
+// body is restored before next is called. Tracking never blocks or fails the
+// request: updates are skipped when the queue is full, the body is too large
+// or it is not valid JSON.
+func (d *Dashgram) WebhookMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Body == http.NoBody {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, d.webhookMaxBodySize+1))
+		if err != nil || int64(len(body)) > d.webhookMaxBodySize {
+			// Hand over what was read along with the unread remainder
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if json.Valid(body) && !d.skipEvent(r.Context(), json.RawMessage(body), nil) {
+			if event, ok := d.runBeforeSend("track", json.RawMessage(body)); ok {
+				d.tryEnqueueTask(asyncTask{
+					ctx:      context.Background(),
+					endpoint: "track",
+					data: TrackEventRequest{
+						Origin:  d.Origin,
+						Updates: []any{d.prepareUpdate(event)},
+					},
+				})
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
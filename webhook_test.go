@@ -0,0 +1,116 @@
+package dashgram
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashgram_WebhookMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		maxBodySize   int64
+		expectTracked bool
+	}{
+		{
+			name:          "tracks update and restores body",
+			body:          `{"update_id":1,"message":{"text":"hello"}}`,
+			maxBodySize:   defaultWebhookMaxBodySize,
+			expectTracked: true,
+		},
+		{
+			name:          "passes oversized body untracked",
+			body:          `{"update_id":2,"message":{"text":"a long message"}}`,
+			maxBodySize:   10,
+			expectTracked: false,
+		},
+		{
+			name:          "passes invalid JSON untracked",
+			body:          `not json`,
+			maxBodySize:   defaultWebhookMaxBodySize,
+			expectTracked: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper()
+			helper.AddResponse(200, `{"status":"success","details":"ok"}`)
+
+			d := New(123, "test-key", WithHTTPClient(helper.MockHTTPClient()), WithWebhookMaxBodySize(tt.maxBodySize))
+			defer d.Close()
+
+			var handlerBody string
+			handler := d.WebhookMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("failed to read body in handler: %v", err)
+				}
+				handlerBody = string(body)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(tt.body)))
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected status 200, got %d", rec.Code)
+			}
+			if handlerBody != tt.body {
+				t.Errorf("expected handler body '%s', got '%s'", tt.body, handlerBody)
+			}
+
+			tracked := helper.WaitForRequests(1, 200*time.Millisecond)
+			if tracked != tt.expectTracked {
+				t.Fatalf("expected tracked %v, got %v", tt.expectTracked, tracked)
+			}
+			if !tt.expectTracked {
+				return
+			}
+
+			var request struct {
+				Updates []json.RawMessage `json:"updates"`
+			}
+			trackedBody := helper.LastRequest().Body
+			if err := json.Unmarshal(trackedBody, &request); err != nil {
+				t.Fatalf("failed to parse tracked body: %v", err)
+			}
+			if len(request.Updates) != 1 || string(request.Updates[0]) != tt.body {
+				t.Errorf("expected tracked update '%s', got %s", tt.body, trackedBody)
+			}
+		})
+	}
+}
+
+func TestDashgram_WebhookMiddlewareFailOpen(t *testing.T) {
+	d := New(123, "test-key")
+
+	// A closed client can't track anything
+	d.Close()
+
+	called := false
+	handler := d.WebhookMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"update_id":1}`)))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("middleware blocked on a closed client")
+	}
+
+	if !called {
+		t.Errorf("expected next handler to be called")
+	}
+}
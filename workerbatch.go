@@ -0,0 +1,133 @@
+package dashgram
+
+import (
+	"context"
+	"time"
+)
+
+// defaultWorkerFlushInterval is how long a batching worker waits for more tasks
+const defaultWorkerFlushInterval = 100 * time.Millisecond
+
+// WithWorkerBatchSize makes each async worker collect up to n tasks before
+// sending them, so consecutive track tasks can be merged into a single
+// request. Tasks of other endpoints, track tasks with per-call headers or
+// timeouts, and track tasks enqueued with a context other than
+// context.Background are still sent one by one, so their context values,
+// deadline and cancellation apply. Tasks are sent in the order they were
+// enqueued. Batching is off by default.
+func WithWorkerBatchSize(n int) Option {
+	return func(d *Dashgram) {
+		if n > 0 {
+			d.workerBatchSize = n
+		}
+	}
+}
+
+// WithWorkerBatchFlushInterval sets how long a batching worker waits for more
+// tasks before sending a partial batch, 100ms by default
+func WithWorkerBatchFlushInterval(interval time.Duration) Option {
+	return func(d *Dashgram) {
+		if interval > 0 {
+			d.workerFlushInterval = interval
+		}
+	}
+}
+
+// runBatchWorker consumes the pool's tasks in batches until the client is closed
+func (d *Dashgram) runBatchWorker(pool *workerPool) {
+	for {
+		task, ok := d.nextTask(pool, nil)
+		if !ok {
+			return
+		}
+
+		// Collect more tasks until the batch is full or the flush interval elapses
+		batch := []asyncTask{task}
+		timer := time.NewTimer(d.workerFlushInterval)
+		for len(batch) < d.workerBatchSize {
+			task, ok := d.nextTask(pool, timer.C)
+			if !ok {
+				break
+			}
+			batch = append(batch, task)
+		}
+		timer.Stop()
+
+		d.checkBackpressure()
+		d.processBatch(pool, batch)
+	}
+}
+
+// processBatch merges consecutive track tasks sharing an origin and sends the
+// other tasks one by one, in the order they were enqueued
+func (d *Dashgram) processBatch(pool *workerPool, batch []asyncTask) {
+	var origin string
+	var group []asyncTask
+	flush := func() {
+		if len(group) > 0 {
+			d.processTrackGroup(pool, origin, group)
+			group = nil
+		}
+	}
+
+	for _, task := range batch {
+		requestData, ok := mergeableTrackRequest(task)
+		if !ok {
+			// Earlier tasks are sent first, so the task can't overtake them
+			flush()
+			d.processTask(pool, task)
+			continue
+		}
+
+		if requestData.Origin != origin {
+			flush()
+		}
+		origin = requestData.Origin
+		group = append(group, task)
+	}
+	flush()
+}
+
+// processTrackGroup sends track tasks sharing an origin as a single request
+func (d *Dashgram) processTrackGroup(pool *workerPool, origin string, tasks []asyncTask) {
+	if len(tasks) == 1 {
+		d.processTask(pool, tasks[0])
+		return
+	}
+
+	var updates []any
+	for _, task := range tasks {
+		updates = append(updates, task.data.(TrackEventRequest).Updates...)
+	}
+
+	// Merged tasks carry context.Background, so it stands in for their contexts
+	d.stats.inFlight.Add(int64(len(tasks)))
+	ctx, cancel := d.taskContext(context.Background())
+	err := d.request(ctx, "track", TrackEventRequest{Origin: origin, Updates: updates})
+	cancel()
+	d.stats.inFlight.Add(-int64(len(tasks)))
+
+	for _, task := range tasks {
+		pool.processed.Add(1)
+		d.handleResult(task, err)
+	}
+}
+
+// mergeableTrackRequest returns the request of a track task that can be merged with others
+func mergeableTrackRequest(task asyncTask) (TrackEventRequest, bool) {
+	// Values, deadlines and cancellation of other contexts only apply per request
+	if task.endpoint != "track" || task.ctx != context.Background() {
+		return TrackEventRequest{}, false
+	}
+
+	requestData, ok := task.data.(TrackEventRequest)
+	if !ok {
+		return TrackEventRequest{}, false
+	}
+
+	call := newCallOptions(task.opts)
+	if len(call.headers) > 0 || call.timeout > 0 || call.projectID != 0 {
+		return TrackEventRequest{}, false
+	}
+	return requestData, true
+}